@@ -9,12 +9,16 @@ import (
 	"github.com/julien-sobczak/the-notewriter/internal/core"
 	"github.com/julien-sobczak/the-notewriter/internal/reference"
 	"github.com/julien-sobczak/the-notewriter/internal/reference/googlebooks"
+	"github.com/julien-sobczak/the-notewriter/internal/reference/tmdb"
 	"github.com/julien-sobczak/the-notewriter/internal/reference/wikipedia"
 	"github.com/julien-sobczak/the-notewriter/internal/reference/zotero"
 	"github.com/spf13/cobra"
 )
 
+var newNoCache bool
+
 func init() {
+	newCmd.Flags().BoolVarP(&newNoCache, "no-cache", "", false, "ignore cached search results")
 	rootCmd.AddCommand(newCmd)
 }
 
@@ -37,7 +41,7 @@ var newCmd = &cobra.Command{
 		_, selectedConfigReference := ChooseCategory(configReference)
 
 		// Instantiate the manager
-		var manager = createManager(selectedConfigReference)
+		var manager = createCachedManager(selectedConfigReference)
 
 		if ready, _ := manager.Ready(); !ready {
 			WaitManagerIsReady(manager)
@@ -86,6 +90,13 @@ var newCmd = &cobra.Command{
 	},
 }
 
+func createCachedManager(category *core.ConfigReference) reference.Manager {
+	cacheDir := filepath.Join(core.CurrentConfig().RootDirectory, ".nt", "cache", "references")
+	manager := reference.NewCachedManager(createManager(category), category.Manager, cacheDir, category.CacheDuration())
+	manager.Refresh = newNoCache
+	return manager
+}
+
 func createManager(category *core.ConfigReference) reference.Manager {
 	switch category.Manager {
 	case "zotero":
@@ -99,6 +110,15 @@ func createManager(category *core.ConfigReference) reference.Manager {
 		return wikipedia.NewManager()
 	case "google-books":
 		return googlebooks.NewManager()
+	case "tmdb":
+		apiKey := category.APIKey
+		if apiKey == "" {
+			apiKey = os.Getenv("TMDB_API_KEY")
+		}
+		if apiKey == "" {
+			log.Fatal("Missing API key for manager \"tmdb\" (use apiKey in .nt/config or $TMDB_API_KEY)")
+		}
+		return tmdb.NewManager(apiKey)
 	}
 	log.Fatalf("Unknown reference manager %q", category.Manager)
 	return nil
@@ -1,14 +1,27 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/julien-sobczak/the-notewriter/internal/core"
 	"github.com/spf13/cobra"
 )
 
+var addDryRun bool
+var addKeepGoing bool
+var addPatch bool
+var addNoVerify bool
+
 func init() {
+	addCmd.Flags().BoolVarP(&addDryRun, "dry-run", "n", false, "Only list what would be staged")
+	addCmd.Flags().BoolVarP(&addKeepGoing, "keep-going", "k", false, "Continue with other files when a file cannot be parsed")
+	addCmd.Flags().BoolVarP(&addPatch, "patch", "p", false, "Interactively choose the changed notes to stage")
+	addCmd.Flags().BoolVarP(&addNoVerify, "no-verify", "", false, "Stage files without checking linter rules")
 	rootCmd.AddCommand(addCmd)
 }
 
@@ -23,10 +36,88 @@ var addCmd = &cobra.Command{
 
 		CheckConfig()
 
-		err := core.CurrentRepository().Add(args...)
+		core.CurrentConfig().DryRun = addDryRun
+		core.CurrentConfig().KeepGoing = addKeepGoing
+		core.CurrentConfig().NoVerify = addNoVerify
+
+		// Stop on Ctrl+C without staging anything
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		progress := make(chan core.MediaProgress)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for event := range progress {
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "Generated blobs %d/%d: %s\n", event.Completed, event.Total, event.RelativePath)
+				}
+			}
+		}()
+		var summary *core.AddSummary
+		var err error
+		if addPatch {
+			summary, err = core.CurrentRepository().AddPatch(ctx, promptNote(), args...)
+		} else {
+			summary, err = core.CurrentRepository().AddWithContext(ctx, progress, args...)
+		}
+		close(progress)
+		<-done
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+
+		if addDryRun {
+			if summary.Count() == 0 {
+				fmt.Println("Nothing would be staged")
+				return
+			}
+			for _, path := range summary.Added {
+				fmt.Printf("Would add:\t%s\n", path)
+			}
+			for _, path := range summary.Modified {
+				fmt.Printf("Would modify:\t%s\n", path)
+			}
+			for _, path := range summary.Deleted {
+				fmt.Printf("Would delete:\t%s\n", path)
+			}
+		}
+
+		if len(summary.FileErrors) > 0 {
+			fmt.Println(summary.FileErrors)
+			os.Exit(1)
+		}
 	},
 }
+
+// promptNote asks on the standard input if every changed note must be staged.
+func promptNote() core.NoteSelector {
+	reader := bufio.NewReader(os.Stdin)
+	quit := false
+	return func(note *core.Note) (bool, error) {
+		if quit {
+			return false, nil
+		}
+		for {
+			fmt.Printf("Stage %s note %q (%s:%d) [y,n,q,?]? ", note.State(), note.Title, note.RelativePath, note.Line)
+			answer, err := reader.ReadString('\n')
+			if err != nil {
+				return false, err
+			}
+			switch strings.TrimSpace(answer) {
+			case "y":
+				return true, nil
+			case "n":
+				return false, nil
+			case "q":
+				quit = true
+				return false, nil
+			default:
+				fmt.Println("y - stage this note")
+				fmt.Println("n - do not stage this note")
+				fmt.Println("q - quit; do not stage this note or any of the remaining ones")
+				fmt.Println("? - print help")
+			}
+		}
+	}
+}
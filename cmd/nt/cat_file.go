@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
@@ -28,8 +29,12 @@ var catFileCmd = &cobra.Command{
 
 		arg := args[0]
 
-		if isOID(arg) {
-			oid := arg
+		oid, ok, err := resolveOID(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if ok {
 
 			// OIDs can represent a pack file, an object inside a pack file, or a blob.
 
@@ -205,7 +210,12 @@ func dumpNote(note *core.Note) {
 
 // isOID checks if the value looks like an OID.
 func isOID(s string) bool {
-	if len(s) != 40 {
+	return len(s) == 40 && isOIDPrefix(s)
+}
+
+// isOIDPrefix checks if the value looks like an OID, possibly abbreviated.
+func isOIDPrefix(s string) bool {
+	if len(s) < core.MinOIDPrefixLength || len(s) > 40 {
 		return false
 	}
 	for _, r := range s {
@@ -215,3 +225,23 @@ func isOID(s string) bool {
 	}
 	return true
 }
+
+// resolveOID returns the full OID for an OID or a unique OID prefix.
+// ok is false when the value must be interpreted differently (ex: a wikilink).
+func resolveOID(s string) (oid string, ok bool, err error) {
+	if !isOIDPrefix(s) {
+		return "", false, nil
+	}
+	oid, err = core.CurrentDB().ResolveOID(s)
+	if errors.Is(err, core.ErrUnknownOID) {
+		if isOID(s) {
+			// Let the caller report the missing object
+			return s, true, nil
+		}
+		return "", false, nil
+	}
+	if err != nil {
+		return "", true, err
+	}
+	return oid, true, nil
+}
@@ -9,9 +9,13 @@ import (
 )
 
 var commitMessage string
+var commitIgnoreHookErrors bool
+var commitAuthor string
 
 func init() {
 	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "commit message")
+	commitCmd.Flags().BoolVarP(&commitIgnoreHookErrors, "ignore-hook-errors", "", false, "Commit even when hooks fail")
+	commitCmd.Flags().StringVarP(&commitAuthor, "author", "", "", "override the author name defined in .nt/config")
 	rootCmd.AddCommand(commitCmd)
 }
 
@@ -20,7 +24,11 @@ var commitCmd = &cobra.Command{
 	Short: "Commit",
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckConfig()
-		err := core.CurrentDB().Commit(commitMessage)
+		core.CurrentConfig().IgnoreHookErrors = commitIgnoreHookErrors
+		if commitAuthor != "" {
+			core.CurrentConfig().ConfigFile.Core.Author = commitAuthor
+		}
+		err := core.CurrentRepository().CommitWithMessage(commitMessage)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
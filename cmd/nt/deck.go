@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var deckStatsJSON bool
+
+func init() {
+	deckStatsCmd.Flags().BoolVarP(&deckStatsJSON, "json", "", false, "Output in JSON")
+	deckCmd.AddCommand(deckStatsCmd)
+	rootCmd.AddCommand(deckCmd)
+}
+
+var deckCmd = &cobra.Command{
+	Use:   "deck",
+	Short: "Manage decks",
+	Long:  `Inspect decks of flashcards declared in .nt/config.`,
+}
+
+var deckStatsCmd = &cobra.Command{
+	Use:   "stats <name>",
+	Short: "Show review statistics of a deck",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		stats, err := core.CurrentRepository().DeckStats(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if deckStatsJSON {
+			printJSON(stats)
+			return
+		}
+
+		fmt.Printf("Deck %s\n", stats.Name)
+		fmt.Printf("Flashcards: %d (%d new, %d young, %d mature)\n", stats.Flashcards, stats.New, stats.Young, stats.Mature)
+		fmt.Printf("Due today:  %d\n", stats.DueToday)
+		fmt.Printf("Ease:       %.2f on average\n", stats.AverageEaseFactor)
+		fmt.Printf("Interval:   %.1f days on average\n", stats.AverageIntervalInDays)
+		fmt.Println("")
+		fmt.Printf("Last %d days:\n", stats.WindowInDays)
+		fmt.Printf("Reviews:    %d\n", stats.Reviews)
+		fmt.Printf("Retention:  %.0f%%\n", stats.Retention*100)
+		fmt.Printf("New/day:    %.1f (configured: %d)\n", stats.NewPerDay, stats.NewFlashcardsPerDay)
+	},
+}
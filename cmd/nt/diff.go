@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -12,24 +13,84 @@ import (
 
 var cached bool
 var staged bool
+var diffRemoteIndexCache bool
+var diffJSON bool
+var diffStat bool
 
 func init() {
 	diffCmd.Flags().BoolVarP(&cached, "cached", "", false, "Show staged changes")
 	diffCmd.Flags().BoolVarP(&staged, "staged", "", false, "Show staged changes")
+	diffCmd.Flags().BoolVarP(&diffJSON, "json", "", false, "Output changes of notes in JSON")
+	diffCmd.Flags().BoolVarP(&diffStat, "stat", "", false, "Show a summary of changed objects per file")
+	diffCmd.Flags().BoolVarP(&diffRemoteIndexCache, "remote-index-cache", "", false, "Download the origin index only when changed since the last operation")
 	rootCmd.AddCommand(diffCmd)
 }
 
 var diffCmd = &cobra.Command{
-	Use:   "diff",
+	Use:   "diff [origin [path]...]",
 	Short: "Show changes",
-	Long:  `Show changes between commit and working tree.`,
+	Long: `Show changes between commit and working tree,
+or between the origin and local commits to review what a push would change.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) > 0 {
-			fmt.Println("Too many arguments. No argument is supported.")
+		CheckConfig()
+		core.CurrentConfig().RemoteIndexCache = diffRemoteIndexCache
+
+		if diffJSON && diffStat {
+			fmt.Println("--json cannot be used with --stat")
 			os.Exit(1)
 		}
 
+		if len(args) > 0 {
+			if args[0] != "origin" {
+				fmt.Printf("Unsupported revision %q. Only origin is supported.\n", args[0])
+				os.Exit(1)
+			}
+			if diffJSON {
+				diffs, err := core.CurrentRepository().DiffRemoteObjects(args[1:])
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				printDiffJSON(diffs)
+				return
+			}
+			if diffStat {
+				diffs, err := core.CurrentRepository().DiffRemoteObjects(args[1:])
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				fmt.Print(core.FormatDiffStats(core.NewDiffStats(diffs)))
+				return
+			}
+			diff, err := core.CurrentRepository().DiffRemote(args[1:])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			printDiff(diff)
+			return
+		}
+
 		stagedOrCached := staged || cached
+		if diffJSON {
+			diffs, err := core.CurrentRepository().DiffObjects(stagedOrCached)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			printDiffJSON(diffs)
+			return
+		}
+		if diffStat {
+			diffs, err := core.CurrentRepository().DiffObjects(stagedOrCached)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Print(core.FormatDiffStats(core.NewDiffStats(diffs)))
+			return
+		}
 		diff, err := core.CurrentRepository().Diff(stagedOrCached)
 		if err != nil {
 			fmt.Println(err)
@@ -39,6 +100,19 @@ var diffCmd = &cobra.Command{
 	},
 }
 
+func printDiffJSON(diffs []*core.ObjectDiff) {
+	jsonDiffs := []json.RawMessage{}
+	for _, diff := range diffs {
+		jsonDiff, err := diff.ToJSON()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		jsonDiffs = append(jsonDiffs, jsonDiff)
+	}
+	printJSON(jsonDiffs)
+}
+
 func printDiff(diff string) {
 	for _, line := range strings.Split(diff, "\n") {
 		if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
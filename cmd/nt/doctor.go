@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var doctorJSON bool
+
+func init() {
+	doctorCmd.Flags().BoolVarP(&doctorJSON, "json", "", false, "Output in JSON")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common repository problems",
+	Long:  `Check the configuration, the database, the index, the remote, and the medias, and suggest fixes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Do not call CheckConfig() as an invalid configuration is reported like other problems
+		diagnostics := core.CurrentRepository().Diagnose()
+
+		failed := false
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Status == core.DiagnosticFail {
+				failed = true
+			}
+		}
+
+		if doctorJSON {
+			printJSON(diagnostics)
+		} else {
+			for _, diagnostic := range diagnostics {
+				fmt.Printf("[%s] %s: %s\n", diagnostic.Status, diagnostic.Name, diagnostic.Message)
+				if diagnostic.Hint != "" {
+					fmt.Printf("       %s\n", diagnostic.Hint)
+				}
+			}
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+	"github.com/spf13/cobra"
+)
+
+var exportJSONKinds []string
+var exportJSONSince string
+var exportJSONMediaBaseURL string
+
+func init() {
+	exportJSONCmd.Flags().StringSliceVarP(&exportJSONKinds, "kinds", "", nil, "Export only notes of these kinds (ex: reference,quote)")
+	exportJSONCmd.Flags().StringVarP(&exportJSONSince, "since", "", "", "Export only notes updated since this date (ex: 2023-01-01) or since the last export (last)")
+	exportJSONCmd.Flags().StringVarP(&exportJSONMediaBaseURL, "media-base-url", "", "", "Replace links to medias by URLs under this base URL (ex: https://example.com/objects)")
+	rootCmd.AddCommand(exportJSONCmd)
+}
+
+var exportJSONCmd = &cobra.Command{
+	Use:   "export-json [path]...",
+	Short: "Export notes in JSON",
+	Long:  `Export notes as newline-delimited JSON (ex: to feed an external search engine).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		var kinds []core.NoteKind
+		for _, kind := range exportJSONKinds {
+			kinds = append(kinds, core.NoteKind(kind))
+		}
+
+		// Save the date before reading notes to not miss concurrent changes next time
+		exportTime := clock.Now()
+
+		var since time.Time
+		if exportJSONSince == "last" {
+			var err error
+			since, err = core.CurrentRepository().LastExportTime()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		} else if exportJSONSince != "" {
+			var err error
+			since, err = parseSince(exportJSONSince)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		err := core.CurrentRepository().ExportJSON(out, args, kinds, since, exportJSONMediaBaseURL)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := out.Flush(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := core.CurrentRepository().SaveExportTime(exportTime); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+// parseSince parses a date (ex: 2023-01-01) or a timestamp (ex: 2023-01-01T12:00:00Z).
+func parseSince(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD or RFC 3339)", value)
+	}
+	return date, nil
+}
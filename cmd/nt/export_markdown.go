@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(exportMarkdownCmd)
+}
+
+var exportMarkdownCmd = &cobra.Command{
+	Use:   "export-markdown [path]...",
+	Short: "Export notes as a single Markdown document",
+	Long:  `Combine notes into a single Markdown document with a table of contents (ex: to share a handout).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		out := bufio.NewWriter(os.Stdout)
+		if err := core.CurrentRepository().ExportCombinedMarkdown(args, out); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := out.Flush(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
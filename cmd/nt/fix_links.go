@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var fixLinksDryRun bool
+
+func init() {
+	fixLinksCmd.Flags().BoolVarP(&fixLinksDryRun, "dry-run", "n", false, "Report the wikilinks to fix without changing them")
+	rootCmd.AddCommand(fixLinksCmd)
+}
+
+var fixLinksCmd = &cobra.Command{
+	Use:   "fix-links [--] [<pathspec>...]",
+	Short: "Fix wikilinks",
+	Long:  `Remove extensions in wikilinks and use the casing of the target files.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		fixes, err := core.CurrentRepository().FixWikilinks(fixLinksDryRun, args...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, fix := range fixes {
+			fmt.Println(fix)
+		}
+		if fixLinksDryRun {
+			fmt.Printf("%d wikilink(s) would be fixed\n", len(fixes))
+		} else {
+			fmt.Printf("%d wikilink(s) fixed\n", len(fixes))
+		}
+	},
+}
@@ -8,7 +8,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var gcAggressive bool
+var gcDryRun bool
+
 func init() {
+	gcCmd.Flags().BoolVarP(&gcAggressive, "aggressive", "", false, "Remove all unreachable pack files/blobs and recompress pack files")
+	gcCmd.Flags().BoolVarP(&gcDryRun, "dry-run", "n", false, "Only list what would be removed (requires --aggressive)")
 	rootCmd.AddCommand(gcCmd)
 }
 
@@ -19,14 +24,53 @@ var gcCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckConfig()
 		if core.CurrentDB().Origin() == nil {
-			fmt.Println("There is no remote currently configured.")
+			fmt.Println("There is no remote currently configured.")
 			fmt.Println("Please specify one in .nt/config")
 			os.Exit(1)
 		}
-		err := core.CurrentDB().GC()
+
+		if gcDryRun && !gcAggressive {
+			fmt.Println("--dry-run is only supported with --aggressive")
+			os.Exit(1)
+		}
+
+		if !gcAggressive {
+			err := core.CurrentDB().GC()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		core.CurrentConfig().DryRun = gcDryRun
+		result, err := core.CurrentDB().GCAggressive()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+
+		action := "Removed"
+		if gcDryRun {
+			action = "Would remove"
+		}
+		for _, oid := range result.PackFiles {
+			fmt.Printf("%s pack file %s\n", action, oid)
+		}
+		for _, oid := range result.Blobs {
+			fmt.Printf("%s blob %s\n", action, oid)
+		}
+		action = "Recompressed"
+		if gcDryRun {
+			action = "Would recompress"
+		}
+		for _, oid := range result.RecompressedPackFiles {
+			fmt.Printf("%s pack file %s\n", action, oid)
+		}
+		if gcDryRun {
+			fmt.Printf("%d bytes would be reclaimed\n", result.ReclaimedBytes)
+		} else {
+			fmt.Printf("%d bytes reclaimed\n", result.ReclaimedBytes)
+		}
 	},
 }
@@ -17,8 +17,8 @@ var goCmd = &cobra.Command{
 	Use:   "go",
 	Short: "Redirect to a Go link",
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) > 1 {
-			fmt.Println("Too many arguments. You can only have one which must be a go link name")
+		if len(args) != 1 {
+			fmt.Println("A single argument is required which must be a go link name")
 			os.Exit(1)
 		}
 
@@ -26,7 +26,11 @@ var goCmd = &cobra.Command{
 
 		link, err := core.CurrentRepository().FindLinkByGoName(goName)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "No Go link %q found", goName)
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if link == nil {
+			fmt.Fprintf(os.Stderr, "No Go link %q found\n", goName)
 			os.Exit(1)
 		}
 
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var golinksResolve string
+var golinksServeAddr string
+
+func init() {
+	golinksCmd.Flags().StringVarP(&golinksResolve, "resolve", "", "", "Print only the URL of the given Go name")
+	golinksServeCmd.Flags().StringVarP(&golinksServeAddr, "addr", "", ":8080", "TCP address to listen on")
+	golinksCmd.AddCommand(golinksServeCmd)
+	rootCmd.AddCommand(golinksCmd)
+}
+
+var golinksCmd = &cobra.Command{
+	Use:   "golinks [filter]",
+	Short: "List Go links",
+	Long:  `List Go links whose name, URL, or text contains the optional filter.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		if golinksResolve != "" {
+			link, err := core.CurrentRepository().ResolveGoLink(golinksResolve)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if link == nil {
+				fmt.Fprintf(os.Stderr, "No Go link %q found\n", golinksResolve)
+				os.Exit(1)
+			}
+			fmt.Println(link.URL)
+			return
+		}
+
+		links, err := core.CurrentRepository().FindGoLinks(strings.Join(args, " "))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, link := range links {
+			source := link.RelativePath
+			if note := link.GetNote(); note != nil {
+				source = fmt.Sprintf("%s: %s", note.RelativePath, note.Title)
+			}
+			fmt.Printf("go/%s\t%s\t(%s)\n", link.GoName, link.URL, source)
+		}
+
+		duplicates, err := core.CurrentRepository().FindDuplicateGoNames()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		goNames := make([]string, 0, len(duplicates))
+		for goName := range duplicates {
+			goNames = append(goNames, goName)
+		}
+		sort.Strings(goNames)
+		for _, goName := range goNames {
+			fmt.Fprintf(os.Stderr, "Duplicate Go name %q declared in:\n", goName)
+			for _, note := range duplicates[goName] {
+				fmt.Fprintf(os.Stderr, "  %s: %s\n", note.RelativePath, note.Title)
+			}
+		}
+	},
+}
+
+var golinksServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve Go links over HTTP",
+	Long:  `Redirect GET /<goname> to the URL of the Go link.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		if !quiet {
+			fmt.Printf("Serving Go links on %s\n", golinksServeAddr)
+		}
+		err := http.ListenAndServe(golinksServeAddr, core.CurrentRepository().GoLinksHandler())
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/julien-sobczak/the-notewriter/internal/core"
 	"github.com/spf13/cobra"
@@ -11,9 +12,25 @@ import (
 )
 
 var lintRules string
+var lintKeepGoing bool
+var lintFix bool
+var lintStdin bool
+var lintPath string
+var lintSince string
+var lintFormat string
+var lintFailOn string
+var lintMaxWarnings int
 
 func init() {
 	lintCmd.Flags().StringVarP(&lintRules, "rules", "r", "all", "comma-separated list of rule names used to filter")
+	lintCmd.Flags().BoolVarP(&lintKeepGoing, "keep-going", "k", false, "Continue with other files when a file cannot be parsed")
+	lintCmd.Flags().BoolVarP(&lintFix, "fix", "", false, "propose fixes for violations (ex: unique slugs)")
+	lintCmd.Flags().BoolVarP(&lintStdin, "stdin", "", false, "Lint the content read from stdin instead of files (requires --path)")
+	lintCmd.Flags().StringVarP(&lintPath, "path", "", "", "Relative path of the content read from stdin")
+	lintCmd.Flags().StringVarP(&lintSince, "since", "", "", "Lint only files modified since a date (YYYY-MM-DD, RFC 3339) or since the last commit (last-commit)")
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "o", "text", "format of output. Allowed: text or sarif")
+	lintCmd.Flags().StringVarP(&lintFailOn, "fail-on", "", "none", "exit with a non-zero status on violations of this severity. Allowed: none, warning, or error")
+	lintCmd.Flags().IntVarP(&lintMaxWarnings, "max-warnings", "", -1, "exit with a non-zero status when the number of warnings exceeds this value (-1 = unlimited)")
 	rootCmd.AddCommand(lintCmd)
 }
 
@@ -23,17 +40,90 @@ var lintCmd = &cobra.Command{
 	Long:  `Check linter rules.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckConfig()
+		if lintFormat != "text" && lintFormat != "sarif" {
+			fmt.Printf("Unsupported format %q\n", lintFormat)
+			os.Exit(1)
+		}
+		threshold, err := core.NewLintThreshold(lintFailOn, lintMaxWarnings)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 		rules := strings.Split(lintRules, ",")
 		if slices.Contains(rules, "all") {
 			// Do not filter
 			rules = []string{}
 		}
 
-		result, err := core.CurrentRepository().Lint(rules, args...)
+		core.CurrentConfig().KeepGoing = lintKeepGoing
+		var result *core.LintResult
+		if lintStdin {
+			if lintPath == "" {
+				fmt.Println("Missing path. Use nt lint --stdin --path <path>")
+				os.Exit(1)
+			}
+			result, err = core.CurrentRepository().LintReader(lintPath, os.Stdin, rules)
+		} else if lintSince != "" {
+			var since time.Time
+			since, err = parseLintSince(lintSince)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			result, err = core.CurrentRepository().LintChanged(since, rules)
+		} else {
+			result, err = core.CurrentRepository().Lint(rules, args...)
+		}
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+		if lintFormat == "sarif" {
+			if err := core.CurrentRepository().LintToSARIF(result, os.Stdout); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if len(result.FileErrors) > 0 {
+				fmt.Fprintln(os.Stderr, result.FileErrors)
+				os.Exit(1)
+			}
+			if err := threshold.Check(result); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
 		fmt.Println(result)
+		if lintFix {
+			for _, suggestion := range result.Suggestions() {
+				fmt.Println(suggestion)
+			}
+		}
+		if len(result.FileErrors) > 0 {
+			os.Exit(1)
+		}
+		if err := threshold.Check(result); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	},
 }
+
+// parseLintSince converts the value of the flag --since to a time.
+func parseLintSince(value string) (time.Time, error) {
+	if value == "last-commit" {
+		head := core.CurrentDB().Head()
+		if head == nil {
+			// No commit yet = all files have changed
+			return time.Time{}, nil
+		}
+		return head.CTime, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, core.CurrentConfig().Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid value %q for --since (expected last-commit, YYYY-MM-DD, or RFC 3339)", value)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var logMaxCount int
+
+func init() {
+	logCmd.Flags().IntVarP(&logMaxCount, "max-count", "n", 0, "limit the number of commits to output")
+	rootCmd.AddCommand(logCmd)
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show commits",
+	Long:  `Show commits, starting with the most recent one, with their author.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		for i, commit := range core.CurrentDB().Log() {
+			if logMaxCount > 0 && i >= logMaxCount {
+				break
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("commit %s\n", commit.OID)
+			if commit.Author != nil {
+				fmt.Printf("Author: %s\n", commit.Author)
+			}
+			fmt.Printf("Date:   %s\n", commit.CTime.In(core.CurrentConfig().Location()).Format(time.RFC1123))
+			fmt.Println()
+			if commit.Message != "" {
+				for _, line := range strings.Split(commit.Message, "\n") {
+					fmt.Printf("    %s\n", line)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("    %d pack file(s)\n", len(commit.PackFiles))
+		}
+	},
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var mergeDryRun bool
+
+func init() {
+	mergeCmd.Flags().BoolVarP(&mergeDryRun, "dry-run", "n", false, "Only print the changes without modifying files")
+	rootCmd.AddCommand(mergeCmd)
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <target> <source>",
+	Short: "Merge a note into another note",
+	Long:  `Append the content of the source note to the target note, remove the source note, and update wikilinks.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		patch, err := core.CurrentRepository().MergeNotes(args[0], args[1], mergeDryRun)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if mergeDryRun {
+			fmt.Print(patch)
+		}
+	},
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var newTitle string
+var newTags []string
+var newVars []string
+var newFile string
+
+func init() {
+	newCmd.Flags().StringVarP(&newTitle, "title", "", "", "Title of the new note")
+	newCmd.Flags().StringSliceVarP(&newTags, "tag", "", nil, "Tags to add in the front matter")
+	newCmd.Flags().StringArrayVarP(&newVars, "var", "", nil, "Additional template variables (ex: --var author=Me)")
+	newCmd.Flags().StringVarP(&newFile, "file", "f", "", "Write the note in the given file instead of the standard output")
+	rootCmd.AddCommand(newCmd)
+}
+
+var newCmd = &cobra.Command{
+	Use:   "new <kind>",
+	Short: "Create a new note",
+	Long:  `Create a new note from the template configured for the given kind.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		vars := make(map[string]string)
+		for _, v := range newVars {
+			key, value, ok := strings.Cut(v, "=")
+			if !ok {
+				fmt.Printf("Invalid variable %q. Expected key=value\n", v)
+				os.Exit(1)
+			}
+			vars[key] = value
+		}
+		vars["title"] = newTitle
+		if len(newTags) > 0 {
+			vars["tags"] = strings.Join(newTags, ", ")
+		}
+
+		content, err := core.CurrentRepository().NewNoteFromTemplate(core.NoteKind(args[0]), vars)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if newFile == "" {
+			fmt.Print(content)
+			return
+		}
+
+		if _, err := os.Stat(newFile); !errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("File %s already exists\n", newFile)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(newFile, []byte(content), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var notesTags []string
+var notesAllTags bool
+
+func init() {
+	notesCmd.Flags().StringSliceVar(&notesTags, "tag", nil, "Only list notes with the given tag (can be repeated)")
+	notesCmd.Flags().BoolVarP(&notesAllTags, "all", "", false, "Only list notes with all the given tags instead of any of them")
+	rootCmd.AddCommand(notesCmd)
+}
+
+var notesCmd = &cobra.Command{
+	Use:   "notes --tag <tag>...",
+	Short: "List notes by tags",
+	Long:  `List notes having exactly the given tags.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		if len(notesTags) == 0 {
+			fmt.Println("Missing tag. Use nt notes --tag <tag>")
+			os.Exit(1)
+		}
+
+		var notes []*core.Note
+		var err error
+		if notesAllTags {
+			notes, err = core.CurrentRepository().FindNotesByAllTags(notesTags...)
+		} else {
+			notes, err = core.CurrentRepository().FindNotesByAnyTag(notesTags...)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, note := range notes {
+			fmt.Printf("%s:%d: %s\n", note.RelativePath, note.Line, note.Title)
+		}
+	},
+}
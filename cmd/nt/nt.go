@@ -14,6 +14,9 @@ import (
 var verboseInfo bool
 var verboseDebug bool
 var verboseTrace bool
+var verboseCount int
+var quiet bool
+var logFormat string
 
 var parallel int
 
@@ -32,6 +35,12 @@ var rootCmd = &cobra.Command{
 		}
 
 		// Enable verbose output. The most verbose level wins when multiple flags are passsed.
+		if quiet {
+			core.CurrentLogger().SetVerboseLevel(core.VerboseQuiet)
+		}
+		if verboseCount > 0 {
+			core.CurrentLogger().SetVerboseLevel(core.VerboseLevel(min(verboseCount, int(core.VerboseTrace))))
+		}
 		if verboseInfo {
 			core.CurrentLogger().SetVerboseLevel(core.VerboseInfo)
 		}
@@ -42,6 +51,11 @@ var rootCmd = &cobra.Command{
 			core.CurrentLogger().SetVerboseLevel(core.VerboseTrace)
 		}
 
+		if err := core.CurrentLogger().SetFormat(core.LogFormat(logFormat)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		if parallel > 0 {
 			core.CurrentConfig().SetParallel(parallel)
 		}
@@ -53,6 +67,9 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verboseInfo, "v", "", false, "enable verbose info output")
 	rootCmd.PersistentFlags().BoolVarP(&verboseDebug, "vv", "", false, "enable verbose debug output")
 	rootCmd.PersistentFlags().BoolVarP(&verboseTrace, "vvv", "", false, "enable verbose trace output")
+	rootCmd.PersistentFlags().CountVarP(&verboseCount, "verbose", "v", "increase verbosity (repeatable: -v, -vv, -vvv)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress all output except fatal errors")
+	rootCmd.PersistentFlags().StringVarP(&logFormat, "log-format", "", "text", "log format (text or json)")
 	rootCmd.PersistentFlags().IntVarP(&parallel, "parallel", "t", 0, "Number of workers to use when generating blobs")
 }
 
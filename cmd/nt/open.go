@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+}
+
+var openCmd = &cobra.Command{
+	Use:   "open <wikilink|slug|oid>",
+	Short: "Edit the file containing a note",
+	Long:  `Open the file containing a note in $EDITOR (or $VISUAL) at the line of the note.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		path, line, err := core.CurrentRepository().ResolveEditorLocation(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := openInEditor(path, line); err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to open %s: %v\n", path, err)
+			os.Exit(1)
+		}
+	},
+}
+
+// openInEditor edits a file at the given line.
+func openInEditor(path string, line int) error {
+	editor := strings.Fields(editorCommand())
+	editorArgs := editor[1:]
+	if editor[0] != "notepad" {
+		// Most editors (vi, emacs, nano, ...) support the syntax +<line>
+		editorArgs = append(editorArgs, fmt.Sprintf("+%d", line))
+	}
+	editorArgs = append(editorArgs, path)
+	editorCmd := exec.Command(editor[0], editorArgs...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	return editorCmd.Run()
+}
+
+// editorCommand returns the command to edit files.
+func editorCommand() string {
+	for _, name := range []string{"EDITOR", "VISUAL"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(outlineCmd)
+}
+
+var outlineCmd = &cobra.Command{
+	Use:   "outline <file>",
+	Short: "Show the outline of a file",
+	Long:  `Show the heading hierarchy of a file, annotating which headings are notes.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		entries, err := core.CurrentRepository().Outline(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(entries) == 0 {
+			return
+		}
+
+		// Indent relative to the top heading
+		minLevel := entries[0].Level
+		for _, entry := range entries {
+			minLevel = min(minLevel, entry.Level)
+		}
+		for _, entry := range entries {
+			indent := strings.Repeat("  ", entry.Level-minLevel)
+			if entry.Note {
+				fmt.Printf("%s%s [%s] (line %d)\n", indent, entry.Text, entry.Kind, entry.Line)
+			} else {
+				fmt.Printf("%s%s (line %d)\n", indent, entry.Text, entry.Line)
+			}
+		}
+	},
+}
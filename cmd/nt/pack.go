@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var packInspectVerify bool
+var packInspectJSON bool
+
+func init() {
+	packInspectCmd.Flags().BoolVarP(&packInspectVerify, "verify", "", false, "Check the pack file is consistent")
+	packInspectCmd.Flags().BoolVarP(&packInspectJSON, "json", "", false, "Output in JSON")
+	packCmd.AddCommand(packInspectCmd)
+	rootCmd.AddCommand(packCmd)
+}
+
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Manage pack files",
+	Long:  `Inspect pack files present in .nt/objects.`,
+}
+
+var packInspectCmd = &cobra.Command{
+	Use:   "inspect <oid>",
+	Short: "Show the content of a pack file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		oid, ok, err := resolveOID(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !ok {
+			fmt.Printf("Invalid OID %q\n", args[0])
+			os.Exit(1)
+		}
+
+		info, err := core.CurrentRepository().InspectPackFile(oid)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if packInspectJSON {
+			printJSON(info)
+		} else {
+			fmt.Printf("Pack file %s\n", info.OID)
+			fmt.Printf("Path:  %s\n", info.RelativePath)
+			fmt.Printf("MTime: %s\n", info.MTime.Format("2006-01-02 15:04:05"))
+			fmt.Printf("Size:  %d bytes\n", info.Size)
+			fmt.Println("")
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KIND\tOID\tSTATE\tSIZE\tDESCRIPTION")
+			for _, object := range info.Objects {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", object.Kind, object.OID, object.State, object.Size, object.Description)
+			}
+			w.Flush()
+
+			if len(info.Blobs) > 0 {
+				fmt.Println("")
+				w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "BLOB\tMIME")
+				for _, blob := range info.Blobs {
+					fmt.Fprintf(w, "%s\t%s\n", blob.OID, blob.MimeType)
+				}
+				w.Flush()
+			}
+		}
+
+		if packInspectVerify {
+			problems, err := core.CurrentRepository().VerifyPackFile(oid)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if len(problems) > 0 {
+				for _, problem := range problems {
+					fmt.Fprintln(os.Stderr, problem)
+				}
+				os.Exit(1)
+			}
+			if !packInspectJSON {
+				fmt.Println("")
+				fmt.Println("Pack file verified")
+			}
+		}
+	},
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+)
+
+/*
+ * The flag --interactive of nt search uses Bubble Tea to filter notes as you type (like fzf).
+ * The UI is written on stderr to keep stdout for the selected note.
+ */
+
+var (
+	pickerSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	pickerHelpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// Number of matching notes visible by default (before receiving the terminal size)
+const pickerDefaultHeight = 10
+
+// pickNote lets the user choose a note among candidates (nil if cancelled).
+func pickNote(candidates []*core.Note) (*core.Note, error) {
+	res, err := tea.NewProgram(newPickerModel(candidates), tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return nil, err
+	}
+	return res.(pickerModel).choice, nil
+}
+
+type pickerModel struct {
+	input      textinput.Model
+	candidates []*core.Note
+	matches    []*core.Note
+	cursor     int
+	height     int
+	choice     *core.Note
+	quitting   bool
+}
+
+func newPickerModel(candidates []*core.Note) pickerModel {
+	input := textinput.New()
+	input.Placeholder = "title, wikilink, ..."
+	input.Focus()
+	return pickerModel{
+		input:      input,
+		candidates: candidates,
+		matches:    candidates,
+		height:     pickerDefaultHeight,
+	}
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Keep lines for the input, the counter, and the help
+		m.height = max(1, msg.Height-4)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.quitting = true
+			return m, tea.Quit
+		case "enter":
+			if len(m.matches) > 0 {
+				m.choice = m.matches[m.cursor]
+			}
+			m.quitting = true
+			return m, tea.Quit
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "ctrl+n":
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	previousValue := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previousValue {
+		m.matches = core.FuzzyFindNotes(m.candidates, m.input.Value())
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+func (m pickerModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.input.View())
+	sb.WriteString("\n")
+	sb.WriteString(pickerHelpStyle.Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.candidates))))
+	sb.WriteString("\n")
+
+	// Scroll to keep the cursor visible
+	start := 0
+	if m.cursor >= m.height {
+		start = m.cursor - m.height + 1
+	}
+	end := min(len(m.matches), start+m.height)
+	for i := start; i < end; i++ {
+		note := m.matches[i]
+		line := fmt.Sprintf("%s (%s:%d)", note.LongTitle, note.RelativePath, note.Line)
+		if i == m.cursor {
+			sb.WriteString(pickerSelectedStyle.Render("> " + line))
+		} else {
+			sb.WriteString("  " + line)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(pickerHelpStyle.Render("(↑/↓ to move, enter to select, esc to quit)"))
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -8,22 +9,35 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var pullStrategy string
+
 func init() {
+	pullCmd.Flags().StringVarP(&pullStrategy, "strategy", "s", core.PullStrategyManual, "Strategy to resolve conflicts (manual, ours, theirs)")
 	rootCmd.AddCommand(pullCmd)
 }
 
 var pullCmd = &cobra.Command{
-	Use:   "pull",
+	Use:   "pull [<remote>]",
 	Short: "Pull remote",
 	Long:  `Pull remote to retrieve new objects and update local database.`,
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckConfig()
-		if core.CurrentDB().Origin() == nil {
-			fmt.Println("There is no remote currently configured.")
+		remoteName := core.DefaultRemoteName
+		if len(args) > 0 {
+			remoteName = args[0]
+		} else if core.CurrentDB().Origin() == nil {
+			fmt.Println("There is no remote currently configured.")
 			fmt.Println("Please specify one in .nt/config")
 			os.Exit(1)
 		}
-		err := core.CurrentDB().Pull()
+		err := core.CurrentDB().PullRemote(remoteName, pullStrategy)
+		var conflictErr *core.PullConflictError
+		if errors.As(err, &conflictErr) {
+			fmt.Println(err)
+			fmt.Println("Use --strategy=ours or --strategy=theirs to resolve conflicts.")
+			os.Exit(1)
+		}
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -8,25 +8,59 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var pushBundle bool
+var pushAll bool
+var pushRemoteIndexCache bool
+
 func init() {
+	pushCmd.Flags().BoolVarP(&pushBundle, "bundle", "", false, "upload a bundle of all objects to speed up the first pull")
+	pushCmd.Flags().BoolVarP(&pushAll, "all", "", false, "push to every configured remote")
+	pushCmd.Flags().BoolVarP(&pushRemoteIndexCache, "remote-index-cache", "", false, "download the remote index only when changed since the last operation")
 	rootCmd.AddCommand(pushCmd)
 }
 
 var pushCmd = &cobra.Command{
-	Use:   "push",
+	Use:   "push [<remote>]",
 	Short: "Push to remote",
 	Long:  `Push to remote new objects.`,
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		CheckConfig()
-		if core.CurrentDB().Origin() == nil {
-			fmt.Println("There is no remote currently configured.")
+		core.CurrentConfig().RemoteIndexCache = pushRemoteIndexCache
+
+		var remoteNames []string
+		if pushAll {
+			if len(args) > 0 {
+				fmt.Println("A remote cannot be specified with --all")
+				os.Exit(1)
+			}
+			remoteNames = core.CurrentConfig().ConfigFile.RemoteNames()
+		} else if len(args) > 0 {
+			remoteNames = []string{args[0]}
+		} else if core.CurrentDB().Origin() != nil {
+			remoteNames = []string{core.DefaultRemoteName}
+		}
+		if len(remoteNames) == 0 {
+			fmt.Println("There is no remote currently configured.")
 			fmt.Println("Please specify one in .nt/config")
 			os.Exit(1)
 		}
-		err := core.CurrentDB().Push()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+
+		for _, remoteName := range remoteNames {
+			if len(remoteNames) > 1 {
+				fmt.Printf("Pushing to %s...\n", remoteName)
+			}
+			err := core.CurrentDB().PushRemote(remoteName)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if pushBundle {
+				if err := core.CurrentRepository().PushBundleToRemote(remoteName); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
 		}
 	},
 }
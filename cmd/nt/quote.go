@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var quoteTags []string
+
+func init() {
+	quoteCmd.Flags().StringSliceVar(&quoteTags, "tag", nil, "Only consider quotes with the given tag (ex: favorite)")
+	rootCmd.AddCommand(quoteCmd)
+}
+
+var quoteCmd = &cobra.Command{
+	Use:   "quote [query]",
+	Short: "Print a random quote",
+	Long:  `Print a random quote among the quotes matching the optional query.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		query := args
+		for _, tag := range quoteTags {
+			query = append(query, "#"+strings.TrimPrefix(tag, "#"))
+		}
+
+		quote, err := core.CurrentRepository().RandomQuote(strings.Join(query, " "))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if quote == nil {
+			fmt.Fprintln(os.Stderr, "No quote found")
+			os.Exit(1)
+		}
+		fmt.Println(quote.ContentMarkdown)
+	},
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(recoverCmd)
+}
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Recover from an interrupted commit",
+	Long:  `Complete or cancel a commit interrupted before all files were written.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		action, err := core.CurrentDB().Recover()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if action == core.RecoveryNone {
+			fmt.Println("Nothing to recover")
+			return
+		}
+		fmt.Printf("Interrupted commit %s\n", action)
+	},
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the full-text index",
+	Long:  `Recreate the full-text index used by nt search with the configured tokenizer.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		if err := core.CurrentRepository().Reindex(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
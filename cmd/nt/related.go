@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var relatedLimit int
+
+func init() {
+	relatedCmd.Flags().IntVarP(&relatedLimit, "limit", "n", core.DefaultQueryLimit, "maximum number of notes to return")
+	rootCmd.AddCommand(relatedCmd)
+}
+
+var relatedCmd = &cobra.Command{
+	Use:   "related <wikilink|slug|oid>",
+	Short: "List related notes",
+	Long:  `List the notes sharing links, tags, or attribute values with a note, starting with the most related one.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		note, err := core.CurrentRepository().ResolveNote(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if note == nil {
+			fmt.Printf("no note found for %q\n", args[0])
+			os.Exit(1)
+		}
+
+		notes, err := core.CurrentRepository().FindRelatedNotes(note.OID, relatedLimit)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, note := range notes {
+			fmt.Printf("%s:%d: %s\n", note.RelativePath, note.Line, note.Title)
+		}
+	},
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+	"github.com/spf13/cobra"
+)
+
+var remindDueWithin string
+
+func init() {
+	remindDueCmd.Flags().StringVarP(&remindDueWithin, "within", "", "", "Include reminders due in the given duration (ex: 7d)")
+	remindCmd.AddCommand(remindDueCmd)
+	remindCmd.AddCommand(remindSnoozeCmd)
+	rootCmd.AddCommand(remindCmd)
+}
+
+var remindCmd = &cobra.Command{
+	Use:     "remind",
+	Aliases: []string{"reminders"},
+	Short:   "Manage reminders",
+	Long:    `Manage reminders declared in notes using tags #reminder-*.`,
+}
+
+var remindDueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "List due reminders",
+	Long:  `List reminders due now, or in the given duration, sorted by due date.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		at := clock.Now()
+		if remindDueWithin != "" {
+			d, err := clock.ParseDuration(remindDueWithin)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			at = at.Add(d)
+		}
+		reminders, err := core.CurrentRepository().DueReminders(at)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		loc := core.CurrentConfig().Location()
+		for _, reminder := range reminders {
+			source := reminder.RelativePath
+			if note := reminder.GetNote(); note != nil {
+				source = fmt.Sprintf("%s: %s", note.RelativePath, note.Title)
+			}
+			fmt.Printf("%s %s (%s) [%s]\n", reminder.NextPerformedAt.In(loc).Format("2006-01-02"), reminder.DescriptionText, source, reminder.OID)
+		}
+	},
+}
+
+var remindSnoozeCmd = &cobra.Command{
+	Use:   "snooze <oid> <duration>",
+	Short: "Postpone the next occurrence of a reminder",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		d, err := clock.ParseDuration(args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		oid, err := core.CurrentDB().ResolveOID(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := core.CurrentRepository().SnoozeReminder(oid, d); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		reminder, err := core.CurrentRepository().LoadReminderByOID(oid)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Reminder %q snoozed until %s\n", reminder.DescriptionText, reminder.NextPerformedAt.In(core.CurrentConfig().Location()).Format("2006-01-02 15:04"))
+	},
+}
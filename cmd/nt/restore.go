@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var restoreStaged bool
+
+func init() {
+	restoreCmd.Flags().BoolVarP(&restoreStaged, "staged", "S", false, "restore the staging area")
+	rootCmd.AddCommand(restoreCmd)
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [--staged] <path>...",
+	Short: "Unstage files",
+	Long:  `Revert staged changes for the given files while leaving other staged files intact.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		err := core.CurrentRepository().Restore(args, restoreStaged)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
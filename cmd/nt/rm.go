@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var rmCached bool
+
+func init() {
+	rmCmd.Flags().BoolVarP(&rmCached, "cached", "", false, "only remove from the index and keep the files on disk")
+	rootCmd.AddCommand(rmCmd)
+}
+
+var rmCmd = &cobra.Command{
+	Use:   "rm [--cached] <path>...",
+	Short: "Remove files",
+	Long:  `Stage the deletion of the objects present in the given files and delete the files.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		err := core.CurrentRepository().Remove(args, rmCached)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var searchSaved string
+var searchList bool
+var searchJSON bool
+var searchInteractive bool
+var searchOpen bool
+
+// How many characters to include in previews of notes
+const searchExcerptLength = 200
+
+// searchResult is the JSON representation of a note matching a search.
+type searchResult struct {
+	OID          string `json:"oid"`
+	Slug         string `json:"slug"`
+	RelativePath string `json:"relativePath"`
+	Line         int    `json:"line"`
+	Title        string `json:"title"`
+	Excerpt      string `json:"excerpt"`
+	// Matched terms in context (only when searching for terms)
+	Snippet string `json:"snippet,omitempty"`
+}
+
+func init() {
+	searchCmd.Flags().StringVarP(&searchSaved, "saved", "", "", "Run the saved search with this name")
+	searchCmd.Flags().BoolVarP(&searchList, "list", "", false, "List saved searches")
+	searchCmd.Flags().BoolVarP(&searchJSON, "json", "", false, "Output in JSON")
+	searchCmd.Flags().BoolVarP(&searchInteractive, "interactive", "i", false, "Filter notes interactively by title")
+	searchCmd.Flags().BoolVarP(&searchOpen, "open", "", false, "Open the selected note in $EDITOR (requires --interactive)")
+	rootCmd.AddCommand(searchCmd)
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search notes",
+	Long:  `Search notes matching a query or a saved search.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		if searchList {
+			searches := core.CurrentConfig().ConfigFile.Search
+			names := make([]string, 0, len(searches))
+			for name := range searches {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("%s: %s (%s)\n", name, searches[name].Name, searches[name].Q)
+			}
+			return
+		}
+
+		if searchOpen && !searchInteractive {
+			fmt.Println("--open requires --interactive")
+			os.Exit(1)
+		}
+		if searchInteractive && searchJSON {
+			fmt.Println("--interactive cannot be used with --json")
+			os.Exit(1)
+		}
+
+		if searchSaved == "" && len(args) == 0 && !searchInteractive {
+			fmt.Println("Missing query. Use nt search <query> or --saved=<name>")
+			os.Exit(1)
+		}
+
+		q := strings.Join(args, " ")
+		if searchSaved != "" {
+			search, ok := core.CurrentConfig().ConfigFile.Search[searchSaved]
+			if !ok {
+				fmt.Printf("Unknown saved search %q\n", searchSaved)
+				os.Exit(1)
+			}
+			q = search.Q
+		}
+
+		if searchInteractive {
+			candidates, err := core.CurrentRepository().FindFuzzyCandidates(q)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			note, err := pickNote(candidates)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if note == nil {
+				// Cancelled
+				os.Exit(1)
+			}
+			if searchOpen {
+				path := core.CurrentRepository().GetAbsolutePath(note.RelativePath)
+				if err := openInEditor(path, note.Line); err != nil {
+					fmt.Fprintf(os.Stderr, "Unable to open %s: %v\n", path, err)
+					os.Exit(1)
+				}
+				return
+			}
+			fmt.Printf("%s:%d: %s\n", note.RelativePath, note.Line, note.Title)
+			return
+		}
+
+		results, err := core.CurrentRepository().SearchNotesWithSnippets(q)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if searchJSON {
+			jsonResults := []searchResult{}
+			for _, result := range results {
+				note := result.Note
+				jsonResults = append(jsonResults, searchResult{
+					OID:          note.OID,
+					Slug:         note.Slug,
+					RelativePath: note.RelativePath,
+					Line:         note.Line,
+					Title:        note.Title,
+					Excerpt:      note.Excerpt(searchExcerptLength),
+					Snippet:      result.Snippet,
+				})
+			}
+			printJSON(jsonResults)
+			return
+		}
+		for _, result := range results {
+			note := result.Note
+			fmt.Printf("%s:%d: %s\n", note.RelativePath, note.Line, note.Title)
+		}
+	},
+}
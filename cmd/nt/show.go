@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var showFormat string
+
+func init() {
+	showCmd.Flags().StringVarP(&showFormat, "format", "o", "md", "format of output. Allowed: md, html, or text")
+	rootCmd.AddCommand(showCmd)
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <wikilink|oid>",
+	Short: "Print a note",
+	Long:  `Print a note in Markdown, HTML, or plain text with links to medias resolved to local files.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		note, err := findNote(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		switch showFormat {
+		case "md", "markdown":
+			fmt.Println(core.CurrentRepository().ResolveOIDLinks(note.FormatToMarkdown()))
+		case "html":
+			fmt.Println(core.CurrentRepository().ResolveOIDLinks(note.FormatToHTML()))
+		case "text":
+			fmt.Println(note.FormatToText())
+		default:
+			fmt.Fprintf(os.Stderr, "Unsupported output format %q\n", showFormat)
+			os.Exit(1)
+		}
+	},
+}
+
+// findNote searches a single note by OID or wikilink.
+func findNote(arg string) (*core.Note, error) {
+	oid, ok, err := resolveOID(arg)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		note, err := core.CurrentRepository().LoadNoteByOID(oid)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, fmt.Errorf("no note found with OID %s", arg)
+		}
+		return note, nil
+	}
+
+	wikilink, err := core.NewWikilink("[[" + arg + "]]")
+	if err != nil {
+		return nil, fmt.Errorf("argument %q doesn't match an OID and isn't a valid wikilink", arg)
+	}
+	notes, err := core.CurrentRepository().FindNotesByWikilink(wikilink.Link)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("no note found with wikilink %q", arg)
+	}
+	if len(notes) > 1 {
+		return nil, fmt.Errorf("multiple notes found with wikilink %q", arg)
+	}
+	return notes[0], nil
+}
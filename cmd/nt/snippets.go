@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/julien-sobczak/the-notewriter/pkg/markdown"
+	"github.com/spf13/cobra"
+)
+
+var snippetsCode bool
+var snippetsCopy bool
+
+func init() {
+	snippetsCmd.Flags().BoolVarP(&snippetsCode, "code", "c", false, "Print only the first code block")
+	snippetsCmd.Flags().BoolVarP(&snippetsCopy, "copy", "", false, "Copy the first matching snippet to the clipboard")
+	rootCmd.AddCommand(snippetsCmd)
+}
+
+var snippetsCmd = &cobra.Command{
+	Use:   "snippets [query]",
+	Short: "Print snippets",
+	Long:  `Print the content of snippet notes matching the optional query.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		snippets, err := core.CurrentRepository().FindSnippets(strings.Join(args, " "))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(snippets) == 0 {
+			fmt.Fprintln(os.Stderr, "No snippet found")
+			os.Exit(1)
+		}
+
+		if snippetsCopy {
+			snippet := snippets[0]
+			if err := clipboard.WriteAll(snippetContent(snippet)); err != nil {
+				fmt.Fprintf(os.Stderr, "Unable to copy to the clipboard: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Copied %q\n", snippet.ShortTitle)
+			return
+		}
+
+		for i, snippet := range snippets {
+			if len(snippets) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				// Headers are printed on stderr to pipe only the snippet content
+				fmt.Fprintf(os.Stderr, "# %s (%s)\n", snippet.ShortTitle, snippet.RelativePath)
+			}
+			fmt.Println(snippetContent(snippet))
+		}
+	},
+}
+
+// snippetContent returns the content of a snippet, or only its first code block when requested.
+func snippetContent(snippet *core.Note) string {
+	content := snippet.ContentMarkdown
+	if snippetsCode {
+		if codeBlocks := markdown.ExtractCodeBlocks(content); len(codeBlocks) > 0 {
+			return codeBlocks[0]
+		}
+	}
+	return content
+}
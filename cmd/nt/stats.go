@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var statsByTag bool
+var statsTimeline string
+var statsChecklists bool
+var statsDangling bool
+var statsJSON bool
+
+func init() {
+	statsCmd.Flags().BoolVarP(&statsByTag, "by-tag", "", false, "Show the number of notes per kind for every tag")
+	statsCmd.Flags().StringVarP(&statsTimeline, "timeline", "", "", "Show the number of notes/flashcards/words created per day, week, or month")
+	statsCmd.Flags().BoolVarP(&statsChecklists, "checklists", "", false, "Show the completion of every checklist")
+	statsCmd.Flags().BoolVarP(&statsDangling, "dangling", "", false, "Show the number of dangling medias, dead wikilinks, and orphan notes")
+	statsCmd.Flags().BoolVarP(&statsJSON, "json", "", false, "Output in JSON")
+	rootCmd.AddCommand(statsCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics",
+	Long:  `Show statistics about notes grouped by tag or over time, checklists completion, and problems in notes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		if !statsByTag && statsTimeline == "" && !statsChecklists && !statsDangling {
+			fmt.Println("Missing option. Use --by-tag, --timeline=<day|week|month>, --checklists, or --dangling")
+			os.Exit(1)
+		}
+
+		if statsByTag {
+			stats, err := core.CurrentRepository().StatsByTag()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if statsJSON {
+				printJSON(stats)
+			} else {
+				tags := make([]string, 0, len(stats))
+				for tag := range stats {
+					tags = append(tags, tag)
+				}
+				sort.Strings(tags)
+				for _, tag := range tags {
+					fmt.Printf("%s:\n", tag)
+					kinds := make(map[string]int)
+					for kind, count := range stats[tag] {
+						kinds[string(kind)] = count
+					}
+					for _, kind := range keysSortedByValuesDesc(kinds) {
+						fmt.Printf("- %s: %d\n", kind, kinds[kind])
+					}
+				}
+			}
+		}
+
+		if statsTimeline != "" {
+			timeline, err := core.CurrentRepository().StatsTimeline(statsTimeline)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if statsJSON {
+				printJSON(timeline)
+			} else {
+				for _, bucket := range timeline {
+					fmt.Printf("%s: %d notes, %d flashcards, %d words\n", bucket.Start.Format("2006-01-02"), bucket.Notes, bucket.Flashcards, bucket.Words)
+				}
+			}
+		}
+
+		if statsChecklists {
+			checklists, err := core.CurrentRepository().StatsChecklists()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if statsJSON {
+				printJSON(checklists)
+			} else {
+				for _, checklist := range checklists {
+					fmt.Printf("%s (%s): %d/%d (%d%%)\n", checklist.Title, checklist.RelativePath, checklist.Done, checklist.Total, checklist.Percent())
+				}
+			}
+		}
+
+		if statsDangling {
+			health, err := core.CurrentRepository().HealthStats()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if statsJSON {
+				printJSON(health)
+			} else {
+				fmt.Printf("Dangling medias: %d\n", health.DanglingMedias)
+				fmt.Printf("Dead wikilinks:  %d\n", health.DeadWikilinks)
+				fmt.Printf("Orphan notes:    %d\n", health.OrphanNotes)
+			}
+		}
+	},
+}
+
+func printJSON(v any) {
+	output, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println(string(output))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var tagRenameDryRun bool
+
+func init() {
+	tagRenameCmd.Flags().BoolVarP(&tagRenameDryRun, "dry-run", "n", false, "Report the files to modify without changing them")
+	tagCmd.AddCommand(tagRenameCmd)
+	rootCmd.AddCommand(tagCmd)
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage tags",
+	Long:  `Manage tags present in notes.`,
+}
+
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new> [--] [<pathspec>...]",
+	Short: "Rename a tag in all files",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		count, err := core.CurrentRepository().RenameTag(args[0], args[1], tagRenameDryRun, args[2:]...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if tagRenameDryRun {
+			fmt.Printf("%d file(s) would be modified\n", count)
+		} else {
+			fmt.Printf("%d file(s) modified\n", count)
+		}
+	},
+}
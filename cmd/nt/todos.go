@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var todosSort string
+var todosJSON bool
+
+func init() {
+	todosCmd.Flags().StringVarP(&todosSort, "sort", "", "", "Sort tasks by next reminder date (due)")
+	todosCmd.Flags().BoolVarP(&todosJSON, "json", "", false, "Output in JSON")
+	rootCmd.AddCommand(todosCmd)
+}
+
+var todosCmd = &cobra.Command{
+	Use:   "todos [path]...",
+	Short: "List open tasks",
+	Long:  `List unchecked items of todo notes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		if todosSort != "" && todosSort != "due" {
+			fmt.Printf("Unsupported sort %q. Use --sort=due\n", todosSort)
+			os.Exit(1)
+		}
+
+		items, err := core.CurrentRepository().FindOpenTodos(args...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if todosSort == "due" {
+			core.SortTodosByDue(items)
+		}
+
+		if todosJSON {
+			printJSON(items)
+			return
+		}
+		for _, item := range items {
+			if item.DueAt.IsZero() {
+				fmt.Printf("%s:%d: %s\n", item.RelativePath, item.Line, item.Text)
+			} else {
+				fmt.Printf("%s:%d: %s (due %s)\n", item.RelativePath, item.Line, item.Text, item.DueAt.Format("2006-01-02"))
+			}
+		}
+	},
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var verifyRemoteJSON bool
+
+func init() {
+	verifyRemoteCmd.Flags().BoolVarP(&verifyRemoteJSON, "json", "", false, "Output in JSON")
+	rootCmd.AddCommand(verifyRemoteCmd)
+}
+
+var verifyRemoteCmd = &cobra.Command{
+	Use:   "verify-remote",
+	Short: "Check the remote holds all objects",
+	Long:  `Check every pack file and blob referenced by the remote index exists remotely.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+		if core.CurrentDB().Origin() == nil {
+			fmt.Println("There is no remote currently configured.")
+			fmt.Println("Please specify one in .nt/config")
+			os.Exit(1)
+		}
+		report, err := core.CurrentRepository().VerifyRemote()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if verifyRemoteJSON {
+			printJSON(report)
+		} else {
+			fmt.Printf("Checked %d pack file(s) and %d blob(s)\n", report.PackFilesChecked, report.BlobsChecked)
+			for _, oid := range report.MissingPackFiles {
+				fmt.Printf("missing pack file %s\n", oid)
+			}
+			for _, oid := range report.MissingBlobs {
+				fmt.Printf("missing blob %s\n", oid)
+			}
+			for _, key := range report.ExtraObjects {
+				fmt.Printf("extra object %s\n", key)
+			}
+		}
+
+		if !report.Complete() {
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/julien-sobczak/the-notewriter/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var watchLintOnly bool
+
+func init() {
+	watchCmd.Flags().BoolVarP(&watchLintOnly, "lint-only", "", false, "Only lint changed files without staging them")
+	rootCmd.AddCommand(watchCmd)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [path]...",
+	Short: "Add changed files automatically",
+	Long:  `Watch files and add them to the staging area as they change.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CheckConfig()
+
+		// Stop on Ctrl+C
+		done := make(chan struct{})
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			close(done)
+		}()
+
+		fmt.Println("Watching for changes (Ctrl+C to stop)...")
+		err := core.CurrentRepository().Watch(args, watchLintOnly, core.DefaultWatchDebounce, done, func(result *core.WatchResult) {
+			switch {
+			case result.Err != nil:
+				fmt.Printf("%s: %v\n", result.RelativePath, result.Err)
+			case result.Lint != nil:
+				fmt.Printf("%s: %s", result.RelativePath, result.Lint)
+			case result.Summary != nil:
+				fmt.Printf("%s: %d added, %d modified, %d deleted\n", result.RelativePath, len(result.Summary.Added), len(result.Summary.Modified), len(result.Summary.Deleted))
+			}
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
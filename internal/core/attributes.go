@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/julien-sobczak/the-notewriter/internal/reference"
 	"github.com/julien-sobczak/the-notewriter/pkg/text"
 	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v3"
@@ -132,6 +133,37 @@ func UnmarshalAttributes(rawValue string) (map[string]interface{}, error) {
 	return CastAttributes(attributes, types), nil
 }
 
+// ComputeAttributes evaluates the attributes declared with a value in schemas (ex: value: "{{.author}}")
+// using the other attributes. Attributes referencing missing attributes are not computed.
+func ComputeAttributes(attributes map[string]interface{}, relativePath string, kind NoteKind) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, definition := range GetSchemaAttributes(relativePath, kind) {
+		if definition.Value == "" {
+			continue
+		}
+		tmpl, err := reference.ParseTemplate(definition.Value)
+		if err != nil {
+			// Already reported when checking the configuration
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Option("missingkey=error").Execute(&buf, attributes); err != nil {
+			CurrentLogger().Debugf("Unable to compute attribute %q in %s: %v", definition.Name, relativePath, err)
+			continue
+		}
+		value := strings.TrimSpace(buf.String())
+		if value == "" {
+			continue
+		}
+		if definition.Type == "" {
+			result[definition.Name] = value
+		} else if typedValue := CastAttribute(value, definition.Type); typedValue != nil {
+			result[definition.Name] = typedValue
+		}
+	}
+	return result
+}
+
 // CastAttributes enforces the types declared in linter schemas.
 func CastAttributes(attributes map[string]interface{}, types map[string]string) map[string]interface{} {
 	result := make(map[string]interface{})
@@ -261,6 +293,37 @@ func FilterNonInheritableAttributes(attributes map[string]interface{}, relativeP
 	return result
 }
 
+// FilterAttributesByInheritDepth removes from the list all attributes that do not propagate
+// down to the given distance (1 = direct children, etc.).
+func FilterAttributesByInheritDepth(attributes map[string]interface{}, relativePath string, kind NoteKind, distance int) map[string]interface{} {
+	inheritDepths := make(map[string]int)
+	for _, definition := range GetSchemaAttributes(relativePath, kind) {
+		if definition.InheritDepth != nil {
+			inheritDepths[definition.Name] = *definition.InheritDepth
+		}
+	}
+	result := make(map[string]interface{})
+	for key, value := range attributes {
+		if depth, ok := inheritDepths[key]; ok && distance > depth {
+			// too deep
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// GetSchemaAttributeInheritDepth returns the maximum inherit depth of an attribute for a given note.
+// Nil means unlimited.
+func GetSchemaAttributeInheritDepth(name string, relativePath string, kind NoteKind) *int {
+	for _, definition := range GetSchemaAttributes(relativePath, kind) {
+		if definition.Name == name {
+			return definition.InheritDepth
+		}
+	}
+	return nil
+}
+
 // ExtractBlockTagsAndAttributes searches for all tags and attributes declared on standalone lines
 // (in comparison with tags/attributes defined, for example, on To-Do list items).
 func ExtractBlockTagsAndAttributes(content string) ([]string, map[string]interface{}) {
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CreateBundle writes the index, the commit graph, and all committed pack files and blobs
+// into a single gzipped tar stream. Entries use the same keys as in remotes (ex: "info/commit-graph").
+func (r *Repository) CreateBundle(w io.Writer) error {
+	db := CurrentDB()
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	writeEntry := func(key string, data []byte) error {
+		header := &tar.Header{
+			Name: key,
+			Mode: 0644,
+			Size: int64(len(data)),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	writeBlobEntry := func(oid string) error {
+		stat, err := os.Stat(db.blobPath(oid))
+		if err != nil {
+			return err
+		}
+		blob, err := db.OpenBlob(oid)
+		if err != nil {
+			return err
+		}
+		defer blob.Close()
+		header := &tar.Header{
+			Name: OIDToPath(oid),
+			Mode: 0644,
+			Size: stat.Size(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, blob)
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := db.index.CloneForRemote().Write(buf); err != nil {
+		return err
+	}
+	if err := writeEntry("index", buf.Bytes()); err != nil {
+		return err
+	}
+	buf = new(bytes.Buffer)
+	if err := db.commitGraph.Write(buf); err != nil {
+		return err
+	}
+	if err := writeEntry("info/commit-graph", buf.Bytes()); err != nil {
+		return err
+	}
+
+	var packFileOIDs []string
+	for oid := range db.index.PackFiles {
+		packFileOIDs = append(packFileOIDs, oid)
+	}
+	sort.Strings(packFileOIDs)
+
+	blobsWritten := make(map[string]bool)
+	for _, oid := range packFileOIDs {
+		packFile, err := db.ReadPackFile(oid)
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		if err := packFile.Write(buf); err != nil {
+			return err
+		}
+		if err := writeEntry(OIDToPath(oid), buf.Bytes()); err != nil {
+			return err
+		}
+
+		for _, packObject := range packFile.PackObjects {
+			for _, blobRef := range packObject.ReadObject().Blobs() {
+				if blobsWritten[blobRef.OID] {
+					continue
+				}
+				// Blobs are streamed as medias can be large
+				if err := writeBlobEntry(blobRef.OID); err != nil {
+					return err
+				}
+				blobsWritten[blobRef.OID] = true
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// PushBundle uploads a new bundle to the origin. The bundle is used by Pull on new repositories.
+func (r *Repository) PushBundle() error {
+	return r.PushBundleToRemote(DefaultRemoteName)
+}
+
+// PushBundleToRemote uploads a new bundle to the remote with the given name.
+func (r *Repository) PushBundleToRemote(name string) error {
+	origin, err := CurrentDB().RemoteByName(name)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	if err := r.CreateBundle(buf); err != nil {
+		return err
+	}
+	CurrentLogger().Debugf("Uploading bundle (%d bytes)...", buf.Len())
+	return origin.PutObject("bundle", buf.Bytes())
+}
+
+// PullBundle unpacks a bundle created by CreateBundle and retrieves its commits locally.
+func (r *Repository) PullBundle(in io.Reader) error {
+	dir, err := os.MkdirTemp("", "nt-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		key := path.Clean(header.Name)
+		if path.IsAbs(key) || key == ".." || strings.HasPrefix(key, "../") {
+			return fmt.Errorf("invalid bundle entry %q", header.Name)
+		}
+		targetPath := filepath.Join(dir, filepath.FromSlash(key))
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return err
+		}
+		out, err := os.Create(targetPath)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, tr)
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Reuse the pull logic by exposing the unpacked bundle as a remote
+	bundle, err := NewFSRemote(dir)
+	if err != nil {
+		return err
+	}
+	_, err = CurrentDB().pullFrom(bundle, PullStrategyManual)
+	return err
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+
+	t.Run("CreateBundle and PullBundle", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		require.NoError(t, CurrentRepository().Add("."))
+		require.NoError(t, CurrentDB().Commit("initial commit"))
+		head := CurrentDB().Head().OID
+
+		var bundle bytes.Buffer
+		require.NoError(t, CurrentRepository().CreateBundle(&bundle))
+
+		Reset()
+
+		// Restore in a new repository
+		root := SetUpRepositoryFromTempDir(t)
+		require.NoError(t, CurrentRepository().PullBundle(&bundle))
+
+		ref, ok := CurrentDB().Ref("main")
+		require.True(t, ok)
+		assert.Equal(t, head, ref)
+		require.FileExists(t, filepath.Join(root, ".nt/objects/info/commit-graph"))
+		note := MustFindNoteByPathAndTitle(t, "go.md", "Reference: Golang History")
+		assert.NotEmpty(t, note.ContentRaw)
+		logo, err := CurrentRepository().FindMediaByRelativePath("medias/go.svg")
+		require.NoError(t, err)
+		require.NotNil(t, logo)
+		for _, blob := range logo.BlobRefs {
+			assert.FileExists(t, filepath.Join(root, ".nt/objects", OIDToPath(blob.OID)))
+		}
+	})
+
+	t.Run("Pull uses the bundle", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		origin := t.TempDir()
+		CurrentConfig().ConfigFile.Remote = ConfigRemote{
+			Type: "fs",
+			Dir:  origin,
+		}
+		require.NoError(t, CurrentRepository().Add("."))
+		require.NoError(t, CurrentDB().Commit("initial commit"))
+		require.NoError(t, CurrentDB().Push())
+		require.NoError(t, CurrentRepository().PushBundle())
+		require.FileExists(t, filepath.Join(origin, "bundle"))
+		initialPackFile := CurrentDB().Head().PackFiles[0].OID
+
+		// Add a commit not present in the bundle
+		MustWriteFile(t, "python.md", "# Python\n\n## Note: Creator\n\nGuido van Rossum\n")
+		require.NoError(t, CurrentRepository().Add("."))
+		require.NoError(t, CurrentDB().Commit("second commit"))
+		require.NoError(t, CurrentDB().Push())
+
+		// Loose objects of the first commit are no longer needed
+		require.NoError(t, os.Remove(filepath.Join(origin, OIDToPath(initialPackFile))))
+
+		Reset()
+
+		SetUpRepositoryFromTempDir(t)
+		CurrentConfig().ConfigFile.Remote = ConfigRemote{
+			Type: "fs",
+			Dir:  origin,
+		}
+		require.NoError(t, CurrentDB().Pull())
+
+		MustFindNoteByPathAndTitle(t, "go.md", "Reference: Golang History")
+		MustFindNoteByPathAndTitle(t, "python.md", "Note: Creator")
+	})
+}
@@ -49,6 +49,74 @@ This is a second note
 		assert.Equal(t, "duplicated note with title \"Name\"", violation.Message)
 	})
 
+	t.Run("Reader", func(t *testing.T) {
+		root := SetUpRepositoryFromTempDir(t)
+		err := os.WriteFile(filepath.Join(root, ".nt/lint"), []byte(`
+rules:
+- name: no-duplicate-note-title
+`), 0644)
+		require.NoError(t, err)
+		configOnce.Reset()
+
+		// The file on disk is valid but not the unsaved content
+		MustWriteFile(t, "lint.md", "# Linter\n\n## Note: Name\n\nThis is a first note\n")
+		result, err := CurrentRepository().LintReader("lint.md", strings.NewReader(`
+# Linter
+
+## Note: Name
+
+This is a first note
+
+## Note: Name
+
+This is a second note
+`), nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, result.AnalyzedFiles)
+		require.Equal(t, 1, result.AffectedFiles)
+		require.Len(t, result.Errors, 1)
+		violation := result.Errors[0]
+		assert.Equal(t, "lint.md", violation.RelativePath)
+		assert.Equal(t, "duplicated note with title \"Name\"", violation.Message)
+
+		// The file on disk is left untouched
+		result, err = CurrentRepository().Lint(nil, ".")
+		require.NoError(t, err)
+		assert.Empty(t, result.Errors)
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		root := SetUpRepositoryFromTempDir(t)
+		err := os.WriteFile(filepath.Join(root, ".nt/lint"), []byte(`
+rules:
+- name: no-dead-wikilink
+`), 0644)
+		require.NoError(t, err)
+		configOnce.Reset()
+
+		MustWriteFile(t, "old.md", "# Old\n\n## Note: Target\n\nSee [[unknown]]\n")
+		MustWriteFile(t, "new.md", "# New\n\n## Note: Source\n\nSee [[old#Note: Target]] and [[missing]]\n")
+		lastCommit := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+		oldTime := lastCommit.Add(-24 * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(root, "old.md"), oldTime, oldTime))
+
+		result, err := CurrentRepository().LintChanged(lastCommit, nil)
+		require.NoError(t, err)
+		// Only the modified file is analyzed but links to unchanged files are resolved
+		require.Equal(t, 1, result.AnalyzedFiles)
+		require.Equal(t, 1, result.AffectedFiles)
+		require.Len(t, result.Errors, 1)
+		violation := result.Errors[0]
+		assert.Equal(t, "new.md", violation.RelativePath)
+		assert.Equal(t, "file not found for wikilink [[missing]]", violation.Message)
+
+		// All files are analyzed without a date
+		result, err = CurrentRepository().LintChanged(time.Time{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.AnalyzedFiles)
+		require.Len(t, result.Errors, 2)
+	})
+
 }
 
 func TestCommandAdd(t *testing.T) {
@@ -281,6 +349,100 @@ Python was conceived in the Netherlands by Guido van Rossum as a successor to th
 		assert.Equal(t, 2, stats.Objects["note"])
 	})
 
+	t.Run("Keep going", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+
+		MustWriteFile(t, "good.md", `# Good
+
+## Note: OK
+
+A valid note
+`)
+		MustWriteFile(t, "bad.md", `---
+tags: [unclosed
+---
+
+# Bad
+`)
+
+		// A single malformed file stops the command by default
+		_, err := CurrentRepository().AddWithSummary(".")
+		require.Error(t, err)
+
+		CurrentConfig().KeepGoing = true
+		defer func() { CurrentConfig().KeepGoing = false }()
+
+		lintResult, err := CurrentRepository().Lint(nil, ".")
+		require.NoError(t, err)
+		require.Len(t, lintResult.FileErrors, 1)
+		assert.Equal(t, "bad.md", lintResult.FileErrors[0].RelativePath)
+		assert.Equal(t, 1, lintResult.AnalyzedFiles)
+
+		summary, err := CurrentRepository().AddWithSummary(".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"good.md"}, summary.Added)
+		require.Len(t, summary.FileErrors, 1)
+		assert.Equal(t, "bad.md", summary.FileErrors[0].RelativePath)
+		assert.ErrorContains(t, summary.FileErrors, "1 files cannot be processed:\n  bad.md: ")
+
+		file, err := CurrentRepository().FindFileByRelativePath("good.md")
+		require.NoError(t, err)
+		assert.NotNil(t, file)
+	})
+
+	t.Run("No verify", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+		CurrentConfig().LintFile.Rules = []ConfigLintRule{
+			{Name: "no-extension-wikilink"},
+		}
+
+		MustWriteFile(t, "go.md", `# Go
+
+## Note: Links
+
+See [[python.md]].
+`)
+		MustWriteFile(t, "python.md", `# Python
+`)
+
+		// Violations stop the command by default
+		_, err := CurrentRepository().AddWithSummary(".")
+		require.ErrorContains(t, err, "1 linter errors detected")
+
+		CurrentConfig().NoVerify = true
+		defer func() { CurrentConfig().NoVerify = false }()
+
+		summary, err := CurrentRepository().AddWithSummary(".")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"go.md", "python.md"}, summary.Added)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		CurrentConfig().DryRun = true
+		summary, err := CurrentRepository().AddWithSummary(".")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"go.md", "medias/go.svg"}, summary.Added)
+		assert.Empty(t, summary.Modified)
+		assert.Empty(t, summary.Deleted)
+		assert.Equal(t, 2, summary.Count())
+
+		// Check nothing was persisted
+		assert.Equal(t, 0, CurrentDB().index.CountChanges())
+		assert.Equal(t, 0, ReadIndex().CountChanges())
+		file, err := CurrentRepository().FindFileByRelativePath("go.md")
+		require.NoError(t, err)
+		assert.Nil(t, file)
+
+		// Check the same changes are staged without dry-run
+		CurrentConfig().DryRun = false
+		summary, err = CurrentRepository().AddWithSummary(".")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"go.md", "medias/go.svg"}, summary.Added)
+		assert.Greater(t, ReadIndex().CountChanges(), 0)
+	})
+
 }
 
 func TestCommandReset(t *testing.T) {
@@ -320,6 +482,142 @@ func TestCommandReset(t *testing.T) {
 
 }
 
+func TestCommandRestore(t *testing.T) {
+
+	t.Run("Staged", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		err := os.WriteFile(filepath.Join(root, "python.md"), []byte(`# Python
+
+## Flashcard: Python's creator
+
+Who invented Python?
+---
+Guido van Rossum
+`), 0644)
+		require.NoError(t, err)
+
+		err = CurrentRepository().Add(".")
+		require.NoError(t, err)
+		changesBefore := ReadIndex().CountChanges()
+
+		// Unstage a single file
+		err = CurrentRepository().Restore([]string{"python.md"}, true)
+		require.NoError(t, err)
+
+		// Check only objects of the restored file were removed from the staging area
+		idx := ReadIndex()
+		assert.Less(t, idx.CountChanges(), changesBefore)
+		assert.Greater(t, idx.CountChanges(), 0)
+		_, ok := idx.StagingArea.ContainsFile("python.md")
+		assert.False(t, ok)
+		_, ok = idx.StagingArea.ContainsFile("go.md")
+		assert.True(t, ok)
+
+		// Check database
+		file, err := CurrentRepository().FindFileByRelativePath("python.md")
+		require.NoError(t, err)
+		assert.Nil(t, file)
+		file, err = CurrentRepository().FindFileByRelativePath("go.md")
+		require.NoError(t, err)
+		assert.NotNil(t, file)
+	})
+
+	t.Run("Working tree", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		err := CurrentRepository().Restore([]string{"go.md"}, false)
+		assert.ErrorContains(t, err, "not supported")
+	})
+
+}
+
+func TestCommandRemove(t *testing.T) {
+
+	t.Run("Default", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		MustWriteFile(t, "python.md", `# Python
+
+## Note: Creator
+
+Guido van Rossum
+`)
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+
+		err = CurrentRepository().Remove([]string{"python.md"}, false)
+		require.NoError(t, err)
+
+		// Check the file is deleted
+		assert.NoFileExists(t, filepath.Join(root, "python.md"))
+		assert.FileExists(t, filepath.Join(root, "go.md"))
+
+		// Check deletions are staged
+		idx := ReadIndex()
+		obj, ok := idx.StagingArea.ContainsFile("python.md")
+		require.True(t, ok)
+		assert.Equal(t, Deleted, obj.State)
+		_, ok = idx.StagingArea.ContainsFile("go.md")
+		assert.False(t, ok)
+
+		// Check database
+		file, err := CurrentRepository().FindFileByRelativePath("python.md")
+		require.NoError(t, err)
+		assert.Nil(t, file)
+		notes, err := CurrentRepository().FindNotesByFileOID(obj.OID)
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+
+		err = CurrentDB().Commit("remove python.md")
+		require.NoError(t, err)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+
+		err = CurrentRepository().Remove([]string{"go.md"}, true)
+		require.NoError(t, err)
+
+		// Check the file is still present
+		assert.FileExists(t, filepath.Join(root, "go.md"))
+		file, err := CurrentRepository().FindFileByRelativePath("go.md")
+		require.NoError(t, err)
+		assert.Nil(t, file)
+		obj, ok := ReadIndex().StagingArea.ContainsFile("go.md")
+		require.True(t, ok)
+		assert.Equal(t, Deleted, obj.State)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+
+		err = CurrentRepository().Remove([]string{"unknown.md"}, false)
+		assert.ErrorContains(t, err, "did not match any files")
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+
+		// "go" must not match "go.md"
+		err = CurrentRepository().Remove([]string{"go"}, false)
+		assert.ErrorContains(t, err, "did not match any files")
+		assert.FileExists(t, filepath.Join(root, "go.md"))
+		file, err := CurrentRepository().FindFileByRelativePath("go.md")
+		require.NoError(t, err)
+		assert.NotNil(t, file)
+	})
+
+}
+
 func TestCommandCommit(t *testing.T) {
 
 	t.Run("Basic", func(t *testing.T) {
@@ -360,6 +658,72 @@ Guido van Rossum
 		require.NotEqual(t, refBefore, refAfter)
 	})
 
+	t.Run("Author", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		// No author by default
+		err := CurrentRepository().Add("go.md")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+		assert.Nil(t, CurrentDB().Head().Author)
+
+		CurrentConfig().ConfigFile.Core.Author = "Julien"
+		CurrentConfig().ConfigFile.Core.AuthorHost = "laptop"
+
+		MustWriteFile(t, "python.md", `# Python
+
+## Note: Zen
+
+Simple is better than complex.
+`)
+		err = CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("second commit")
+		require.NoError(t, err)
+		require.NotNil(t, CurrentDB().Head().Author)
+		assert.Equal(t, "Julien (laptop)", CurrentDB().Head().Author.String())
+
+		// Author must be persisted in the commit graph
+		Reset()
+		log := CurrentDB().Log()
+		require.Len(t, log, 2)
+		require.NotNil(t, log[0].Author)
+		assert.Equal(t, "Julien", log[0].Author.Name)
+		assert.Equal(t, "laptop", log[0].Author.Host)
+		assert.Nil(t, log[1].Author)
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		// Messages are optional
+		err := CurrentRepository().Add("go.md")
+		require.NoError(t, err)
+		err = CurrentRepository().CommitWithMessage("")
+		require.NoError(t, err)
+		assert.Empty(t, CurrentDB().Head().Message)
+
+		MustWriteFile(t, "python.md", `# Python
+
+## Note: Zen
+
+Simple is better than complex.
+`)
+		err = CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentRepository().CommitWithMessage("  Add Python notes\n")
+		require.NoError(t, err)
+		assert.Equal(t, "Add Python notes", CurrentDB().Head().Message)
+
+		// Message must be persisted in the commit graph
+		Reset()
+		log := CurrentDB().Log()
+		require.Len(t, log, 2)
+		assert.Equal(t, "Add Python notes", log[0].Message)
+		assert.Empty(t, log[1].Message)
+	})
+
 }
 
 func TestCommandPushPull(t *testing.T) {
@@ -409,6 +773,58 @@ func TestCommandPushPull(t *testing.T) {
 		require.FileExists(t, filepath.Join(root, ".nt/objects/info/commit-graph"))
 	})
 
+	t.Run("Mirrors", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		// Configure origin and a mirror
+		origin := t.TempDir()
+		mirror := t.TempDir()
+		CurrentConfig().ConfigFile.Remote = ConfigRemote{
+			Type: "fs",
+			Dir:  origin,
+		}
+		CurrentConfig().ConfigFile.Remotes = map[string]*ConfigRemote{
+			"backup": {
+				Type: "fs",
+				Dir:  mirror,
+			},
+		}
+		assert.Equal(t, []string{"origin", "backup"}, CurrentConfig().ConfigFile.RemoteNames())
+
+		// Push to the mirror only
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+		err = CurrentDB().PushRemote("backup")
+		require.NoError(t, err)
+		head, ok := CurrentDB().Ref("backup")
+		require.True(t, ok)
+		require.FileExists(t, filepath.Join(mirror, "info/commit-graph"))
+		require.NoFileExists(t, filepath.Join(origin, "info/commit-graph"))
+		_, ok = CurrentDB().Ref("origin")
+		require.False(t, ok)
+
+		// Unknown remote
+		err = CurrentDB().PushRemote("unknown")
+		require.ErrorContains(t, err, "no remote found")
+
+		Reset()
+
+		// Pull from the mirror in a new repository
+		root := SetUpRepositoryFromTempDir(t)
+		CurrentConfig().ConfigFile.Remotes = map[string]*ConfigRemote{
+			"backup": {
+				Type: "fs",
+				Dir:  mirror,
+			},
+		}
+		err = CurrentDB().PullRemote("backup", PullStrategyManual)
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(root, ".nt/objects/info/commit-graph"))
+		_, ok = CurrentDB().ReadCommit(head)
+		require.True(t, ok)
+	})
+
 	t.Run("Pull before push", func(t *testing.T) {
 		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
 		// Configure origin
@@ -505,6 +921,116 @@ Guido van Rossum
 		assert.Empty(t, originIndex.StagingArea) // Must not include non-committed changes
 	})
 
+	t.Run("Conflicts", func(t *testing.T) {
+		goContent := func(history string) string {
+			return `---
+tags:
+- go
+---
+
+# Go
+
+## Reference: Golang History
+
+` + history + `
+
+## TODO: Conferences
+
+* [Gophercon Europe](https://gophercon.eu/) ` + "`#reminder-2023-06-26`" + `
+`
+		}
+
+		// Diverge two repositories sharing the same origin
+		setUp := func(t *testing.T) {
+			root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+			origin := t.TempDir()
+			CurrentConfig().ConfigFile.Remote = ConfigRemote{
+				Type: "fs",
+				Dir:  origin,
+			}
+			require.NoError(t, CurrentRepository().Add("."))
+			require.NoError(t, CurrentDB().Commit("initial commit"))
+			require.NoError(t, CurrentDB().Push())
+
+			Reset()
+
+			// Edit go.md and create python.md in another repository
+			SetUpRepositoryFromTempDir(t)
+			CurrentConfig().ConfigFile.Remote = ConfigRemote{
+				Type: "fs",
+				Dir:  origin,
+			}
+			require.NoError(t, CurrentDB().Pull())
+			MustWriteFile(t, "go.md", goContent("Edited remotely"))
+			MustWriteFile(t, "python.md", "# Python\n\n## Note: Creator\n\nGuido van Rossum\n")
+			require.NoError(t, CurrentRepository().Add("."))
+			require.NoError(t, CurrentDB().Commit("remote commit"))
+			require.NoError(t, CurrentDB().Push())
+
+			Reset()
+
+			// Edit go.md in the first repository
+			configureDir(t, root)
+			CurrentConfig().ConfigFile.Remote = ConfigRemote{
+				Type: "fs",
+				Dir:  origin,
+			}
+			MustWriteFile(t, "go.md", goContent("Edited locally"))
+			require.NoError(t, CurrentRepository().Add("."))
+			require.NoError(t, CurrentDB().Commit("local commit"))
+		}
+
+		t.Run("Manual", func(t *testing.T) {
+			setUp(t)
+
+			err := CurrentDB().Pull()
+			var conflictErr *PullConflictError
+			require.ErrorAs(t, err, &conflictErr)
+			assert.Equal(t, []string{"go.md"}, conflictErr.RelativePaths)
+
+			// Nothing was pulled
+			file, err := CurrentRepository().FindFileByRelativePath("python.md")
+			require.NoError(t, err)
+			assert.Nil(t, file)
+		})
+
+		t.Run("Ours", func(t *testing.T) {
+			setUp(t)
+
+			err := CurrentDB().PullWithStrategy(PullStrategyOurs)
+			require.NoError(t, err)
+
+			// Non-conflicting files are merged
+			file, err := CurrentRepository().FindFileByRelativePath("python.md")
+			require.NoError(t, err)
+			assert.NotNil(t, file)
+			note := MustFindNoteByPathAndTitle(t, "go.md", "Reference: Golang History")
+			assert.Equal(t, "Edited locally", note.ContentRaw)
+
+			// Local commits can now be pushed
+			require.NoError(t, CurrentDB().Push())
+		})
+
+		t.Run("Theirs", func(t *testing.T) {
+			setUp(t)
+
+			err := CurrentDB().PullWithStrategy(PullStrategyTheirs)
+			require.NoError(t, err)
+
+			file, err := CurrentRepository().FindFileByRelativePath("python.md")
+			require.NoError(t, err)
+			assert.NotNil(t, file)
+			note := MustFindNoteByPathAndTitle(t, "go.md", "Reference: Golang History")
+			assert.Equal(t, "Edited remotely", note.ContentRaw)
+		})
+
+		t.Run("Unknown strategy", func(t *testing.T) {
+			setUp(t)
+
+			err := CurrentDB().PullWithStrategy("unknown")
+			require.ErrorContains(t, err, `unsupported pull strategy "unknown"`)
+		})
+	})
 }
 
 func TestCommandStatus(t *testing.T) {
@@ -694,6 +1220,65 @@ A **gopher**.
 		require.FileExists(t, filepath.Join(origin, OIDToPath(logoModifiedBlob.OID)))
 	})
 
+	t.Run("Aggressive", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+		logo, err := CurrentRepository().FindMediaByRelativePath("medias/go.svg")
+		require.NoError(t, err)
+		require.NotNil(t, logo)
+		head := CurrentDB().Head()
+		require.NotNil(t, head)
+
+		// Simulate leftovers from an interrupted command
+		strayPackFile := NewPackFileWithOID("a000000000000000000000000000000000000001")
+		require.NoError(t, strayPackFile.Save())
+		strayBlobOID := "a000000000000000000000000000000000000002"
+		require.NoError(t, CurrentDB().WriteBlob(strayBlobOID, []byte("stray")))
+
+		// Run "nt gc --aggressive --dry-run"
+		CurrentConfig().DryRun = true
+		result, err := CurrentDB().GCAggressive()
+		require.NoError(t, err)
+		assert.Equal(t, []string{strayPackFile.OID}, result.PackFiles)
+		assert.Equal(t, []string{strayBlobOID}, result.Blobs)
+		assert.Greater(t, result.ReclaimedBytes, int64(0))
+		require.FileExists(t, filepath.Join(root, ".nt/objects/", OIDToPath(strayPackFile.OID)))
+		require.FileExists(t, filepath.Join(root, ".nt/objects/", OIDToPath(strayBlobOID)))
+
+		// Run "nt gc --aggressive"
+		CurrentConfig().DryRun = false
+		result, err = CurrentDB().GCAggressive()
+		require.NoError(t, err)
+		assert.Equal(t, []string{strayPackFile.OID}, result.PackFiles)
+		assert.Equal(t, []string{strayBlobOID}, result.Blobs)
+		require.NoFileExists(t, filepath.Join(root, ".nt/objects/", OIDToPath(strayPackFile.OID)))
+		require.NoFileExists(t, filepath.Join(root, ".nt/objects/", OIDToPath(strayBlobOID)))
+
+		// Reachable objects must still exist
+		for _, blob := range logo.BlobRefs {
+			require.FileExists(t, filepath.Join(root, ".nt/objects/", OIDToPath(blob.OID)))
+		}
+		for _, packFileRef := range head.PackFiles {
+			packFile, err := CurrentDB().ReadPackFile(packFileRef.OID)
+			require.NoError(t, err)
+			// Objects must still be readable after recompression
+			for _, packObject := range packFile.PackObjects {
+				require.NotNil(t, packObject.ReadObject())
+			}
+		}
+
+		// Nothing more to reclaim
+		result, err = CurrentDB().GCAggressive()
+		require.NoError(t, err)
+		assert.Empty(t, result.PackFiles)
+		assert.Empty(t, result.Blobs)
+		assert.Empty(t, result.RecompressedPackFiles)
+	})
+
 	t.Run("Edit PackFiles", func(t *testing.T) {
 		root := SetUpRepositoryFromTempDir(t)
 
@@ -994,6 +1579,88 @@ func TestCommandDiff(t *testing.T) {
 		assert.Equal(t, expected, diff) // TODO BUG the deleted flashcard is missing
 	})
 
+	t.Run("Remote", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		_, err := CurrentRepository().DiffRemote(nil)
+		require.ErrorContains(t, err, "no remote found")
+
+		// Configure origin
+		origin := t.TempDir()
+		CurrentConfig().ConfigFile.Remote = ConfigRemote{
+			Type: "fs",
+			Dir:  origin,
+		}
+
+		// Step 1: Nothing pushed yet
+
+		err = CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+
+		diff, err := CurrentRepository().DiffRemote(nil)
+		require.NoError(t, err)
+		assert.Contains(t, diff, "@@ -0,0 +1,7 @@\n+What does the **Golang logo** represent?\n")
+
+		// Step 2: Push
+
+		err = CurrentDB().Push()
+		require.NoError(t, err)
+
+		diff, err = CurrentRepository().DiffRemote(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "", diff)
+
+		// Step 3: Commit a new version of the file
+
+		err = os.WriteFile(filepath.Join(root, "go.md"), []byte(`
+# Go
+
+## Reference: Golang History
+
+[Golang](https://go.dev/doc/ "#go/go") was designed by Robert Greisemer, Rob Pike, and Ken Thompson at Google in 2007.
+
+
+## TODO: Conferences
+
+* [Gophercon Europe](https://gophercon.eu/) `+"`#reminder-2023-06-26`"+`
+`), 0644)
+		require.NoError(t, err)
+		err = CurrentRepository().Add(".")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("second commit")
+		require.NoError(t, err)
+
+		diff, err = CurrentRepository().DiffRemote(nil)
+		require.NoError(t, err)
+		expected := "--- a/go.md\n" +
+			"+++ b/go.md\n" +
+			"@@ -1,5 +1,1 @@\n" +
+			"-`#history`\n" +
+			"-\n" +
+			"-`@source: https://en.wikipedia.org/wiki/Go_(programming_language)`\n" +
+			"-\n" +
+			" [Golang](https://go.dev/doc/ \"#go/go\") was designed by Robert Greisemer, Rob Pike, and Ken Thompson at Google in 2007.\n" +
+			"\\ No newline at end of file\n" +
+			"--- a/go.md\n" +
+			"+++ b/go.md\n" +
+			"@@ -1,7 +0,0 @@\n" +
+			"-What does the **Golang logo** represent?\n" +
+			"-\n" +
+			"----\n" +
+			"-\n" +
+			"-A **gopher**.\n" +
+			"-\n" +
+			"-![Logo](./medias/go.svg)\n" +
+			"\\ No newline at end of file\n"
+		assert.Equal(t, expected, diff)
+
+		// Only paths matching are compared
+		diff, err = CurrentRepository().DiffRemote([]string{"python.md"})
+		require.NoError(t, err)
+		assert.Equal(t, "", diff)
+	})
 }
 
 /* Learning Tests */
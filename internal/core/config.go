@@ -2,13 +2,17 @@ package core
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/julien-sobczak/the-notewriter/internal/medias"
 	"github.com/julien-sobczak/the-notewriter/internal/reference"
@@ -32,6 +36,9 @@ const (
 	DefaultSRSEaseFactor  = 2.5
 )
 
+// List of supported commands to convert medias
+var SupportedConverters = []string{"ffmpeg", "svgo", "random"}
+
 // Default .nt/config content
 const DefaultConfig = `
 [core]
@@ -55,6 +62,7 @@ const DefaultGitIgnore = `
 /objects/
 /index
 /refs/
+/export-state
 `
 
 // Default .ntignore content
@@ -102,21 +110,57 @@ type ConfigFile struct {
 	Core      ConfigCore
 	Medias    ConfigMedias
 	Remote    ConfigRemote
+	Remotes   map[string]*ConfigRemote // Additional remotes by name (ex: [remotes.backup])
 	Deck      map[string]*ConfigDeck
 	Search    map[string]*ConfigSearch
 	Reference map[string]*ConfigReference
+	Templates map[string]string // Ex: todo = "# {{index . \"title\"}}\n"
+	Hooks     map[string]string // Ex: gist = "python3 scripts/gist.py"
 }
 type ConfigCore struct {
 	Extensions            []string
 	MaxObjectsPerPackFile int
+	Compression           string // none, fast, default, or best
+	// Components used to generate note slugs: path, title, or path+title (default)
+	SlugStrategy string `toml:"slug_strategy"`
+	// Append a numeric suffix (ex: go-2) when a generated note slug is already used
+	SlugDisambiguation bool `toml:"slug_disambiguation"`
+	// Walk symlinked directories and files (symlinks are ignored by default)
+	FollowSymlinks bool `toml:"follow_symlinks"`
+	// Ignore formatting-only changes (ex: reflowed paragraph) when hashing note contents
+	NormalizeHash bool `toml:"normalize_hash"`
+	// Name recorded in commits (ex: "Julien")
+	Author string `toml:"author"`
+	// Optional machine recorded in commits along the author name (ex: "laptop")
+	AuthorHost string `toml:"author_host"`
+	// Tokenizer of the full-text index: unicode61 (default), porter, or trigram (changes require nt reindex)
+	SearchTokenizer string `toml:"search_tokenizer"`
+	// IANA time zone used for reminders and dates (ex: "Europe/Paris"). Local time by default.
+	Timezone string `toml:"timezone"`
+	// Formatting of note long titles (ex: "Go / History")
+	LongTitle ConfigLongTitle `toml:"long_title"`
+}
+type ConfigLongTitle struct {
+	// Separator between titles (default: " / ")
+	Separator string `toml:"separator"`
+	// Maximum number of titles to keep, starting from the note title (0 = unlimited)
+	MaxSegments int `toml:"max_segments"`
+	// Include the file title as the first title (default: true)
+	IncludeFileTitle *bool `toml:"include_file_title"`
 }
 type ConfigMedias struct {
 	Command  string
 	Parallel int
 	Preset   string
+	// Optional command to use per media kind (ex: picture = "random")
+	Converters map[string]string
+	// Optional media kind per file extension, overriding the built-in detection (ex: mkv = "video")
+	Kinds map[string]string
+	// Optional URL of the image used for dangling medias when exporting (ex: "https://example.com/404.svg")
+	MissingURL string `toml:"missing_url"`
 }
 type ConfigRemote struct {
-	Type string // fs or s3
+	Type string // fs, s3, storj, or rclone
 	// fs-specific attributes
 	Dir string
 	// s3-specific attributes
@@ -128,7 +172,43 @@ type ConfigRemote struct {
 	// Storj-specific attributes
 	AccessGrant string
 	// + reuse BucketName
+	// rclone-specific attributes
+	Path string // Ex: "mydrive:notes"
+}
+
+// Name of the remote declared in the section [remote]
+const DefaultRemoteName = "origin"
+
+// RemoteNames returns the names of all configured remotes, starting with the default remote.
+func (c *ConfigFile) RemoteNames() []string {
+	var names []string
+	if c.Remote.Type != "" {
+		names = append(names, DefaultRemoteName)
+	}
+	var otherNames []string
+	for name := range c.Remotes {
+		if name == DefaultRemoteName {
+			// Invalid configuration (see Validate)
+			continue
+		}
+		otherNames = append(otherNames, name)
+	}
+	slices.Sort(otherNames)
+	return append(names, otherNames...)
+}
+
+// RemoteByName returns the settings of a configured remote. An empty name means the default remote.
+func (c *ConfigFile) RemoteByName(name string) (ConfigRemote, bool) {
+	if name == "" || name == DefaultRemoteName {
+		return c.Remote, c.Remote.Type != ""
+	}
+	remote, ok := c.Remotes[name]
+	if !ok {
+		return ConfigRemote{}, false
+	}
+	return *remote, true
 }
+
 type ConfigDeck struct {
 	Name  string
 	Query string
@@ -149,6 +229,17 @@ type ConfigReference struct {
 	Manager  string // Ex: "zotero"
 	Path     string // Ex: "references/books"
 	Template string // Ex: "# {{.Title}}\n"
+	APIKey   string // Ex: "xxx" (required by some managers like "tmdb")
+	CacheTTL string // Ex: "720h" (default to 30 days)
+}
+
+// CacheDuration returns the TTL of cached search results for this reference.
+func (r *ConfigReference) CacheDuration() time.Duration {
+	ttl, err := time.ParseDuration(r.CacheTTL)
+	if err != nil {
+		return reference.DefaultCacheTTL
+	}
+	return ttl
 }
 
 // SetParallel overrides the value in config file.
@@ -167,6 +258,20 @@ func (f *ConfigFile) SupportExtension(path string) bool {
 	return false
 }
 
+// MediaKindFor returns the media kind configured for the extension of the given file.
+func (f *ConfigFile) MediaKindFor(path string) (MediaKind, bool) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".") // ".mkv" => "mkv"
+	if ext == "" {
+		return KindUnknown, false
+	}
+	for extension, kind := range f.Medias.Kinds {
+		if strings.EqualFold(strings.TrimPrefix(extension, "."), ext) { // case-insensitive
+			return MediaKind(kind), true
+		}
+	}
+	return KindUnknown, false
+}
+
 // ConfigureFSRemote defines a local remote using the file system.
 func (f *ConfigFile) ConfigureFSRemote(dir string) *ConfigFile {
 	f.Remote = ConfigRemote{
@@ -189,14 +294,54 @@ func (f *ConfigFile) ConfigureS3Remote(bucketName, accessKey, secretKey string)
 
 type IgnoreFile struct {
 	Entries GlobPaths
+	// Entries of .ntignore files present in subdirectories indexed by their directory relative path.
+	// Entries are relative to this directory.
+	Nested map[string]GlobPaths
 }
 
+// AddNested registers the content of a .ntignore file present in a subdirectory.
+func (i *IgnoreFile) AddNested(dirRelativePath string, content string) error {
+	nestedFile, err := parseIgnoreFile(content)
+	if err != nil {
+		return err
+	}
+	if i.Nested == nil {
+		i.Nested = make(map[string]GlobPaths)
+	}
+	i.Nested[strings.Trim(filepath.ToSlash(dirRelativePath), "/")] = nestedFile.Entries
+	return nil
+}
+
+// MustExcludeFile returns true if the path is excluded by the root ignore file or
+// by an ignore file present in one of its parent directories.
 func (i *IgnoreFile) MustExcludeFile(path string, dir bool) bool {
-	path = strings.Trim(path, "/")
+	path = strings.Trim(filepath.ToSlash(path), "/")
+	suffix := ""
 	if dir {
-		path += "/"
+		suffix = "/"
+	}
+	if i.Entries.Match(path + suffix) {
+		return true
 	}
-	return i.Entries.Match(path)
+	for nestedDir, entries := range i.Nested {
+		if relativePath, ok := strings.CutPrefix(path, nestedDir+"/"); ok && entries.Match(relativePath+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Negated returns true if at least one entry is negated in any ignore file.
+func (i *IgnoreFile) Negated() bool {
+	if i.Entries.Negated() {
+		return true
+	}
+	for _, entries := range i.Nested {
+		if entries.Negated() {
+			return true
+		}
+	}
+	return false
 }
 
 type GlobPath string
@@ -256,6 +401,16 @@ func (g GlobPath) Match(path string) bool {
 
 type GlobPaths []GlobPath
 
+// Negated returns true if at least one entry is negated.
+func (g GlobPaths) Negated() bool {
+	for _, entry := range g {
+		if entry.Negate() {
+			return true
+		}
+	}
+	return false
+}
+
 // Match tests if a file path satisfies the conditions.
 func (g GlobPaths) Match(path string) bool {
 	foundMatch := false
@@ -272,10 +427,33 @@ func (g GlobPaths) Match(path string) bool {
 	return foundMatch
 }
 
+// Include tests if a file path is selected by a list of includes.
+// A path is selected when it matches at least one entry and no negated entry.
+// When all entries are negated, all other paths are selected (ex: ["!drafts/"]).
+func (g GlobPaths) Include(path string) bool {
+	if len(g) == 0 {
+		return true
+	}
+	for _, entry := range g {
+		if !entry.Negate() {
+			return g.Match(path)
+		}
+	}
+	for _, entry := range g {
+		if entry.Match(path) {
+			return false
+		}
+	}
+	return true
+}
+
 type LintFile struct {
 	Rules []ConfigLintRule `yaml:"rules"`
 
 	Schemas []ConfigLintSchema `yaml:"schemas"`
+
+	// Expected headings in files (see rule "file-structure")
+	FileTypes []ConfigLintFileType `yaml:"file_types"`
 }
 
 type ConfigLintRule struct {
@@ -308,6 +486,40 @@ type ConfigLintSchemaAttribute struct {
 	Pattern  string   `yaml:"pattern"`
 	Required *bool    `yaml:"required"`
 	Inherit  *bool    `yaml:"inherit"`
+	// Maximum number of levels the attribute propagates down
+	// (0 = self only, 1 = direct children, etc.). Unlimited when nil.
+	InheritDepth *int `yaml:"inherit_depth"`
+	// Optional Go template to compute the attribute from other attributes (ex: "{{.author}}")
+	Value string `yaml:"value"`
+}
+
+type ConfigLintFileType struct {
+	// Name of the file type used when reporting violations.
+	Name string `yaml:"name"`
+	// Restriction on the file path
+	Path     string               `yaml:"path"`
+	Headings []*ConfigLintHeading `yaml:"headings"`
+}
+
+type ConfigLintHeading struct {
+	// Optional note kind of the heading (ex: note, free)
+	Kind string `yaml:"kind"`
+	// Regex matching the heading title without the kind prefix. Match any heading when empty.
+	Match         string               `yaml:"match"`
+	Required      bool                 `yaml:"required"`
+	AllowMultiple bool                 `yaml:"allow_multiple"`
+	Children      []*ConfigLintHeading `yaml:"children"`
+}
+
+func (h ConfigLintHeading) String() string {
+	description := "heading"
+	if h.Kind != "" {
+		description = h.Kind + " heading"
+	}
+	if h.Match != "" {
+		description += fmt.Sprintf(" matching %q", h.Match)
+	}
+	return description
 }
 
 func (a ConfigLintSchemaAttribute) String() string {
@@ -324,6 +536,12 @@ func (a ConfigLintSchemaAttribute) String() string {
 	if *a.Inherit {
 		specs = append(specs, "inherit")
 	}
+	if a.InheritDepth != nil {
+		specs = append(specs, fmt.Sprintf("inherit_depth=%d", *a.InheritDepth))
+	}
+	if a.Value != "" {
+		specs = append(specs, fmt.Sprintf("value=%s", a.Value))
+	}
 	return strings.Join(specs, ",")
 }
 
@@ -356,11 +574,14 @@ func (l *LintFile) IsInheritableAttribute(attributeName string, filePath string)
 // Severity returns the severity of a lint rule.
 func (l *LintFile) Severity(name string) string {
 	for _, rule := range l.Rules {
-		if rule.Name == name {
+		if rule.Name == name && rule.Severity != "" {
 			return rule.Severity
 		}
 	}
-	return "error" // must not happen but default to error
+	if definition, ok := LintRules[name]; ok && definition.Severity != "" {
+		return definition.Severity
+	}
+	return "error" // default to error
 }
 
 // GetAttributeDefinition returns the attribute definition to use.
@@ -382,15 +603,15 @@ func (l *LintFile) GetAttributeDefinition(name string, filter func(schema Config
 	//
 	// We must use the second schema when both apply.
 
-	var matchingSchemas []ConfigLintSchema
-	for _, schema := range l.Schemas {
+	var matchingSchemas []declaredSchema
+	for i, schema := range l.Schemas {
 		if !filter(schema) {
 			continue
 		}
 		if slices.ContainsFunc(schema.Attributes, func(a *ConfigLintSchemaAttribute) bool {
 			return a.Name == name
 		}) {
-			matchingSchemas = append(matchingSchemas, schema)
+			matchingSchemas = append(matchingSchemas, declaredSchema{ConfigLintSchema: schema, Order: i})
 		}
 	}
 	if len(matchingSchemas) == 0 {
@@ -399,12 +620,8 @@ func (l *LintFile) GetAttributeDefinition(name string, filter func(schema Config
 	}
 
 	// Sort from most specific to least specific
-	slices.SortFunc(matchingSchemas, func(a, b ConfigLintSchema) bool {
-		// Most specific path first
-		if a.Path != b.Path {
-			return strings.HasPrefix(a.Path, b.Path)
-		}
-		return false // The last must win but SortFunc is not stable...
+	slices.SortFunc(matchingSchemas, func(a, b declaredSchema) bool {
+		return compareSchemaSpecificity(a, b) < 0
 	})
 
 	schemaToUse := matchingSchemas[0]
@@ -437,6 +654,18 @@ type Config struct {
 
 	// Toggle this flag to skip some side-effects
 	DryRun bool
+
+	// Toggle this flag to continue processing other files when a file cannot be parsed
+	KeepGoing bool
+
+	// Toggle this flag to commit even when hooks fail
+	IgnoreHookErrors bool
+
+	// Toggle this flag to stage files without checking linter rules first
+	NoVerify bool
+
+	// Toggle this flag to reuse the last-seen remote index when unchanged (see .nt/remote-index-cache)
+	RemoteIndexCache bool
 }
 
 func CurrentConfig() *Config {
@@ -455,6 +684,19 @@ func CurrentConfig() *Config {
 	return configSingleton
 }
 
+// Location returns the time zone used for reminders and dates.
+func (c *Config) Location() *time.Location {
+	if c.ConfigFile.Core.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(c.ConfigFile.Core.Timezone)
+	if err != nil {
+		// Already reported by Check()
+		return time.Local
+	}
+	return loc
+}
+
 // TempDir returns the privileged temporary directory to use when generating temporary files.
 func (c *Config) TempDir() string {
 	if c.tempDir == "" {
@@ -470,12 +712,24 @@ func (c *Config) TempDir() string {
 
 // Converter returns the convertor to use when creating blobs from media files.
 func (c *Config) Converter() medias.Converter {
-	mediaConfig := c.ConfigFile.Medias
-	switch mediaConfig.Command {
+	return c.newConverter(c.ConfigFile.Medias.Command)
+}
+
+// ConverterFor returns the convertor to use for a given media kind.
+// Fallback to the default convertor when no specific command is configured.
+func (c *Config) ConverterFor(kind MediaKind) medias.Converter {
+	if command, ok := c.ConfigFile.Medias.Converters[string(kind)]; ok {
+		return c.newConverter(command)
+	}
+	return c.Converter()
+}
+
+func (c *Config) newConverter(command string) medias.Converter {
+	switch command {
 	case "":
 		fallthrough
 	case "ffmpeg":
-		preset := mediaConfig.Preset
+		preset := c.ConfigFile.Medias.Preset
 		converter, err := medias.NewFFmpegConverter(preset)
 		if err != nil {
 			log.Fatal(err)
@@ -484,10 +738,16 @@ func (c *Config) Converter() medias.Converter {
 			CurrentLogger().Debugf("Running command %q", cmd+" "+strings.Join(args, " "))
 		})
 		return converter
+	case "svgo":
+		converter := medias.NewSvgoConverter()
+		converter.OnPreGeneration(func(cmd string, args ...string) {
+			CurrentLogger().Debugf("Running command %q", cmd+" "+strings.Join(args, " "))
+		})
+		return converter
 	case "random":
 		return medias.NewRandomConverter()
 	}
-	log.Fatalf("Unsupported converter %q", c.ConfigFile.Medias.Command)
+	log.Fatalf("Unsupported converter %q", command)
 	return nil
 }
 
@@ -764,6 +1024,50 @@ func InitConfigFromDirectory(path string) (*Config, error) {
 
 func (c *Config) Check() error {
 
+	// Check for invalid compression
+	if !slices.Contains([]string{"", CompressionNone, CompressionFast, CompressionDefault, CompressionBest}, c.ConfigFile.Core.Compression) {
+		return fmt.Errorf("unsupported compression %q", c.ConfigFile.Core.Compression)
+	}
+
+	// Check for invalid slug strategy
+	if !slices.Contains([]string{"", SlugStrategyPath, SlugStrategyTitle, SlugStrategyPathTitle}, c.ConfigFile.Core.SlugStrategy) {
+		return fmt.Errorf("unsupported slug strategy %q", c.ConfigFile.Core.SlugStrategy)
+	}
+
+	// Check for invalid search tokenizer
+	if !slices.Contains([]string{"", FTSTokenizerUnicode61, FTSTokenizerPorter, FTSTokenizerTrigram}, c.ConfigFile.Core.SearchTokenizer) {
+		return fmt.Errorf("unsupported search tokenizer %q", c.ConfigFile.Core.SearchTokenizer)
+	}
+
+	// Check for invalid long title settings
+	if c.ConfigFile.Core.LongTitle.MaxSegments < 0 {
+		return fmt.Errorf("invalid long title max segments %d", c.ConfigFile.Core.LongTitle.MaxSegments)
+	}
+
+	// Check for invalid time zone
+	if c.ConfigFile.Core.Timezone != "" {
+		if _, err := time.LoadLocation(c.ConfigFile.Core.Timezone); err != nil {
+			return fmt.Errorf("unsupported timezone %q", c.ConfigFile.Core.Timezone)
+		}
+	}
+
+	// Check for invalid converters
+	for kind, command := range c.ConfigFile.Medias.Converters {
+		if !slices.Contains([]MediaKind{KindAudio, KindPicture, KindVideo, KindDocument, KindUnknown}, MediaKind(kind)) {
+			return fmt.Errorf("unknown media kind %q for converter", kind)
+		}
+		if !slices.Contains(SupportedConverters, command) {
+			return fmt.Errorf("unsupported converter %q for media kind %q", command, kind)
+		}
+	}
+
+	// Check for invalid media kinds
+	for extension, kind := range c.ConfigFile.Medias.Kinds {
+		if !slices.Contains([]MediaKind{KindAudio, KindPicture, KindVideo, KindDocument, KindUnknown}, MediaKind(kind)) {
+			return fmt.Errorf("unknown media kind %q for extension %q", kind, extension)
+		}
+	}
+
 	// Check for invalid reference templates
 	for key, referenceConfig := range c.ConfigFile.Reference {
 		// Only path and template supports Go Templating
@@ -775,6 +1079,22 @@ func (c *Config) Check() error {
 		if err != nil {
 			return fmt.Errorf("invalid template for reference %q: %w", key, err)
 		}
+		if referenceConfig.CacheTTL != "" {
+			if _, err := time.ParseDuration(referenceConfig.CacheTTL); err != nil {
+				return fmt.Errorf("invalid cache TTL for reference %q: %w", key, err)
+			}
+		}
+	}
+
+	// Check for invalid note templates
+	for kind, templateText := range c.ConfigFile.Templates {
+		if _, ok := noteKindTitles[NoteKind(kind)]; !ok {
+			return fmt.Errorf("unsupported kind %q for template", kind)
+		}
+		_, err := reference.ParseTemplate(templateText)
+		if err != nil {
+			return fmt.Errorf("invalid template for kind %q: %w", kind, err)
+		}
 	}
 
 	// Check all rules are valid
@@ -801,6 +1121,13 @@ func (c *Config) Check() error {
 		}
 	}
 
+	// Check for invalid headings in file types
+	for _, fileType := range c.LintFile.FileTypes {
+		if err := checkLintHeadings(fileType.Name, fileType.Headings); err != nil {
+			return err
+		}
+	}
+
 	// Check for invalid patterns
 	for _, schema := range c.LintFile.Schemas {
 		for _, attribute := range schema.Attributes {
@@ -812,11 +1139,324 @@ func (c *Config) Check() error {
 		}
 	}
 
+	// Check for invalid computed values
+	for _, schema := range c.LintFile.Schemas {
+		for _, attribute := range schema.Attributes {
+			if attribute.Value == "" {
+				continue
+			}
+			if _, err := reference.ParseTemplate(attribute.Value); err != nil {
+				return fmt.Errorf("invalid value %q for attribute %q: %v", attribute.Value, attribute.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkLintHeadings validates the heading specifications of a file type.
+func checkLintHeadings(fileTypeName string, headings []*ConfigLintHeading) error {
+	for _, heading := range headings {
+		if _, ok := noteKindTitles[NoteKind(heading.Kind)]; !ok && heading.Kind != "" && NoteKind(heading.Kind) != KindFree {
+			return fmt.Errorf("unsupported kind %q in file type %q", heading.Kind, fileTypeName)
+		}
+		if _, err := regexp.Compile(heading.Match); err != nil {
+			return fmt.Errorf("invalid pattern %q in file type %q: %v", heading.Match, fileTypeName, err)
+		}
+		if err := checkLintHeadings(fileTypeName, heading.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the configuration like Check but reports all problems found.
+func (c *Config) Validate() []error {
+	var errs []error
+
+	if err := c.Check(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if !slices.Contains([]string{"", "fs", "s3", "storj", "rclone"}, c.ConfigFile.Remote.Type) {
+		errs = append(errs, fmt.Errorf("unsupported remote type %q (fs, s3, storj, or rclone)", c.ConfigFile.Remote.Type))
+	}
+	for name, remote := range c.ConfigFile.Remotes {
+		if name == DefaultRemoteName {
+			errs = append(errs, fmt.Errorf("remote name %q is reserved for the section [remote]", name))
+		}
+		if !slices.Contains([]string{"fs", "s3", "storj", "rclone"}, remote.Type) {
+			errs = append(errs, fmt.Errorf("unsupported type %q for remote %q (fs, s3, storj, or rclone)", remote.Type, name))
+		}
+	}
+	for name, deck := range c.ConfigFile.Deck {
+		if _, err := ParseQuery(deck.Query); err != nil {
+			errs = append(errs, fmt.Errorf("invalid query for deck %q: %w", name, err))
+		}
+	}
+	for name, search := range c.ConfigFile.Search {
+		if _, err := ParseQuery(search.Q); err != nil {
+			errs = append(errs, fmt.Errorf("invalid query for search %q: %w", name, err))
+		}
+	}
+
+	// Check schemas
+	for _, schema := range c.LintFile.Schemas {
+		for _, attribute := range schema.Attributes {
+			if attribute.Name == "" {
+				errs = append(errs, fmt.Errorf("missing attribute name in schema %q", schema.Name))
+			}
+			if !slices.Contains([]string{"", "array", "string", "object", "number", "boolean", "bool"}, attribute.Type) {
+				errs = append(errs, fmt.Errorf("unsupported type %q for attribute %q in schema %q (array, string, object, number, or boolean)", attribute.Type, attribute.Name, schema.Name))
+			}
+			if attribute.InheritDepth != nil && *attribute.InheritDepth < 0 {
+				errs = append(errs, fmt.Errorf("negative inherit_depth for attribute %q in schema %q", attribute.Name, schema.Name))
+			}
+		}
+	}
+
+	return errs
+}
+
+// GetValue returns the value of a key (ex: core.extensions) present in .nt/config.
+func (c *Config) GetValue(key string) (any, error) {
+	values, err := c.readConfigValues()
+	if err != nil {
+		return nil, err
+	}
+	var current any = values
+	for _, part := range strings.Split(key, ".") {
+		table, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+		name, ok := configKey(table, part)
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+		current = table[name]
+	}
+	return current, nil
+}
+
+// SetValue updates a key (ex: core.slug_strategy) in .nt/config.
+// The value is parsed as a TOML value (ex: true, 10, ["md"]) and defaults to a string otherwise.
+// Only the line of the key is rewritten (comments and formatting are kept).
+// The new configuration is validated before being saved.
+func (c *Config) SetValue(key string, value string) error {
+	parts := strings.Split(key, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid key %q (ex: core.slug_strategy)", key)
+	}
+	typ, ok := configKeyType(reflect.TypeOf(ConfigFile{}), parts)
+	if !ok {
+		return fmt.Errorf("unknown key %q", key)
+	}
+	if typ.Kind() == reflect.Struct || typ.Kind() == reflect.Map {
+		return fmt.Errorf("invalid key %q: it's a section", key)
+	}
+
+	content, err := os.ReadFile(c.configFilePath())
+	if os.IsNotExist(err) {
+		content = []byte(DefaultConfig)
+	} else if err != nil {
+		return fmt.Errorf("failed to read .nt/config file: %v", err)
+	}
+	newContent := setConfigValue(string(content), parts, formatConfigValue(value))
+
+	// Re-read and validate before persisting
+	configFile, err := parseConfigFile(newContent)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	newConfig := *c
+	newConfig.ConfigFile = *configFile
+	if err := newConfig.Check(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := os.WriteFile(c.configFilePath(), []byte(newContent), 0644); err != nil {
+		return err
+	}
+	c.ConfigFile = *configFile
 	return nil
 }
 
+// configKeyType searches the type of a key using the same rules as the TOML decoder.
+func configKeyType(typ reflect.Type, parts []string) (reflect.Type, bool) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if len(parts) == 0 {
+		return typ, true
+	}
+	switch typ.Kind() {
+	case reflect.Map:
+		// Any name is accepted (ex: [deck.life])
+		return configKeyType(typ.Elem(), parts[1:])
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if strings.EqualFold(name, parts[0]) {
+				return configKeyType(field.Type, parts[1:])
+			}
+		}
+		return nil, false
+	case reflect.Interface:
+		return typ, true
+	}
+	// Scalar values (or lists) cannot have sub-keys
+	return nil, false
+}
+
+// formatConfigValue converts a value to its TOML representation. Invalid TOML values are considered as strings.
+func formatConfigValue(value string) string {
+	var result map[string]any
+	if err := toml.Unmarshal([]byte("value = "+value), &result); err == nil {
+		return value
+	}
+	// JSON escape sequences are valid in TOML basic strings
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprintf("%q", value)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+var (
+	regexConfigTable = regexp.MustCompile(`^\s*\[\[?\s*([^\]]+?)\s*\]\]?\s*(?:#.*)?$`)
+	regexConfigKey   = regexp.MustCompile(`^(\s*)([A-Za-z0-9_."' -]+?)\s*=\s*(.*)$`)
+)
+
+// setConfigValue rewrites the line defining the key in a TOML document, or adds it when missing.
+func setConfigValue(content string, parts []string, value string) string {
+	lines := strings.Split(content, "\n")
+
+	matchKey := func(table []string, key string) bool {
+		var actual []string
+		actual = append(actual, table...)
+		actual = append(actual, splitConfigKey(key)...)
+		if len(actual) != len(parts) {
+			return false
+		}
+		for i := range actual {
+			if !strings.EqualFold(actual[i], parts[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var table []string
+	tableFound := false
+	tableEnd := -1 // Index of the last line of the target table
+	for i, line := range lines {
+		if match := regexConfigTable.FindStringSubmatch(line); match != nil {
+			table = splitConfigKey(match[1])
+			if len(table) == len(parts)-1 && matchKey(table, parts[len(parts)-1]) {
+				tableFound = true
+				tableEnd = i
+			}
+			continue
+		}
+		if match := regexConfigKey.FindStringSubmatch(line); match != nil {
+			if matchKey(table, match[2]) {
+				// Keep the optional trailing comment
+				comment := trailingConfigComment(match[3])
+				lines[i] = match[1] + strings.TrimSpace(match[2]) + " = " + value + comment
+				return strings.Join(lines, "\n")
+			}
+		}
+		if tableFound && tableEnd == i-1 && strings.TrimSpace(line) != "" {
+			tableEnd = i
+		}
+	}
+
+	newLine := parts[len(parts)-1] + " = " + value
+	if tableFound {
+		lines = slices.Insert(lines, tableEnd+1, newLine)
+		return strings.Join(lines, "\n")
+	}
+
+	// Append a new section
+	result := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	result += "\n\n[" + strings.Join(parts[:len(parts)-1], ".") + "]\n" + newLine + "\n"
+	return result
+}
+
+// splitConfigKey splits a TOML key (ex: remotes."my backup".dir) into parts.
+func splitConfigKey(key string) []string {
+	var parts []string
+	for _, part := range strings.Split(key, ".") {
+		parts = append(parts, strings.Trim(strings.TrimSpace(part), `"'`))
+	}
+	return parts
+}
+
+// trailingConfigComment returns the comment following a TOML value (ex: ` # default`).
+func trailingConfigComment(value string) string {
+	for i := 0; i < len(value); i++ {
+		if value[i] != '#' {
+			continue
+		}
+		var result map[string]any
+		if err := toml.Unmarshal([]byte("value = "+value[:i]), &result); err == nil {
+			return " " + strings.TrimSpace(value[i:])
+		}
+	}
+	return ""
+}
+
+func (c *Config) configFilePath() string {
+	return filepath.Join(c.RootDirectory, ".nt", "config")
+}
+
+// readConfigValues reads .nt/config (or the default configuration when missing) as raw values.
+func (c *Config) readConfigValues() (map[string]any, error) {
+	content, err := os.ReadFile(c.configFilePath())
+	if os.IsNotExist(err) {
+		content = []byte(DefaultConfig)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read .nt/config file: %v", err)
+	}
+	values := make(map[string]any)
+	if err := toml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse .nt/config file: %v", err)
+	}
+	return values, nil
+}
+
+// configKey searches a key in a TOML table ignoring case like when decoding.
+func configKey(table map[string]any, key string) (string, bool) {
+	if _, ok := table[key]; ok {
+		return key, true
+	}
+	for name := range table {
+		if strings.EqualFold(name, key) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 /* Helpers */
 
 func BoolPointer(b bool) *bool {
 	return &b
 }
+
+func IntPointer(i int) *int {
+	return &i
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/julien-sobczak/the-notewriter/internal/medias"
 	"github.com/julien-sobczak/the-notewriter/pkg/text"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,6 +44,49 @@ func TestGlobPaths(t *testing.T) {
 
 	ignoreFile := IgnoreFile{Entries: g}
 	assert.True(t, ignoreFile.MustExcludeFile("archives/toto", true))
+
+	assert.True(t, g.Negated())
+	assert.False(t, GlobPaths{"build/", "*.tmp"}.Negated())
+}
+
+func TestGlobPathsInclude(t *testing.T) {
+	var tests = []struct {
+		name     string
+		includes GlobPaths
+		path     string
+		included bool
+	}{
+		{name: "No includes", includes: nil, path: "go.md", included: true},
+		{name: "Single include", includes: GlobPaths{"references/"}, path: "references/go.md", included: true},
+		{name: "Single include not matching", includes: GlobPaths{"references/"}, path: "go.md", included: false},
+		{name: "Multiple includes", includes: GlobPaths{"references/", "projects/"}, path: "projects/go.md", included: true},
+		{name: "Include and exclude", includes: GlobPaths{"references/**", "!references/drafts/**"}, path: "references/go.md", included: true},
+		{name: "Include and exclude matching", includes: GlobPaths{"references/**", "!references/drafts/**"}, path: "references/drafts/go.md", included: false},
+		{name: "Include and exclude not matching", includes: GlobPaths{"references/**", "!references/drafts/**"}, path: "go.md", included: false},
+		{name: "Only exclude", includes: GlobPaths{"!drafts/"}, path: "go.md", included: true},
+		{name: "Only exclude matching", includes: GlobPaths{"!drafts/"}, path: "drafts/go.md", included: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.included, tt.includes.Include(tt.path))
+		})
+	}
+}
+
+func TestIgnoreFileNested(t *testing.T) {
+	ignoreFile := IgnoreFile{Entries: GlobPaths{"build/"}}
+	require.NoError(t, ignoreFile.AddNested("notes/drafts", "# Work in progress\n/wip.md\nold/\n"))
+
+	assert.True(t, ignoreFile.MustExcludeFile("build/index.md", false))
+	assert.True(t, ignoreFile.MustExcludeFile("notes/drafts/wip.md", false))
+	assert.True(t, ignoreFile.MustExcludeFile("notes/drafts/old", true))
+	assert.True(t, ignoreFile.MustExcludeFile("notes/drafts/old/go.md", false))
+	assert.False(t, ignoreFile.MustExcludeFile("notes/drafts/go.md", false))
+	// Rules are relative to the directory
+	assert.False(t, ignoreFile.MustExcludeFile("wip.md", false))
+	assert.False(t, ignoreFile.MustExcludeFile("notes/wip.md", false))
+	assert.False(t, ignoreFile.MustExcludeFile("notes/drafts/sub/wip.md", false))
+	assert.False(t, ignoreFile.Negated())
 }
 
 func TestReadConfigFromDirectory(t *testing.T) {
@@ -281,6 +325,27 @@ schemas:
 		require.ErrorContains(t, err, "invalid pattern")
 	})
 
+	t.Run("Invalid headings in file types", func(t *testing.T) {
+		dir := populate(t, map[string]interface{}{
+
+			".nt/lint": `
+file_types:
+
+- name: Meeting
+  headings:
+  - match: "^Decisions$"
+    children:
+    - kind: decision
+`,
+		})
+
+		c, err := ReadConfigFromDirectory(dir)
+		require.NoError(t, err)
+
+		err = c.Check()
+		require.ErrorContains(t, err, `unsupported kind "decision" in file type "Meeting"`)
+	})
+
 	t.Run("Invalid .nt/config", func(t *testing.T) {
 		tests := []struct {
 			name             string
@@ -316,6 +381,19 @@ template = """# {{index . "title" | title }}"""
 				expectedError: "invalid path for reference",
 			},
 
+			{
+				name: "Invalid cache TTL in references",
+				config: `
+[reference.books]
+title = "A book"
+manager = "google-books"
+path = """references/books/test.md"""
+template = """# {{index . "title" | title }}"""
+cacheTTL = "30 days"
+`,
+				expectedError: "invalid cache TTL for reference",
+			},
+
 			{
 				name: "Supported SRS algorithm",
 				config: `
@@ -354,6 +432,153 @@ algorithmSettings.easeFactor = 3.1
 					}, deck.AlgorithmSettings)
 				},
 			},
+
+			{
+				name: "Named remotes",
+				config: `
+[remote]
+type = "fs"
+dir = "/tmp/origin"
+
+[remotes.backup]
+type = "fs"
+dir = "/tmp/backup"
+
+[remotes.origin]
+type = "ftp"
+`,
+				additionalChecks: func(t *testing.T, c *Config) {
+					assert.Equal(t, []string{"origin", "backup"}, c.ConfigFile.RemoteNames())
+					remote, ok := c.ConfigFile.RemoteByName("backup")
+					require.True(t, ok)
+					assert.Equal(t, "/tmp/backup", remote.Dir)
+					remote, ok = c.ConfigFile.RemoteByName("")
+					require.True(t, ok)
+					assert.Equal(t, "/tmp/origin", remote.Dir)
+					_, ok = c.ConfigFile.RemoteByName("unknown")
+					assert.False(t, ok)
+
+					errs := c.Validate()
+					require.Len(t, errs, 2)
+					assert.ErrorContains(t, errs[0], `remote name "origin" is reserved`)
+					assert.ErrorContains(t, errs[1], `unsupported type "ftp" for remote "origin"`)
+				},
+			},
+
+			{
+				name: "Invalid note template",
+				config: `
+[templates]
+todo = """# {{index . "title"}"""
+`,
+				expectedError: "invalid template for kind",
+			},
+
+			{
+				name: "Unsupported kind in note templates",
+				config: `
+[templates]
+free = """# {{index . "title"}}"""
+`,
+				expectedError: "unsupported kind",
+			},
+
+			{
+				name: "Unsupported slug strategy",
+				config: `
+[core]
+slug_strategy = "random"
+`,
+				expectedError: "unsupported slug strategy",
+			},
+
+			{
+				name: "Unsupported compression",
+				config: `
+[core]
+compression = "zstd"
+`,
+				expectedError: "unsupported compression",
+			},
+
+			{
+				name: "Time zone",
+				config: `
+[core]
+timezone = "Europe/Paris"
+`,
+				additionalChecks: func(t *testing.T, c *Config) {
+					assert.Equal(t, "Europe/Paris", c.Location().String())
+				},
+			},
+
+			{
+				name: "Unsupported time zone",
+				config: `
+[core]
+timezone = "Europe/Atlantis"
+`,
+				expectedError: "unsupported timezone",
+			},
+
+			{
+				name: "Converters per media kind",
+				config: `
+[medias]
+command = "ffmpeg"
+converters.picture = "svgo"
+converters.audio = "random"
+`,
+				additionalChecks: func(t *testing.T, c *Config) {
+					assert.Equal(t, map[string]string{
+						"picture": "svgo",
+						"audio":   "random",
+					}, c.ConfigFile.Medias.Converters)
+				},
+			},
+
+			{
+				name: "Media kinds per extension",
+				config: `
+[medias.kinds]
+mkv = "video"
+heic = "picture"
+`,
+				additionalChecks: func(t *testing.T, c *Config) {
+					kind, ok := c.ConfigFile.MediaKindFor("movies/matrix.MKV")
+					assert.True(t, ok)
+					assert.Equal(t, KindVideo, kind)
+					_, ok = c.ConfigFile.MediaKindFor("movies/matrix.mp4")
+					assert.False(t, ok)
+				},
+			},
+
+			{
+				name: "Unknown media kind in kinds",
+				config: `
+[medias.kinds]
+mkv = "movie"
+`,
+				expectedError: "unknown media kind",
+			},
+
+			{
+				name: "Unknown media kind in converters",
+				config: `
+[medias.converters]
+diagram = "random"
+`,
+				expectedError: "unknown media kind",
+			},
+
+			{
+				name: "Unsupported converter",
+				config: `
+[medias.converters]
+picture = "imagemagick"
+`,
+				expectedError: "unsupported converter",
+			},
 		}
 
 		for _, tt := range tests {
@@ -389,6 +614,139 @@ algorithmSettings.easeFactor = 3.1
 
 }
 
+func TestValidateConfig(t *testing.T) {
+	dir := populate(t, map[string]interface{}{
+
+		".nt/config": `
+[remote]
+type="ftp"
+
+[deck.go]
+name="Go"
+query="#"
+`,
+
+		".nt/lint": `
+schemas:
+- name: Books
+  attributes:
+  - name: isbn
+    type: text
+`,
+	})
+
+	c, err := ReadConfigFromDirectory(dir)
+	require.NoError(t, err)
+	require.NoError(t, c.Check())
+
+	errs := c.Validate()
+	require.Len(t, errs, 3)
+	assert.ErrorContains(t, errs[0], `unsupported remote type "ftp"`)
+	assert.ErrorContains(t, errs[1], `invalid query for deck "go"`)
+	assert.ErrorContains(t, errs[2], `unsupported type "text" for attribute "isbn" in schema "Books"`)
+}
+
+func TestConfigValues(t *testing.T) {
+	dir := populate(t, map[string]interface{}{
+
+		".nt/config": `
+# My settings
+[core]
+extensions=["md", "markdown"] # Markdown only
+maxObjectsPerPackfile=100
+
+[medias]
+converters.picture = "random"
+`,
+	})
+
+	c, err := ReadConfigFromDirectory(dir)
+	require.NoError(t, err)
+
+	value, err := c.GetValue("core.extensions")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"md", "markdown"}, value)
+	value, err = c.GetValue("core.MaxObjectsPerPackFile") // case-insensitive
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, value)
+	_, err = c.GetValue("core.unknown")
+	require.ErrorContains(t, err, `unknown key "core.unknown"`)
+
+	require.NoError(t, c.SetValue("core.slug_strategy", "title"))
+	require.NoError(t, c.SetValue("core.follow_symlinks", "true"))
+	require.NoError(t, c.SetValue("core.maxObjectsPerPackFile", "50"))
+	require.NoError(t, c.SetValue("remote.dir", "/tmp/remote"))
+	require.NoError(t, c.SetValue("medias.converters.picture", "svgo"))
+	require.NoError(t, c.SetValue("core.extensions", `["md"]`))
+	assert.Equal(t, SlugStrategyTitle, c.ConfigFile.Core.SlugStrategy)
+
+	// Comments and formatting are kept
+	content, err := os.ReadFile(filepath.Join(dir, ".nt/config"))
+	require.NoError(t, err)
+	assert.Equal(t, `
+# My settings
+[core]
+extensions = ["md"] # Markdown only
+maxObjectsPerPackfile = 50
+slug_strategy = "title"
+follow_symlinks = true
+
+[medias]
+converters.picture = "svgo"
+
+[remote]
+dir = "/tmp/remote"
+`, string(content))
+
+	// Changes are persisted
+	c, err = ReadConfigFromDirectory(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SlugStrategyTitle, c.ConfigFile.Core.SlugStrategy)
+	assert.True(t, c.ConfigFile.Core.FollowSymlinks)
+	assert.Equal(t, 50, c.ConfigFile.Core.MaxObjectsPerPackFile)
+	assert.Equal(t, "/tmp/remote", c.ConfigFile.Remote.Dir)
+	assert.Equal(t, []string{"md"}, c.ConfigFile.Core.Extensions)
+	assert.Equal(t, "svgo", c.ConfigFile.Medias.Converters["picture"])
+
+	// Invalid changes are not persisted
+	err = c.SetValue("core.slug_strategy", "random")
+	require.ErrorContains(t, err, `unsupported slug strategy "random"`)
+	err = c.SetValue("core.unknown", "true")
+	require.ErrorContains(t, err, `unknown key "core.unknown"`)
+	err = c.SetValue("core.extensions.md", "true")
+	require.ErrorContains(t, err, `unknown key "core.extensions.md"`)
+	err = c.SetValue("medias.converters", "true")
+	require.ErrorContains(t, err, "invalid key")
+	err = c.SetValue("core", "true")
+	require.ErrorContains(t, err, "invalid key")
+	c, err = ReadConfigFromDirectory(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SlugStrategyTitle, c.ConfigFile.Core.SlugStrategy)
+}
+
+func TestConverterFor(t *testing.T) {
+	c := &Config{
+		ConfigFile: ConfigFile{
+			Medias: ConfigMedias{
+				Command: "random",
+				Converters: map[string]string{
+					"picture": "svgo",
+				},
+			},
+		},
+	}
+	// Use the per-kind converter
+	assert.IsType(t, &medias.SvgoConverter{}, c.ConverterFor(KindPicture))
+	// Fallback to the default converter
+	assert.IsType(t, &medias.RandomConverter{}, c.ConverterFor(KindVideo))
+	assert.IsType(t, &medias.RandomConverter{}, c.ConverterFor(KindAudio))
+
+	// The default converter must not be instantiated when a per-kind converter exists
+	c.ConfigFile.Medias.Command = "ffmpeg"
+	c.ConfigFile.Medias.Converters["picture"] = "random"
+	assert.IsType(t, &medias.RandomConverter{}, c.ConverterFor(KindPicture))
+}
+
 func TestInitConfiguration(t *testing.T) {
 	dir := populate(t, map[string]interface{}{
 		// missing .nt directory
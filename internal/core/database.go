@@ -7,10 +7,12 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
@@ -19,7 +21,6 @@ import (
 	"github.com/julien-sobczak/the-notewriter/pkg/filesystem"
 	"github.com/julien-sobczak/the-notewriter/pkg/resync"
 	"github.com/julien-sobczak/the-notewriter/pkg/text"
-	godiffpatch "github.com/sourcegraph/go-diff-patch"
 	"golang.org/x/exp/slices"
 )
 
@@ -92,6 +93,11 @@ func CurrentDB() *DB {
 			commitGraph: commitGraph,
 			refs:        refs,
 		}
+
+		// Complete or cancel a commit interrupted by a crash
+		if _, err := dbSingleton.Recover(); err != nil {
+			CurrentLogger().Warnf("Unable to recover interrupted commit: %v (run nt recover)", err)
+		}
 	})
 	return dbSingleton
 }
@@ -244,6 +250,34 @@ func (db *DB) ReadCommit(oid string) (*Commit, bool) {
 	return nil, false
 }
 
+// ResolveOID returns the full OID of the object, pack file, commit, or blob starting with the given prefix.
+// An error is returned when no OID or several OIDs match.
+func (db *DB) ResolveOID(prefix string) (string, error) {
+	oids := db.index.knownOIDs()
+	for _, commit := range db.commitGraph.Commits {
+		oids = append(oids, commit.OID)
+	}
+
+	pattern := strings.ToLower(strings.TrimSpace(prefix)) + "%"
+	rows, err := db.Client().Query(`SELECT oid FROM blob WHERE oid LIKE ?`, pattern)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var oid string
+		if err := rows.Scan(&oid); err != nil {
+			return "", err
+		}
+		oids = append(oids, oid)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	return resolveOIDPrefix(prefix, oids)
+}
+
 // Head returns the latest commit or nil if no commit exists.
 func (db *DB) Head() *Commit {
 	if len(db.commitGraph.Commits) == 0 {
@@ -252,6 +286,15 @@ func (db *DB) Head() *Commit {
 	return db.commitGraph.Commits[len(db.commitGraph.Commits)-1]
 }
 
+// Log returns all commits, starting with the most recent one.
+func (db *DB) Log() []*Commit {
+	var results []*Commit
+	for i := len(db.commitGraph.Commits) - 1; i >= 0; i-- {
+		results = append(results, db.commitGraph.Commits[i])
+	}
+	return results
+}
+
 // ReadCommittedObject reads the last known committed version of stateful object on disk.
 func (db *DB) ReadCommittedObject(oid string) (StatefulObject, error) {
 	indexObject, ok := db.index.objectsRef[oid]
@@ -372,13 +415,46 @@ func (db *DB) CompressPackFile(packFile *PackFile) (bool, error) {
 
 // ReadBlob reads a blob file on disk.
 func (db *DB) ReadBlob(oid string) ([]byte, error) {
-	path := filepath.Join(CurrentConfig().RootDirectory, ".nt/objects", OIDToPath(oid))
-	return os.ReadFile(path)
+	return os.ReadFile(db.blobPath(oid))
+}
+
+// OpenBlob opens a blob file on disk for reading without loading it in memory.
+// The caller must close the reader.
+func (db *DB) OpenBlob(oid string) (io.ReadCloser, error) {
+	return os.Open(db.blobPath(oid))
+}
+
+// WriteBlobFrom writes a blob file on disk by copying the reader content.
+// No partial file is left on error.
+func (db *DB) WriteBlobFrom(oid string, r io.Reader) error {
+	path := db.blobPath(oid)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	CurrentLogger().Infof("💾 Saved blob %s", filepath.Base(path))
+	return nil
+}
+
+// blobPath returns the path of a blob file inside .nt/objects.
+func (db *DB) blobPath(oid string) string {
+	return filepath.Join(CurrentConfig().RootDirectory, ".nt/objects", OIDToPath(oid))
 }
 
 // WriteBlob writes a blob file on disk
 func (db *DB) WriteBlob(oid string, data []byte) error {
-	path := filepath.Join(CurrentConfig().RootDirectory, ".nt/objects", OIDToPath(oid))
+	path := db.blobPath(oid)
 	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
 		return err
 	}
@@ -418,42 +494,70 @@ func (db *DB) DeleteBlob(media *Media, blob *BlobRef) error {
 
 // Origin returns the origin implementation based on the optional configured type.
 func (db *DB) Origin() Remote {
+	if CurrentConfig().ConfigFile.Remote.Type == "" {
+		// Not cached as the remote can be configured later
+		return nil
+	}
 	dbRemoteOnce.Do(func() {
-		config := CurrentConfig()
-		configRemote := config.ConfigFile.Remote
-		if configRemote.Type == "" {
-			return
-		}
-		switch configRemote.Type {
-		case "fs":
-			remote, err := NewFSRemote(configRemote.Dir)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Unable to init FS remote: %v\n", err)
-				os.Exit(1)
-			}
-			db.origin = remote
-		case "s3":
-			remote, err := NewS3RemoteWithCredentials(configRemote.Endpoint, configRemote.BucketName, configRemote.AccessKey, configRemote.SecretKey, configRemote.Secure)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Unable to init S3 remote: %v\n", err)
-				os.Exit(1)
-			}
-			db.origin = remote
-		case "storj":
-			remote, err := NewStorjRemoteWithCredentials(configRemote.BucketName, configRemote.AccessKey)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Unable to init Storj remote: %v\n", err)
-				os.Exit(1)
-			}
-			db.origin = remote
-		default:
-			fmt.Fprintf(os.Stderr, "Unknow remote type %q\n", configRemote.Type)
+		configRemote := CurrentConfig().ConfigFile.Remote
+		remote, err := NewRemote(configRemote)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
+		db.origin = remote
 	})
 	return db.origin
 }
 
+// RemoteByName returns the implementation of a configured remote. An empty name means the default remote.
+func (db *DB) RemoteByName(name string) (Remote, error) {
+	if name == "" || name == DefaultRemoteName {
+		origin := db.Origin()
+		if origin == nil {
+			return nil, errors.New("no remote found")
+		}
+		return origin, nil
+	}
+	configRemote, ok := CurrentConfig().ConfigFile.RemoteByName(name)
+	if !ok {
+		return nil, fmt.Errorf("no remote found with name %q", name)
+	}
+	return NewRemote(configRemote)
+}
+
+// NewRemote instantiates the remote implementation for the given settings.
+func NewRemote(configRemote ConfigRemote) (Remote, error) {
+	switch configRemote.Type {
+	case "fs":
+		remote, err := NewFSRemote(configRemote.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to init FS remote: %v", err)
+		}
+		return remote, nil
+	case "s3":
+		remote, err := NewS3RemoteWithCredentials(configRemote.Endpoint, configRemote.BucketName, configRemote.AccessKey, configRemote.SecretKey, configRemote.Secure)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to init S3 remote: %v", err)
+		}
+		return remote, nil
+	case "storj":
+		remote, err := NewStorjRemoteWithCredentials(configRemote.BucketName, configRemote.AccessKey)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to init Storj remote: %v", err)
+		}
+		return remote, nil
+	case "rclone":
+		remote, err := NewRcloneRemote(configRemote.Path)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to init rclone remote: %v", err)
+		}
+		return remote, nil
+	default:
+		return nil, fmt.Errorf("Unknow remote type %q", configRemote.Type)
+	}
+}
+
 func (db *DB) initClient() *sql.DB {
 	dbClientOnce.Do(func() {
 		config := CurrentConfig()
@@ -483,6 +587,11 @@ func (db *DB) initClient() *sql.DB {
 		if err != nil && err != migrate.ErrNoChange {
 			log.Fatalf("Error while running migrations: %v", err)
 		}
+
+		// Apply the configured tokenizer (existing databases require nt reindex)
+		if err := initFTSTokenizer(db); err != nil {
+			log.Fatalf("Error while initializing full-text index: %v", err)
+		}
 	})
 	return dbSingleton.client
 }
@@ -493,6 +602,10 @@ func (db *DB) StageObject(obj StatefulObject) error {
 
 // Commit creates a new commit object and clear the staging area.
 func (db *DB) Commit(msg string) error {
+	if db.CommitInProgress() {
+		return errors.New(`a previous commit was interrupted (run "nt recover" first)`)
+	}
+
 	changesAdded := db.index.StagingArea.CountByState(Added)
 	changesModified := db.index.StagingArea.CountByState(Modified)
 	changesDeleted := db.index.StagingArea.CountByState(Deleted)
@@ -511,12 +624,21 @@ func (db *DB) Commit(msg string) error {
 		}
 		note := obj.ReadObject().(*Note)
 		if err := note.RunHooks(nil); err != nil {
-			return err
+			if !CurrentConfig().IgnoreHookErrors {
+				return err
+			}
+			CurrentLogger().Warnf("Ignoring hook error: %v", err)
 		}
 	}
 
 	// Convert the staging area to a new commit file
 	commit, packFiles := db.index.CreateCommitFromStagingArea()
+	commit.Author = NewCommitAuthorFromConfig()
+	commit.Message = strings.TrimSpace(msg)
+	// Leave a marker until all files are written to recover from a crash
+	if err := startCommit(commit); err != nil {
+		return err
+	}
 	for _, packFile := range packFiles {
 		if err := packFile.Save(); err != nil {
 			return err
@@ -538,7 +660,16 @@ func (db *DB) Commit(msg string) error {
 	}
 
 	// Update the main ref
-	db.updateRef("main", commit.OID)
+	if err := db.updateRef("main", commit.OID); err != nil {
+		return err
+	}
+	if err := endCommit(); err != nil {
+		return err
+	}
+
+	for _, packFile := range packFiles {
+		emitPackFileChanges(packFile, commit.OID)
+	}
 
 	// Output result
 	fmt.Printf("[%7s] %s\n", commit.OID, msg)
@@ -568,55 +699,148 @@ func (db *DB) Commit(msg string) error {
 	return nil
 }
 
-// Pull retrieves remote objects.
+// Supported strategies to resolve conflicts when pulling
+const (
+	// PullStrategyManual reports conflicts without pulling anything
+	PullStrategyManual = "manual"
+	// PullStrategyOurs keeps the local version of conflicting files
+	PullStrategyOurs = "ours"
+	// PullStrategyTheirs keeps the remote version of conflicting files
+	PullStrategyTheirs = "theirs"
+)
+
+// PullConflictError reports files changed both locally and remotely since the last common commit.
+type PullConflictError struct {
+	RelativePaths []string
+}
+
+func (e *PullConflictError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d files changed both locally and remotely:", len(e.RelativePaths)))
+	for _, relativePath := range e.RelativePaths {
+		sb.WriteString("\n  " + relativePath)
+	}
+	return sb.String()
+}
+
+// Pull retrieves remote objects, reporting conflicts if local and remote commits have diverged.
 func (db *DB) Pull() error {
-	origin := db.Origin()
-	if origin == nil {
-		return errors.New("no remote found")
+	return db.PullWithStrategy(PullStrategyManual)
+}
+
+// PullWithStrategy retrieves remote objects using the given strategy to resolve conflicts.
+func (db *DB) PullWithStrategy(strategy string) error {
+	return db.PullRemote(DefaultRemoteName, strategy)
+}
+
+// PullRemote retrieves objects from the remote with the given name using the given strategy to resolve conflicts.
+func (db *DB) PullRemote(name string, strategy string) error {
+	if !slices.Contains([]string{PullStrategyManual, PullStrategyOurs, PullStrategyTheirs}, strategy) {
+		return fmt.Errorf("unsupported pull strategy %q", strategy)
 	}
 
+	origin, err := db.RemoteByName(name)
+	if err != nil {
+		return err
+	}
+
+	// Start from the bundle on a new repository to avoid downloading objects one at a time
+	if len(db.commitGraph.Commits) == 0 {
+		exists, err := origin.ObjectExists("bundle")
+		if err != nil {
+			return err
+		}
+		if exists {
+			data, err := origin.GetObject("bundle")
+			if err != nil {
+				return err
+			}
+			if err := CurrentRepository().PullBundle(bytes.NewReader(data)); err != nil {
+				return fmt.Errorf("unable to pull bundle: %w", err)
+			}
+		}
+	}
+
+	cg, err := db.pullFrom(origin, strategy)
+	if err != nil {
+		return err
+	}
+	if cg != nil {
+		// Keep note of last retrieved commit
+		db.updateRef(remoteRefName(name), cg.Ref())
+	}
+	return nil
+}
+
+// pullFrom retrieves objects of commits missing locally from the given remote
+// and returns the remote commit graph (nil if the remote is empty).
+func (db *DB) pullFrom(origin Remote, strategy string) (*CommitGraph, error) {
 	// Read remote's commit-graph to find new commits to pull
 	data, err := origin.GetObject("info/commit-graph")
 	if errors.Is(err, ErrObjectNotExist) {
 		// Nothing to pull
-		return nil
+		return nil, nil
 	}
 	cg := new(CommitGraph)
 	if err := cg.Read(bytes.NewReader(data)); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Iterate over missing commits
+	// Download pack files of missing commits first to detect conflicts before any change
 	diff := db.commitGraph.Diff(cg)
 	commits := diff.MissingCommits
+	packFiles := make(map[string][]*PackFile)
 	for _, commit := range commits {
-
-		// Download each commit in a single transaction
-		err := db.BeginTransaction()
-		if err != nil {
-			return err
-		}
-		defer db.RollbackTransaction()
-
 		for _, packFileRef := range commit.PackFiles {
 			// Retrieve the pack file content
 			data, err = origin.GetObject(OIDToPath(packFileRef.OID))
 			if errors.Is(err, ErrObjectNotExist) {
-				return fmt.Errorf("missing pack file %q", packFileRef.OID)
+				return nil, fmt.Errorf("missing pack file %q", packFileRef.OID)
 			} else if err != nil {
-				return err
+				return nil, err
 			}
 
 			// Read the content
 			packFile := new(PackFile)
 			if err := packFile.Read(bytes.NewReader(data)); err != nil {
-				return err
+				return nil, err
 			}
+			packFiles[commit.OID] = append(packFiles[commit.OID], packFile)
+		}
+	}
+
+	conflicts, err := db.findPullConflicts(cg, packFiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && strategy == PullStrategyManual {
+		return nil, &PullConflictError{RelativePaths: conflicts}
+	}
+
+	// Iterate over missing commits
+	for _, commit := range commits {
+
+		// Download each commit in a single transaction
+		err := db.BeginTransaction()
+		if err != nil {
+			return nil, err
+		}
+		defer db.RollbackTransaction()
 
+		// Listeners are notified only after the transaction succeeds
+		var events []ObjectChangeEvent
+
+		for _, packFile := range packFiles[commit.OID] {
 			// Parse the objects and blobs
 			for _, packObject := range packFile.PackObjects {
 				remoteObject := packObject.ReadObject()
 
+				conflicting := slices.Contains(conflicts, objectRelativePath(remoteObject))
+				if conflicting && strategy == PullStrategyOurs {
+					// Keep the local version
+					continue
+				}
+
 				// Retrieve optional blobs
 				for _, blobRef := range remoteObject.Blobs() {
 					// Check if blob exists
@@ -625,42 +849,50 @@ func (db *DB) Pull() error {
 						continue
 					}
 
-					// Download the file
-					blobData, err := origin.GetObject(blobPath)
+					// Download the file (streamed as medias can be large)
+					blobReader, err := origin.GetObjectStream(blobPath)
 					if err != nil {
-						return err
+						return nil, err
 					}
-					blobFile := new(BlobFile)
-					blobFile.Ref = blobRef
-					if err := blobFile.Read(bytes.NewReader(blobData)); err != nil {
-						return err
-					}
-					if err := blobFile.Save(); err != nil {
-						return err
+					err = db.WriteBlobFrom(blobRef.OID, blobReader)
+					blobReader.Close()
+					if err != nil {
+						return nil, err
 					}
 				}
 
 				newState := db.determineState(packObject)
+				if conflicting && newState == None && packObject.State != Deleted {
+					// The remote version wins even if the local one is more recent
+					newState = Modified
+				}
 				remoteObject.ForceState(newState)
 
 				// Add in SQL database
 				if err := remoteObject.Save(); err != nil {
-					return err
+					return nil, err
 				}
 
+				// Enrich index
+				db.index.putPackObject(commit.OID, packFile.OID, packObject)
+
+				if hasObjectChangeListeners() {
+					events = append(events, newObjectChangeEvent(remoteObject, packObject.State, commit.OID))
+				}
 			}
 
 			// Write on disk
 			if err := packFile.Save(); err != nil {
-				return fmt.Errorf("unable to write retrieved pack file %q: %v", packFile.OID, err)
+				return nil, fmt.Errorf("unable to write retrieved pack file %q: %v", packFile.OID, err)
 			}
-
-			// Enrich index
-			db.index.putPackFile(commit.OID, packFile)
+			db.index.PackFiles[packFile.OID] = commit.OID
 		}
 
 		if err := db.CommitTransaction(); err != nil {
-			return err
+			return nil, err
+		}
+		for _, evt := range events {
+			emitObjectChange(evt)
 		}
 
 		db.commitGraph.AppendCommit(commit)
@@ -671,23 +903,111 @@ func (db *DB) Pull() error {
 
 	// Persist local commit-graph including downloaded commits
 	if err := db.commitGraph.Save(); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Keep note of last origin retrieved commit
-	db.updateRef("origin", cg.Ref())
+	return cg, nil
+}
 
-	return nil
+// findPullConflicts returns the relative paths of files changed differently in local commits
+// missing from the origin and in origin commits missing locally.
+func (db *DB) findPullConflicts(originCommitGraph *CommitGraph, originPackFiles map[string][]*PackFile) ([]string, error) {
+	localCommits := originCommitGraph.Diff(db.commitGraph).MissingCommits
+	if len(localCommits) == 0 || len(originPackFiles) == 0 {
+		// No divergence
+		return nil, nil
+	}
+
+	var localPackFiles []*PackFile
+	for _, commit := range localCommits {
+		packFiles, err := db.ReadPackFilesFromCommit(commit)
+		if err != nil {
+			return nil, err
+		}
+		localPackFiles = append(localPackFiles, packFiles...)
+	}
+	localChanges := changedPaths(localPackFiles)
+
+	var remotePackFiles []*PackFile
+	for _, packFiles := range originPackFiles {
+		remotePackFiles = append(remotePackFiles, packFiles...)
+	}
+	remoteChanges := changedPaths(remotePackFiles)
+
+	var conflicts []string
+	for relativePath, remoteChange := range remoteChanges {
+		localChange, ok := localChanges[relativePath]
+		if !ok {
+			continue
+		}
+		if localChange.hash != "" && localChange.hash == remoteChange.hash {
+			// Same changes on both sides (ex: a media deleted twice)
+			continue
+		}
+		conflicts = append(conflicts, relativePath)
+	}
+	slices.Sort(conflicts)
+	return conflicts, nil
+}
+
+// pathChange describes the last version of a path changed in pack files.
+type pathChange struct {
+	mtime time.Time
+	// Content hash of the file or the media ("deleted" when removed, empty when unknown)
+	hash string
+}
+
+// changedPaths returns the paths of objects present in the given pack files.
+func changedPaths(packFiles []*PackFile) map[string]*pathChange {
+	result := make(map[string]*pathChange)
+	for _, packFile := range packFiles {
+		for _, packObject := range packFile.PackObjects {
+			object := packObject.ReadObject()
+			relativePath := objectRelativePath(object)
+			if relativePath == "" {
+				continue
+			}
+			change, ok := result[relativePath]
+			if !ok {
+				change = new(pathChange)
+				result[relativePath] = change
+			}
+
+			// Only files and medias hash their content. Other objects are derived from files.
+			var hash string
+			switch object := object.(type) {
+			case *File:
+				hash = object.Hash
+			case *Media:
+				hash = object.Hash
+			default:
+				continue
+			}
+			if packObject.State == Deleted {
+				hash = "deleted"
+			}
+			if change.hash == "" || packObject.MTime.After(change.mtime) {
+				change.mtime = packObject.MTime
+				change.hash = hash
+			}
+		}
+	}
+	return result
 }
 
 // Push pushes new objects remotely.
 func (db *DB) Push() error {
+	return db.PushRemote(DefaultRemoteName)
+}
+
+// PushRemote pushes new objects to the remote with the given name.
+func (db *DB) PushRemote(name string) error {
 	// Implementation: We don't use a locking mechanism to prevent another repository to push at the same time.
 	// The NoteWriter is a personal tool and you are not expected to push from two repositories at the same time.
 
-	origin := db.Origin()
-	if origin == nil {
-		return errors.New("no remote found")
+	origin, err := db.RemoteByName(name)
+	if err != nil {
+		return err
 	}
 
 	// List of changes to push
@@ -719,7 +1039,7 @@ func (db *DB) Push() error {
 		}
 
 		// Read the origin index (must exist if commit-graph exists)
-		data, err := origin.GetObject("index")
+		data, err := readRemoteIndex(name, origin)
 		if errors.Is(err, ErrObjectNotExist) {
 			return errors.New("missing index in remote")
 		}
@@ -849,14 +1169,20 @@ func (db *DB) Push() error {
 		return err
 	}
 
-	// Update the origin ref
-	db.updateRef("origin", db.refs["main"])
+	// Update the remote ref
+	db.updateRef(remoteRefName(name), db.refs["main"])
 
 	return nil
 }
 
 // Reset reverts the latest add command.
 func (db *DB) Reset() error {
+	return db.Restore(nil)
+}
+
+// Restore reverts the staged objects whose relative paths match one of the given paths.
+// Other staged objects are left intact. All staged objects are reverted when no path is given.
+func (db *DB) Restore(relativePaths []string) error {
 	// Run all queries inside the same transaction
 	err := db.BeginTransaction()
 	if err != nil {
@@ -864,55 +1190,14 @@ func (db *DB) Reset() error {
 	}
 	defer db.RollbackTransaction()
 
-	// We must clear the staging area
+	var remainingStagingArea StagingArea
 	for _, obj := range db.index.StagingArea {
-
-		switch obj.State {
-		case Added:
-			// Deleted the object in SQL database
-			object := obj.ReadObject()
-			if object == nil {
-				return fmt.Errorf("unknown object %q", obj.OID)
-			}
-			// Mark for deletion
-			object.ForceState(Deleted)
-			if err := object.Save(); err != nil {
-				return err
-			}
-		case Deleted:
-			// Re-read object from latest commit
-			parentPackFile, err := db.ReadPackFile(obj.PreviousPackFileOID)
-			if err != nil {
-				return fmt.Errorf("missing parent pack file %q: %v", obj.PreviousPackFileOID, err)
-			}
-			original, found := parentPackFile.GetPackObject(obj.OID)
-			if !found {
-				return fmt.Errorf("missing object %q in pack file %s", obj.OID, obj.PreviousPackFileOID)
-			}
-			originalObject := original.ReadObject()
-			if originalObject == nil {
-				return fmt.Errorf("unknown object %q", obj.OID)
-			}
-			// Mark for restoration
-			originalObject.ForceState(Added)
-			originalObject.Save()
-		case Modified:
-			// Re-read object from latest commit
-			parentPackFile, err := db.ReadPackFile(obj.PreviousPackFileOID)
-			if err != nil {
-				return fmt.Errorf("missing parent pack file %q: %v", obj.PreviousPackFileOID, err)
-			}
-			original, found := parentPackFile.GetPackObject(obj.OID)
-			if !found {
-				return fmt.Errorf("missing object %q in pack file %s", obj.OID, obj.PreviousPackFileOID)
-			}
-			originalObject := original.ReadObject()
-			if originalObject == nil {
-				return fmt.Errorf("unknown object %q", obj.OID)
-			}
-			// Nothing to change. Simply save back.
-			originalObject.ForceState(Modified)
-			originalObject.Save()
+		if !stagingObjectMatchPaths(obj, relativePaths) {
+			remainingStagingArea = append(remainingStagingArea, obj)
+			continue
+		}
+		if err := db.restoreStagingObject(obj); err != nil {
+			return err
 		}
 	}
 
@@ -921,15 +1206,121 @@ func (db *DB) Reset() error {
 		return err
 	}
 	// And to persist the index
-	db.index.StagingArea = nil
+	db.index.StagingArea = remainingStagingArea
 	err = db.index.Save()
 
 	return err
 }
 
+// stagingObjectMatchPaths returns true if the staged object is located under one of the given paths.
+func stagingObjectMatchPaths(obj *StagingObject, relativePaths []string) bool {
+	if len(relativePaths) == 0 {
+		return true
+	}
+	return matchRelativePaths(stagingObjectRelativePath(obj), relativePaths)
+}
+
+// matchRelativePaths checks if the relative path is inside one of the given relative paths.
+func matchRelativePaths(relativePath string, relativePaths []string) bool {
+	if len(relativePaths) == 0 {
+		return true
+	}
+	for _, path := range relativePaths {
+		if path == "." || path == "" || relativePath == path || strings.HasPrefix(relativePath, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// stagingObjectRelativePath returns the relative path of the file or media containing the staged object.
+func stagingObjectRelativePath(obj *StagingObject) string {
+	return objectRelativePath(obj.ReadObject())
+}
+
+// objectRelativePath returns the relative path of the file or media containing the object.
+func objectRelativePath(obj StatefulObject) string {
+	switch object := obj.(type) {
+	case *File:
+		return object.RelativePath
+	case *Note:
+		return object.RelativePath
+	case *Flashcard:
+		return object.RelativePath
+	case *Media:
+		return object.RelativePath
+	case *Link:
+		return object.RelativePath
+	case *Reminder:
+		return object.RelativePath
+	}
+	return ""
+}
+
+// restoreStagingObject reverts a staged object in database to its committed version.
+func (db *DB) restoreStagingObject(obj *StagingObject) error {
+	switch obj.State {
+	case Added:
+		// Deleted the object in SQL database
+		object := obj.ReadObject()
+		if object == nil {
+			return fmt.Errorf("unknown object %q", obj.OID)
+		}
+		// Mark for deletion
+		object.ForceState(Deleted)
+		if err := object.Save(); err != nil {
+			return err
+		}
+	case Deleted:
+		// Re-read object from latest commit
+		parentPackFile, err := db.ReadPackFile(obj.PreviousPackFileOID)
+		if err != nil {
+			return fmt.Errorf("missing parent pack file %q: %v", obj.PreviousPackFileOID, err)
+		}
+		original, found := parentPackFile.GetPackObject(obj.OID)
+		if !found {
+			return fmt.Errorf("missing object %q in pack file %s", obj.OID, obj.PreviousPackFileOID)
+		}
+		originalObject := original.ReadObject()
+		if originalObject == nil {
+			return fmt.Errorf("unknown object %q", obj.OID)
+		}
+		// Mark for restoration
+		originalObject.ForceState(Added)
+		originalObject.Save()
+	case Modified:
+		// Re-read object from latest commit
+		parentPackFile, err := db.ReadPackFile(obj.PreviousPackFileOID)
+		if err != nil {
+			return fmt.Errorf("missing parent pack file %q: %v", obj.PreviousPackFileOID, err)
+		}
+		original, found := parentPackFile.GetPackObject(obj.OID)
+		if !found {
+			return fmt.Errorf("missing object %q in pack file %s", obj.OID, obj.PreviousPackFileOID)
+		}
+		originalObject := original.ReadObject()
+		if originalObject == nil {
+			return fmt.Errorf("unknown object %q", obj.OID)
+		}
+		// Nothing to change. Simply save back.
+		originalObject.ForceState(Modified)
+		originalObject.Save()
+	}
+	return nil
+}
+
 // Diff show the changes in the staging area.
 func (db *DB) Diff() (string, error) {
-	var diff strings.Builder
+	diffs, err := db.DiffObjects()
+	if err != nil {
+		return "", err
+	}
+	return FormatPatches(diffs), nil
+}
+
+// DiffObjects returns the changes of notes between the staging area and the last commit.
+func (db *DB) DiffObjects() ([]*ObjectDiff, error) {
+	var diffs []*ObjectDiff
 	for _, stagedObj := range db.index.StagingArea {
 		if stagedObj.Kind != "note" {
 			continue
@@ -938,19 +1329,180 @@ func (db *DB) Diff() (string, error) {
 
 		commitObj, err := db.ReadCommittedObject(stagedObj.OID)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		noteContentBefore := ""
+		var commitNote *Note
 		if commitObj != nil {
-			commitNote := commitObj.(*Note)
-			noteContentBefore = commitNote.ContentRaw
+			commitNote = commitObj.(*Note)
+		}
+		diffs = append(diffs, &ObjectDiff{
+			Before: commitNote,
+			After:  stagedNote,
+		})
+	}
+	sortObjectDiffs(diffs)
+
+	return diffs, nil
+}
+
+// DiffRemote shows changes between the origin and the local commits for notes under the given relative paths.
+func (db *DB) DiffRemote(relativePaths []string) (string, error) {
+	diffs, err := db.DiffRemoteObjects(relativePaths)
+	if err != nil {
+		return "", err
+	}
+	return FormatPatches(diffs), nil
+}
+
+// DiffRemoteObjects returns the changes of notes between the origin and the local commits for notes under the given relative paths.
+func (db *DB) DiffRemoteObjects(relativePaths []string) ([]*ObjectDiff, error) {
+	origin := db.Origin()
+	if origin == nil {
+		return nil, errors.New("no remote found")
+	}
+
+	// Read the origin index (missing before the first push)
+	originIndex := NewIndex()
+	data, err := readRemoteIndex(DefaultRemoteName, origin)
+	if err != nil && !errors.Is(err, ErrObjectNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := originIndex.Read(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+	originObjects := make(map[string]*IndexObject)
+	for _, object := range originIndex.Objects {
+		originObjects[object.OID] = object
+	}
+
+	// Pack files are downloaded at most once
+	originPackFiles := make(map[string]*PackFile)
+	readOriginNote := func(indexObject *IndexObject) (*Note, error) {
+		packFile, ok := originPackFiles[indexObject.PackFileOID]
+		if !ok {
+			data, err := origin.GetObject(OIDToPath(indexObject.PackFileOID))
+			if errors.Is(err, ErrObjectNotExist) {
+				return nil, fmt.Errorf("missing pack file %q", indexObject.PackFileOID)
+			} else if err != nil {
+				return nil, err
+			}
+			packFile = new(PackFile)
+			if err := packFile.Read(bytes.NewReader(data)); err != nil {
+				return nil, err
+			}
+			originPackFiles[indexObject.PackFileOID] = packFile
+		}
+		packObject, ok := packFile.GetPackObject(indexObject.OID)
+		if !ok {
+			return nil, nil
+		}
+		return packObject.ReadObject().(*Note), nil
+	}
+
+	var diffs []*ObjectDiff
+
+	// Diff added or modified notes
+	for _, localObject := range db.index.Objects {
+		if localObject.Kind != "note" {
+			continue
+		}
+		originObject, found := originObjects[localObject.OID]
+		if found && originObject.PackFileOID == localObject.PackFileOID {
+			// Same version
+			continue
+		}
+		committedObject, err := db.ReadCommittedObject(localObject.OID)
+		if err != nil {
+			return nil, err
+		}
+		if committedObject == nil {
+			continue
+		}
+		localNote := committedObject.(*Note)
+		if !matchRelativePaths(localNote.RelativePath, relativePaths) {
+			continue
+		}
+		var originNote *Note
+		if found {
+			originNote, err = readOriginNote(originObject)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if localNote.State() == Deleted {
+			// Deleted objects are kept in the index
+			if originNote == nil || originNote.State() == Deleted {
+				continue
+			}
+			localNote = nil
+		}
+		objectDiff := &ObjectDiff{
+			Before: originNote,
+			After:  localNote,
+		}
+		if objectDiff.ContentBefore() == objectDiff.ContentAfter() {
+			continue
+		}
+		diffs = append(diffs, objectDiff)
+	}
+
+	// Diff deleted notes
+	for _, originObject := range originIndex.Objects {
+		if originObject.Kind != "note" {
+			continue
+		}
+		if _, found := db.index.objectsRef[originObject.OID]; found {
+			continue
+		}
+		originNote, err := readOriginNote(originObject)
+		if err != nil {
+			return nil, err
+		}
+		if originNote == nil || !matchRelativePaths(originNote.RelativePath, relativePaths) {
+			continue
+		}
+		diffs = append(diffs, &ObjectDiff{
+			Before: originNote,
+		})
+	}
+	sortObjectDiffs(diffs)
+
+	return diffs, nil
+}
+
+// DeletedNotesSince returns the last known version of notes deleted after the given time,
+// searching in commits and in the staging area.
+func (db *DB) DeletedNotesSince(t time.Time) ([]*Note, error) {
+	var results []*Note
+	appendIfDeleted := func(packObject *PackObject) {
+		if packObject.Kind != "note" || packObject.State != Deleted || !packObject.MTime.After(t) {
+			return
+		}
+		results = append(results, packObject.ReadObject().(*Note))
+	}
+
+	for _, commit := range db.commitGraph.Commits {
+		if commit.CTime.Before(t) {
+			// Objects cannot be deleted after the creation of their commit
+			continue
+		}
+		packFiles, err := db.ReadPackFilesFromCommit(commit)
+		if err != nil {
+			return nil, err
+		}
+		for _, packFile := range packFiles {
+			for _, packObject := range packFile.PackObjects {
+				appendIfDeleted(packObject)
+			}
 		}
-		noteContentAfter := stagedNote.ContentRaw
-		patch := godiffpatch.GeneratePatch(stagedNote.RelativePath, noteContentBefore, noteContentAfter)
-		diff.WriteString(patch)
+	}
+	for _, stagingObject := range db.index.StagingArea {
+		appendIfDeleted(&stagingObject.PackObject)
 	}
 
-	return diff.String(), nil
+	return results, nil
 }
 
 func (db *DB) PrintIndex() {
@@ -1086,6 +1638,181 @@ func (db *DB) GC() error {
 	return db.index.Save()
 }
 
+// GCResult reports the files reclaimed by an aggressive garbage collection.
+type GCResult struct {
+	// OIDs of unreachable pack files
+	PackFiles []string
+	// OIDs of unreachable blobs
+	Blobs []string
+	// OIDs of pack files rewritten with a higher compression level
+	RecompressedPackFiles []string
+	// Total number of bytes reclaimed on disk
+	ReclaimedBytes int64
+}
+
+// GCAggressive removes all files under .nt/objects that are no longer reachable
+// from the commit graph or the index, and recompresses the remaining pack files.
+// Nothing is modified on disk when the dry-run mode is enabled.
+func (db *DB) GCAggressive() (*GCResult, error) {
+	dryRun := CurrentConfig().DryRun
+
+	if !dryRun {
+		// Start with the standard housekeeping to drop obsolete revisions first
+		if err := db.GC(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := new(GCResult)
+
+	// Stage 1: Reachability analysis
+	// -------
+
+	CurrentLogger().Info("Searching for reachable objects...")
+
+	reachablePackFiles := make(map[string]bool)
+	reachableBlobs := make(map[string]bool)
+
+	// Blobs are referenced by medias, whatever the revision
+	markBlobs := func(packObject *PackObject) error {
+		if packObject.Kind != "media" {
+			return nil
+		}
+		media := new(Media)
+		if err := packObject.Data.Unmarshal(media); err != nil {
+			return err
+		}
+		for _, blob := range media.BlobRefs {
+			reachableBlobs[blob.OID] = true
+		}
+		return nil
+	}
+
+	for _, commit := range db.commitGraph.Commits {
+		for _, packFileRef := range commit.PackFiles {
+			reachablePackFiles[packFileRef.OID] = true
+		}
+	}
+	for packFileOID := range db.index.PackFiles {
+		reachablePackFiles[packFileOID] = true
+	}
+	for _, indexObject := range db.index.Objects {
+		reachablePackFiles[indexObject.PackFileOID] = true
+	}
+	for _, stagingObject := range db.index.StagingArea {
+		reachablePackFiles[stagingObject.PreviousPackFileOID] = true
+		if err := markBlobs(&stagingObject.PackObject); err != nil {
+			return nil, err
+		}
+	}
+	for packFileOID := range reachablePackFiles {
+		if packFileOID == "" || !db.ObjectExists(packFileOID) {
+			continue
+		}
+		packFile, err := db.ReadPackFile(packFileOID)
+		if err != nil {
+			return nil, err
+		}
+		for _, packObject := range packFile.PackObjects {
+			if err := markBlobs(packObject); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Stage 2: Unreachable files reclaiming
+	// -------
+
+	CurrentLogger().Info("Reclaiming unreachable files...")
+
+	objectsPath := filepath.Join(CurrentConfig().RootDirectory, ".nt/objects/")
+	if _, err := os.Stat(objectsPath); os.IsNotExist(err) {
+		// Nothing committed yet
+		return result, nil
+	}
+	paths, err := filesystem.ListFiles(objectsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		oid := filepath.Base(path)
+		if path != filepath.Join(objectsPath, OIDToPath(oid)) {
+			// Not an object (ex: .nt/objects/info/commit-graph)
+			continue
+		}
+		if reachablePackFiles[oid] || reachableBlobs[oid] {
+			continue
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Blobs are raw files whereas pack files are YAML documents
+		if packFile, err := NewPackFileFromPath(path); err == nil && packFile.OID == oid {
+			result.PackFiles = append(result.PackFiles, oid)
+		} else {
+			result.Blobs = append(result.Blobs, oid)
+		}
+		result.ReclaimedBytes += stat.Size()
+
+		if dryRun {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+		CurrentLogger().Infof("💾 Deleted unreachable object %s", oid)
+	}
+
+	// Stage 3: Pack file recompression
+	// -------
+
+	CurrentLogger().Info("Recompressing pack files...")
+
+	for _, commit := range db.commitGraph.Commits {
+		for _, packFileRef := range commit.PackFiles {
+			packFile, err := db.ReadPackFile(packFileRef.OID)
+			if err != nil {
+				return nil, err
+			}
+			oldSize, err := packFile.Size()
+			if err != nil {
+				return nil, err
+			}
+			for _, packObject := range packFile.PackObjects {
+				data, err := packObject.Data.Recompress(CompressionBest)
+				if err != nil {
+					return nil, err
+				}
+				packObject.Data = data
+			}
+			newSize, err := packFile.Size()
+			if err != nil {
+				return nil, err
+			}
+			if newSize >= oldSize {
+				// Already optimal
+				continue
+			}
+
+			result.RecompressedPackFiles = append(result.RecompressedPackFiles, packFile.OID)
+			result.ReclaimedBytes += oldSize - newSize
+
+			if dryRun {
+				continue
+			}
+			if err := packFile.Save(); err != nil {
+				return nil, err
+			}
+			CurrentLogger().Infof("💾 Recompressed pack file %s", packFile.OID)
+		}
+	}
+
+	return result, nil
+}
+
 // CompressCommit remove obsolete pack objects and merge small pack files together.
 func (db *DB) CompressCommit(commit *Commit) (bool, error) {
 	commitRevised := false
@@ -1163,6 +1890,14 @@ func (db *DB) CompressCommit(commit *Commit) (bool, error) {
 
 /* Utility */
 
+// remoteRefName returns the name of the ref tracking the last known commit of a remote.
+func remoteRefName(remoteName string) string {
+	if remoteName == "" {
+		return DefaultRemoteName
+	}
+	return remoteName
+}
+
 // Ref returns the commit OID for the given ref
 func (db *DB) Ref(name string) (string, bool) {
 	value, ok := db.refs[name]
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+)
+
+// Number of days of reviews used to compute the retention of a deck
+const DeckStatsWindowInDays = 30
+
+// Flashcards with an interval of at least this number of days are considered mature (same as Anki)
+const MatureIntervalInDays = 21
+
+// DeckStats summarizes the scheduling state and the recent reviews of the flashcards in a deck.
+type DeckStats struct {
+	Name string `json:"name"`
+
+	// Flashcards
+	Flashcards int `json:"flashcards"`
+	New        int `json:"new"`    // Never studied
+	Young      int `json:"young"`  // Studied with an interval lower than MatureIntervalInDays
+	Mature     int `json:"mature"` // Studied with an interval of at least MatureIntervalInDays
+	DueToday   int `json:"dueToday"`
+
+	// Averages over studied flashcards (zero when no flashcard was studied)
+	AverageEaseFactor     float64 `json:"averageEaseFactor"`
+	AverageIntervalInDays float64 `json:"averageIntervalInDays"`
+
+	// Reviews completed during the last WindowInDays days
+	WindowInDays int `json:"windowInDays"`
+	Reviews      int `json:"reviews"`
+	// Share of reviews not answered "again" or "too-hard" (between 0 and 1)
+	Retention float64 `json:"retention"`
+	// Number of flashcards studied for the first time per day on average
+	NewPerDay float64 `json:"newPerDay"`
+
+	// Deck settings to compare with the actual numbers
+	BoostFactor         int `json:"boostFactor"`
+	NewFlashcardsPerDay int `json:"newFlashcardsPerDay"`
+	MaxFlashcardsPerDay int `json:"maxFlashcardsPerDay"`
+}
+
+// DeckStats computes statistics about the flashcards of a deck declared in the configuration.
+func (r *Repository) DeckStats(deckName string) (*DeckStats, error) {
+	deck, ok := CurrentConfig().ConfigFile.Deck[deckName]
+	if !ok {
+		return nil, fmt.Errorf("unknown deck %q", deckName)
+	}
+
+	flashcards, err := r.findDeckFlashcards(deck)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
+	year, month, day := now.Date()
+	endOfToday := time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+	windowStart := now.AddDate(0, 0, -DeckStatsWindowInDays)
+
+	result := &DeckStats{
+		Name:                deck.Name,
+		Flashcards:          len(flashcards),
+		WindowInDays:        DeckStatsWindowInDays,
+		BoostFactor:         deck.BoostFactor,
+		NewFlashcardsPerDay: deck.NewFlashcardsPerDay,
+		MaxFlashcardsPerDay: deck.MaxFlashcardsPerDay,
+	}
+
+	flashcardOIDs := make(map[string]bool)
+	var easeFactors, intervals []float64
+	for _, flashcard := range flashcards {
+		flashcardOIDs[flashcard.OID] = true
+
+		if flashcard.StudiedAt.IsZero() {
+			result.New++
+			continue
+		}
+		if !flashcard.DueAt.IsZero() && flashcard.DueAt.Before(endOfToday) {
+			result.DueToday++
+		}
+		if easeFactor, ok := settingAsFloat(flashcard.Settings, "easeFactor"); ok {
+			easeFactors = append(easeFactors, easeFactor)
+		}
+		interval, ok := settingAsFloat(flashcard.Settings, "interval")
+		if ok {
+			intervals = append(intervals, interval)
+		}
+		if ok && interval >= MatureIntervalInDays {
+			result.Mature++
+		} else {
+			result.Young++
+		}
+	}
+	result.AverageEaseFactor = average(easeFactors)
+	result.AverageIntervalInDays = average(intervals)
+
+	studies, err := CurrentDB().ReadStudies()
+	if err != nil {
+		return nil, err
+	}
+	firstReviews := make(map[string]time.Time)
+	var correct int
+	for _, study := range studies {
+		for _, review := range study.Reviews {
+			if !flashcardOIDs[review.FlashcardOID] {
+				continue
+			}
+			if first, ok := firstReviews[review.FlashcardOID]; !ok || review.CompletedAt.Before(first) {
+				firstReviews[review.FlashcardOID] = review.CompletedAt
+			}
+			if review.CompletedAt.Before(windowStart) {
+				continue
+			}
+			result.Reviews++
+			if review.Feedback != FeedbackAgain && review.Feedback != FeedbackTooHard {
+				correct++
+			}
+		}
+	}
+	if result.Reviews > 0 {
+		result.Retention = float64(correct) / float64(result.Reviews)
+	}
+	var newStudied int
+	for _, first := range firstReviews {
+		if !first.Before(windowStart) {
+			newStudied++
+		}
+	}
+	result.NewPerDay = float64(newStudied) / float64(DeckStatsWindowInDays)
+
+	return result, nil
+}
+
+// findDeckFlashcards returns the flashcards matching the query of a deck.
+func (r *Repository) findDeckFlashcards(deck *ConfigDeck) ([]*Flashcard, error) {
+	query, err := ParseQuery(deck.Query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query for deck %q: %w", deck.Name, err)
+	}
+	query.Kinds = []string{string(KindFlashcard)}
+	query.Limit = -1 // No limit
+	query.Offset = 0
+	results, err := r.searchNotesByQuery(query, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var flashcards []*Flashcard
+	for _, result := range results {
+		flashcard, err := r.LoadFlashcardByNoteOID(result.Note.OID)
+		if err != nil {
+			return nil, err
+		}
+		if flashcard != nil {
+			flashcards = append(flashcards, flashcard)
+		}
+	}
+	return flashcards, nil
+}
+
+// ReadStudies reads all committed studies, starting with the oldest one.
+func (db *DB) ReadStudies() ([]*Study, error) {
+	// Group studies by pack file to read every pack file once
+	studyOIDsByPackFile := make(map[string][]string)
+	var packFileOIDs []string
+	for _, indexObject := range db.index.Objects {
+		if indexObject.Kind != "study" {
+			continue
+		}
+		if _, ok := studyOIDsByPackFile[indexObject.PackFileOID]; !ok {
+			packFileOIDs = append(packFileOIDs, indexObject.PackFileOID)
+		}
+		studyOIDsByPackFile[indexObject.PackFileOID] = append(studyOIDsByPackFile[indexObject.PackFileOID], indexObject.OID)
+	}
+
+	var results []*Study
+	for _, packFileOID := range packFileOIDs {
+		packFile, err := db.ReadPackFile(packFileOID)
+		if err != nil {
+			return nil, err
+		}
+		for _, oid := range studyOIDsByPackFile[packFileOID] {
+			packObject, ok := packFile.GetPackObject(oid)
+			if !ok {
+				continue
+			}
+			if study, ok := packObject.ReadObject().(*Study); ok {
+				results = append(results, study)
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartedAt.Before(results[j].StartedAt)
+	})
+	return results, nil
+}
+
+// settingAsFloat returns a numeric SRS setting whatever the type used when decoding it.
+func settingAsFloat(settings map[string]any, name string) (float64, bool) {
+	switch value := settings[name].(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case float32:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+// average returns the arithmetic mean or zero for an empty slice.
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeckStats(t *testing.T) {
+	now := HumanTime(t, "2023-02-03 12:00")
+	UseSequenceOID(t)
+	FreezeAt(t, now)
+
+	SetUpRepositoryFromTempDir(t)
+	origin := t.TempDir()
+	CurrentConfig().ConfigFile.Remote = ConfigRemote{
+		Type: "fs",
+		Dir:  origin,
+	}
+	CurrentConfig().ConfigFile.Deck = map[string]*ConfigDeck{
+		"english": {
+			Name:                "English",
+			Query:               "path:english",
+			BoostFactor:         DefaultSRSBoostFactor,
+			NewFlashcardsPerDay: 10,
+		},
+	}
+
+	MustWriteFile(t, "english.md", `# English
+
+## Flashcard: Car
+
+Translate _Voiture_
+
+---
+
+**Car**
+
+## Flashcard: Airplane
+
+Translate _Avion_
+
+---
+
+**Airplane**
+
+## Flashcard: Motorbike
+
+Translate _Moto_
+
+---
+
+**Motorbike**
+`)
+	MustWriteFile(t, "spanish.md", `# Spanish
+
+## Flashcard: Coche
+
+Translate _Voiture_
+
+---
+
+**Coche**
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+	err = CurrentDB().Commit("initial commit")
+	require.NoError(t, err)
+	err = CurrentDB().Push()
+	require.NoError(t, err)
+
+	flashcardCar := MustFindFlashcardByShortTitle(t, "Car")
+	flashcardAirplane := MustFindFlashcardByShortTitle(t, "Airplane")
+	flashcardCoche := MustFindFlashcardByShortTitle(t, "Coche")
+
+	// No review yet
+	stats, err := CurrentRepository().DeckStats("english")
+	require.NoError(t, err)
+	assert.Equal(t, "English", stats.Name)
+	assert.Equal(t, 3, stats.Flashcards)
+	assert.Equal(t, 3, stats.New)
+	assert.Equal(t, 0, stats.Reviews)
+	assert.Zero(t, stats.Retention)
+
+	// Simulate studies from another device
+	oldStudyTime := now.Add(-40 * Day) // Outside the window
+	oldStudy := &Study{
+		OID:       NewOID(),
+		StartedAt: oldStudyTime,
+		EndedAt:   oldStudyTime.Add(1 * time.Minute),
+		Reviews: []*Review{
+			{
+				FlashcardOID: flashcardCar.OID,
+				Feedback:     FeedbackEasy,
+				CompletedAt:  oldStudyTime.Add(30 * time.Second),
+				DueAt:        oldStudyTime.Add(1 * Day),
+				Settings: map[string]any{
+					"interval":   1,
+					"easeFactor": 2.5,
+				},
+			},
+		},
+	}
+	studyTime := now.Add(-2 * time.Hour)
+	study := &Study{
+		OID:       NewOID(),
+		StartedAt: studyTime,
+		EndedAt:   studyTime.Add(1 * time.Minute),
+		Reviews: []*Review{
+			{
+				FlashcardOID: flashcardCar.OID,
+				Feedback:     FeedbackGood,
+				CompletedAt:  studyTime.Add(30 * time.Second),
+				DueAt:        studyTime.Add(25 * Day),
+				Settings: map[string]any{
+					"interval":   25,
+					"easeFactor": 2.5,
+				},
+			},
+			{
+				FlashcardOID: flashcardAirplane.OID,
+				Feedback:     FeedbackAgain,
+				CompletedAt:  studyTime.Add(1 * time.Minute),
+				DueAt:        studyTime.Add(10 * time.Minute),
+				Settings: map[string]any{
+					"interval":   0.1,
+					"easeFactor": 2.0,
+				},
+			},
+			{
+				// Not in the deck
+				FlashcardOID: flashcardCoche.OID,
+				Feedback:     FeedbackAgain,
+				CompletedAt:  studyTime.Add(1 * time.Minute),
+				DueAt:        studyTime.Add(10 * time.Minute),
+				Settings: map[string]any{
+					"interval":   0.1,
+					"easeFactor": 2.0,
+				},
+			},
+		},
+	}
+	packFile := NewPackFile()
+	packFile.AppendObject(oldStudy)
+	packFile.AppendObject(study)
+	err = packFile.SaveTo(filepath.Join(origin, OIDToPath(packFile.OID)))
+	require.NoError(t, err)
+	originCGPath := filepath.Join(origin, "info/commit-graph")
+	originCG, err := NewCommitGraphFromPath(originCGPath)
+	require.NoError(t, err)
+	originCG.AppendCommit(NewCommitFromPackFiles(packFile))
+	err = originCG.SaveTo(originCGPath)
+	require.NoError(t, err)
+	err = CurrentDB().Pull()
+	require.NoError(t, err)
+
+	stats, err = CurrentRepository().DeckStats("english")
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Flashcards)
+	assert.Equal(t, 1, stats.New)
+	assert.Equal(t, 1, stats.Young)
+	assert.Equal(t, 1, stats.Mature)
+	assert.Equal(t, 1, stats.DueToday)
+	assert.InDelta(t, 2.25, stats.AverageEaseFactor, 0.001)
+	assert.InDelta(t, 12.55, stats.AverageIntervalInDays, 0.001)
+	assert.Equal(t, DeckStatsWindowInDays, stats.WindowInDays)
+	assert.Equal(t, 2, stats.Reviews)
+	assert.InDelta(t, 0.5, stats.Retention, 0.001)
+	assert.InDelta(t, 1.0/DeckStatsWindowInDays, stats.NewPerDay, 0.001) // The car was first studied before the window
+	assert.Equal(t, 10, stats.NewFlashcardsPerDay)
+
+	// Unknown deck
+	_, err = CurrentRepository().DeckStats("unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,331 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	godiffpatch "github.com/sourcegraph/go-diff-patch"
+)
+
+// Types of changes of a note
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeDeleted  = "deleted"
+)
+
+// ObjectDiff is a change of a note between two versions.
+type ObjectDiff struct {
+	// Previous version (nil for added notes)
+	Before *Note
+	// New version (nil for deleted notes)
+	After *Note
+}
+
+// AttributeChange is a change of a single attribute of a note.
+type AttributeChange struct {
+	Name string `json:"name"`
+	// Previous value (null for added attributes)
+	Before any `json:"before"`
+	// New value (null for removed attributes)
+	After any `json:"after"`
+}
+
+// note returns the most recent version of the note.
+func (d *ObjectDiff) note() *Note {
+	if d.After != nil {
+		return d.After
+	}
+	return d.Before
+}
+
+// RelativePath returns the path of the file containing the note.
+func (d *ObjectDiff) RelativePath() string {
+	return d.note().RelativePath
+}
+
+// OID returns the OID of the note.
+func (d *ObjectDiff) OID() string {
+	return d.note().OID
+}
+
+// Change returns the type of change (added, modified, or deleted).
+func (d *ObjectDiff) Change() string {
+	if d.Before == nil {
+		return ChangeAdded
+	}
+	if d.After == nil {
+		return ChangeDeleted
+	}
+	return ChangeModified
+}
+
+// ContentBefore returns the raw content of the previous version (empty for added notes).
+func (d *ObjectDiff) ContentBefore() string {
+	if d.Before == nil {
+		return ""
+	}
+	return d.Before.ContentRaw
+}
+
+// ContentAfter returns the raw content of the new version (empty for deleted notes).
+func (d *ObjectDiff) ContentAfter() string {
+	if d.After == nil {
+		return ""
+	}
+	return d.After.ContentRaw
+}
+
+// Patch returns the unified diff of the note content.
+func (d *ObjectDiff) Patch() string {
+	return godiffpatch.GeneratePatch(d.RelativePath(), d.ContentBefore(), d.ContentAfter())
+}
+
+// AttributeChanges returns the attributes added, removed, or updated, sorted by name.
+func (d *ObjectDiff) AttributeChanges() []AttributeChange {
+	var attributesBefore, attributesAfter map[string]any
+	if d.Before != nil {
+		attributesBefore = d.Before.Attributes
+	}
+	if d.After != nil {
+		attributesAfter = d.After.Attributes
+	}
+
+	var names []string
+	for name := range attributesBefore {
+		names = append(names, name)
+	}
+	for name := range attributesAfter {
+		if _, ok := attributesBefore[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []AttributeChange{}
+	for _, name := range names {
+		before := attributesBefore[name]
+		after := attributesAfter[name]
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		changes = append(changes, AttributeChange{
+			Name:   name,
+			Before: before,
+			After:  after,
+		})
+	}
+	return changes
+}
+
+// ToJSON returns a machine-readable representation of the change.
+func (d *ObjectDiff) ToJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RelativePath     string            `json:"relativePath"`
+		Kind             NoteKind          `json:"kind"`
+		OID              string            `json:"oid"`
+		Change           string            `json:"change"`
+		Before           string            `json:"before"`
+		After            string            `json:"after"`
+		AttributeChanges []AttributeChange `json:"attributeChanges"`
+	}{
+		RelativePath:     d.RelativePath(),
+		Kind:             d.note().NoteKind,
+		OID:              d.OID(),
+		Change:           d.Change(),
+		Before:           d.ContentBefore(),
+		After:            d.ContentAfter(),
+		AttributeChanges: d.AttributeChanges(),
+	})
+}
+
+// sortObjectDiffs sorts changes by file, preserving the order of notes inside a file.
+func sortObjectDiffs(diffs []*ObjectDiff) {
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].RelativePath() < diffs[j].RelativePath()
+	})
+}
+
+// FormatPatches concatenates the unified diffs of changes.
+func FormatPatches(diffs []*ObjectDiff) string {
+	var diff strings.Builder
+	for _, objectDiff := range diffs {
+		diff.WriteString(objectDiff.Patch())
+	}
+	return diff.String()
+}
+
+// DiffStatCounts counts changed objects of a kind.
+type DiffStatCounts struct {
+	Added    int
+	Modified int
+	Deleted  int
+}
+
+// Total returns the number of changed objects.
+func (c DiffStatCounts) Total() int {
+	return c.Added + c.Modified + c.Deleted
+}
+
+// Add increments the counter matching the type of change.
+func (c *DiffStatCounts) Add(change string) {
+	switch change {
+	case ChangeAdded:
+		c.Added++
+	case ChangeModified:
+		c.Modified++
+	case ChangeDeleted:
+		c.Deleted++
+	}
+}
+
+// Merge adds the counts of another counter.
+func (c *DiffStatCounts) Merge(other DiffStatCounts) {
+	c.Added += other.Added
+	c.Modified += other.Modified
+	c.Deleted += other.Deleted
+}
+
+// String returns a compact representation (ex: "+1 ~2 -3"), ignoring zero counts.
+func (c DiffStatCounts) String() string {
+	var parts []string
+	if c.Added > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", c.Added))
+	}
+	if c.Modified > 0 {
+		parts = append(parts, fmt.Sprintf("~%d", c.Modified))
+	}
+	if c.Deleted > 0 {
+		parts = append(parts, fmt.Sprintf("-%d", c.Deleted))
+	}
+	return strings.Join(parts, " ")
+}
+
+// DiffStat summarizes the changes inside a file.
+type DiffStat struct {
+	RelativePath string
+	Notes        DiffStatCounts
+	Flashcards   DiffStatCounts
+	// Medias referenced by notes
+	Medias DiffStatCounts
+	// Go links declared by notes
+	Links DiffStatCounts
+}
+
+// Merge adds the counts of another summary.
+func (s *DiffStat) Merge(other *DiffStat) {
+	s.Notes.Merge(other.Notes)
+	s.Flashcards.Merge(other.Flashcards)
+	s.Medias.Merge(other.Medias)
+	s.Links.Merge(other.Links)
+}
+
+// String returns the non-empty counts (ex: "notes +1 ~2, links -1").
+func (s *DiffStat) String() string {
+	var parts []string
+	for _, category := range []struct {
+		name   string
+		counts DiffStatCounts
+	}{
+		{"notes", s.Notes},
+		{"flashcards", s.Flashcards},
+		{"medias", s.Medias},
+		{"links", s.Links},
+	} {
+		if category.counts.Total() > 0 {
+			parts = append(parts, category.name+" "+category.counts.String())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// NewDiffStats summarizes changes per file, sorted by relative path.
+// Flashcards, medias, and links are determined from the content of changed notes.
+func NewDiffStats(diffs []*ObjectDiff) []*DiffStat {
+	var stats []*DiffStat
+	statsByPath := make(map[string]*DiffStat)
+	for _, diff := range diffs {
+		stat, ok := statsByPath[diff.RelativePath()]
+		if !ok {
+			stat = &DiffStat{RelativePath: diff.RelativePath()}
+			statsByPath[diff.RelativePath()] = stat
+			stats = append(stats, stat)
+		}
+
+		stat.Notes.Add(diff.Change())
+		if diff.note().NoteKind == KindFlashcard {
+			stat.Flashcards.Add(diff.Change())
+		}
+
+		// Compare medias
+		mediasBefore := make(map[string]bool)
+		for _, media := range ParseMedias(diff.RelativePath(), diff.ContentBefore()) {
+			mediasBefore[media.RelativePath] = true
+		}
+		mediasAfter := make(map[string]bool)
+		for _, media := range ParseMedias(diff.RelativePath(), diff.ContentAfter()) {
+			mediasAfter[media.RelativePath] = true
+			if !mediasBefore[media.RelativePath] {
+				stat.Medias.Add(ChangeAdded)
+			}
+		}
+		for path := range mediasBefore {
+			if !mediasAfter[path] {
+				stat.Medias.Add(ChangeDeleted)
+			}
+		}
+
+		// Compare links using their Go names
+		linksBefore := make(map[string]parsedGoLink)
+		for _, link := range parseGoLinks(diff.ContentBefore()) {
+			linksBefore[link.GoName] = link
+		}
+		linksAfter := make(map[string]parsedGoLink)
+		for _, link := range parseGoLinks(diff.ContentAfter()) {
+			linksAfter[link.GoName] = link
+			linkBefore, ok := linksBefore[link.GoName]
+			if !ok {
+				stat.Links.Add(ChangeAdded)
+			} else if linkBefore != link {
+				stat.Links.Add(ChangeModified)
+			}
+		}
+		for goName := range linksBefore {
+			if _, ok := linksAfter[goName]; !ok {
+				stat.Links.Add(ChangeDeleted)
+			}
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].RelativePath < stats[j].RelativePath
+	})
+	return stats
+}
+
+// FormatDiffStats returns one line per file followed by a total line (like git diff --stat).
+func FormatDiffStats(stats []*DiffStat) string {
+	if len(stats) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, stat := range stats {
+		width = max(width, len(stat.RelativePath))
+	}
+
+	var sb strings.Builder
+	total := &DiffStat{}
+	for _, stat := range stats {
+		sb.WriteString(fmt.Sprintf(" %-*s | %s\n", width, stat.RelativePath, stat))
+		total.Merge(stat)
+	}
+	filesLabel := "files"
+	if len(stats) == 1 {
+		filesLabel = "file"
+	}
+	sb.WriteString(fmt.Sprintf(" %d %s changed: %s\n", len(stats), filesLabel, total))
+	return sb.String()
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectDiff(t *testing.T) {
+
+	t.Run("Modified", func(t *testing.T) {
+		diff := &ObjectDiff{
+			Before: &Note{
+				OID:          "0000000000000000000000000000000000000001",
+				NoteKind:     KindReference,
+				RelativePath: "go.md",
+				ContentRaw:   "Go was designed at Google.",
+				Attributes: map[string]any{
+					"source": "https://go.dev",
+					"tags":   []any{"go"},
+				},
+			},
+			After: &Note{
+				OID:          "0000000000000000000000000000000000000001",
+				NoteKind:     KindReference,
+				RelativePath: "go.md",
+				ContentRaw:   "Go was designed at Google in 2007.",
+				Attributes: map[string]any{
+					"tags":   []any{"go"},
+					"author": "Rob Pike",
+				},
+			},
+		}
+		assert.Equal(t, ChangeModified, diff.Change())
+		assert.Equal(t, []AttributeChange{
+			{Name: "author", Before: nil, After: "Rob Pike"},
+			{Name: "source", Before: "https://go.dev", After: nil},
+		}, diff.AttributeChanges())
+
+		data, err := diff.ToJSON()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"relativePath": "go.md",
+			"kind": "reference",
+			"oid": "0000000000000000000000000000000000000001",
+			"change": "modified",
+			"before": "Go was designed at Google.",
+			"after": "Go was designed at Google in 2007.",
+			"attributeChanges": [
+				{"name": "author", "before": null, "after": "Rob Pike"},
+				{"name": "source", "before": "https://go.dev", "after": null}
+			]
+		}`, string(data))
+	})
+
+	t.Run("Added and deleted", func(t *testing.T) {
+		note := &Note{
+			OID:          "0000000000000000000000000000000000000002",
+			NoteKind:     KindNote,
+			RelativePath: "go.md",
+			ContentRaw:   "Hello",
+		}
+		added := &ObjectDiff{After: note}
+		deleted := &ObjectDiff{Before: note}
+		assert.Equal(t, ChangeAdded, added.Change())
+		assert.Equal(t, ChangeDeleted, deleted.Change())
+		assert.Equal(t, "go.md", deleted.RelativePath())
+		assert.Empty(t, added.AttributeChanges())
+		assert.Contains(t, added.Patch(), "+Hello")
+		assert.Contains(t, deleted.Patch(), "-Hello")
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		diffs := []*ObjectDiff{
+			{After: &Note{OID: "1", RelativePath: "python.md"}},
+			{After: &Note{OID: "2", RelativePath: "go.md"}},
+			{After: &Note{OID: "3", RelativePath: "python.md"}},
+		}
+		sortObjectDiffs(diffs)
+		assert.Equal(t, "2", diffs[0].OID())
+		assert.Equal(t, "1", diffs[1].OID()) // Order inside a file is preserved
+		assert.Equal(t, "3", diffs[2].OID())
+	})
+}
+
+func TestDiffStats(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	diffs := []*ObjectDiff{
+		{
+			// Modified note with a new media and a removed link
+			Before: &Note{
+				RelativePath: "go.md",
+				NoteKind:     KindReference,
+				ContentRaw:   `[Golang](https://go.dev "#go/go") was designed at Google.`,
+			},
+			After: &Note{
+				RelativePath: "go.md",
+				NoteKind:     KindReference,
+				ContentRaw:   "Golang was designed at Google.\n\n![Logo](./medias/go.svg)",
+			},
+		},
+		{
+			// Added flashcard
+			After: &Note{
+				RelativePath: "go.md",
+				NoteKind:     KindFlashcard,
+				ContentRaw:   "What does the Golang logo represent?\n\n---\n\nA gopher.",
+			},
+		},
+		{
+			// Deleted note with a link
+			Before: &Note{
+				RelativePath: "python.md",
+				NoteKind:     KindNote,
+				ContentRaw:   `[Python](https://python.org "#go/python") is popular.`,
+			},
+		},
+	}
+
+	stats := NewDiffStats(diffs)
+	require.Len(t, stats, 2)
+	assert.Equal(t, &DiffStat{
+		RelativePath: "go.md",
+		Notes:        DiffStatCounts{Added: 1, Modified: 1},
+		Flashcards:   DiffStatCounts{Added: 1},
+		Medias:       DiffStatCounts{Added: 1},
+		Links:        DiffStatCounts{Deleted: 1},
+	}, stats[0])
+	assert.Equal(t, &DiffStat{
+		RelativePath: "python.md",
+		Notes:        DiffStatCounts{Deleted: 1},
+		Links:        DiffStatCounts{Deleted: 1},
+	}, stats[1])
+
+	expected := "" +
+		" go.md     | notes +1 ~1, flashcards +1, medias +1, links -1\n" +
+		" python.md | notes -1, links -1\n" +
+		" 2 files changed: notes +1 ~1 -1, flashcards +1, medias +1, links -2\n"
+	assert.Equal(t, expected, FormatDiffStats(stats))
+	assert.Equal(t, "", FormatDiffStats(nil))
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+type DiagnosticStatus string
+
+const (
+	DiagnosticPass DiagnosticStatus = "pass"
+	DiagnosticWarn DiagnosticStatus = "warn"
+	DiagnosticFail DiagnosticStatus = "fail"
+)
+
+// Diagnostic is the result of a single check run by Diagnose.
+type Diagnostic struct {
+	Name    string           `json:"name"`
+	Status  DiagnosticStatus `json:"status"`
+	Message string           `json:"message"`
+	// Remediation hint (empty when the check passes)
+	Hint string `json:"hint,omitempty"`
+}
+
+// Diagnose checks the repository for common problems.
+func (r *Repository) Diagnose() []Diagnostic {
+	return []Diagnostic{
+		diagnoseConfig(),
+		diagnoseMigrations(),
+		diagnoseFTSTokenizer(),
+		diagnosePackFiles(),
+		diagnoseRemote(),
+		diagnoseDanglingMedias(),
+	}
+}
+
+// diagnoseConfig checks the configuration is valid.
+func diagnoseConfig() Diagnostic {
+	result := Diagnostic{Name: "config"}
+	if err := CurrentConfig().Check(); err != nil {
+		result.Status = DiagnosticFail
+		result.Message = err.Error()
+		result.Hint = "Fix .nt/config (see nt config validate)"
+		return result
+	}
+	if len(CurrentConfig().ConfigFile.Core.Extensions) == 0 {
+		result.Status = DiagnosticWarn
+		result.Message = "no file extensions configured"
+		result.Hint = `Add extensions = ["md", "markdown"] under [core] in .nt/config`
+		return result
+	}
+	result.Status = DiagnosticPass
+	result.Message = "configuration is valid"
+	return result
+}
+
+// diagnoseMigrations checks the database schema is up-to-date.
+func diagnoseMigrations() Diagnostic {
+	result := Diagnostic{Name: "database"}
+
+	latestVersion, err := latestMigrationVersion()
+	if err != nil {
+		result.Status = DiagnosticFail
+		result.Message = err.Error()
+		return result
+	}
+
+	// Do not use the current database client as migrations are applied when it is initialized
+	version, dirty, err := currentMigrationVersion()
+	if err != nil {
+		result.Status = DiagnosticFail
+		result.Message = fmt.Sprintf("unable to read schema version: %v", err)
+		result.Hint = "Remove .nt/database.db and run nt reset to rebuild the database"
+		return result
+	}
+	if dirty {
+		result.Status = DiagnosticFail
+		result.Message = fmt.Sprintf("migration %d failed", version)
+		result.Hint = "Remove .nt/database.db and run nt reset to rebuild the database"
+		return result
+	}
+	if version < latestVersion {
+		result.Status = DiagnosticWarn
+		result.Message = fmt.Sprintf("schema version %d (expected %d)", version, latestVersion)
+		result.Hint = "Run nt status to apply pending migrations"
+		return result
+	}
+	if version > latestVersion {
+		result.Status = DiagnosticFail
+		result.Message = fmt.Sprintf("schema version %d (expected %d)", version, latestVersion)
+		result.Hint = "Upgrade nt to the latest version"
+		return result
+	}
+	result.Status = DiagnosticPass
+	result.Message = fmt.Sprintf("schema version %d", version)
+	return result
+}
+
+// diagnoseFTSTokenizer checks the full-text index uses the configured tokenizer.
+func diagnoseFTSTokenizer() Diagnostic {
+	result := Diagnostic{Name: "search"}
+
+	tokenizer, err := CurrentDB().FTSTokenizer()
+	if err != nil {
+		result.Status = DiagnosticFail
+		result.Message = err.Error()
+		result.Hint = "Run nt reindex to rebuild the full-text index"
+		return result
+	}
+	configuredTokenizer := CurrentConfig().ConfiguredFTSTokenizer()
+	if tokenizer != configuredTokenizer {
+		result.Status = DiagnosticWarn
+		result.Message = fmt.Sprintf("full-text index uses tokenizer %s (configured %s)", tokenizer, configuredTokenizer)
+		result.Hint = "Run nt reindex to apply the configured tokenizer"
+		return result
+	}
+	result.Status = DiagnosticPass
+	result.Message = fmt.Sprintf("full-text index uses tokenizer %s", tokenizer)
+	return result
+}
+
+// currentMigrationVersion returns the version of the last migration applied to the database without applying pending migrations.
+func currentMigrationVersion() (int, bool, error) {
+	client, err := sql.Open("sqlite3", filepath.Join(CurrentConfig().RootDirectory, ".nt/database.db"))
+	if err != nil {
+		return 0, false, err
+	}
+	defer client.Close()
+
+	instance, err := sqlite3.WithInstance(client, &sqlite3.Config{})
+	if err != nil {
+		return 0, false, err
+	}
+	d, err := iofs.New(migrationsFS, "sql")
+	if err != nil {
+		return 0, false, err
+	}
+	m, err := migrate.NewWithInstance("iofs", d, "sqlite3", instance)
+	if err != nil {
+		return 0, false, err
+	}
+
+	version, dirty, err := m.Version()
+	if err == migrate.ErrNilVersion {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return int(version), dirty, nil
+}
+
+// latestMigrationVersion returns the version of the last embedded migration.
+func latestMigrationVersion() (int, error) {
+	entries, err := fs.ReadDir(migrationsFS, "sql")
+	if err != nil {
+		return 0, err
+	}
+	latest := 0
+	for _, entry := range entries {
+		// Ex: 3_note_items.up.sql
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			continue
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
+
+// diagnosePackFiles checks the pack files referenced by the index are present.
+func diagnosePackFiles() Diagnostic {
+	result := Diagnostic{Name: "index"}
+
+	var missingPackFiles []string
+	for oid := range CurrentDB().index.PackFiles {
+		path := filepath.Join(CurrentConfig().RootDirectory, ".nt/objects", OIDToPath(oid))
+		if _, err := os.Stat(path); err != nil {
+			missingPackFiles = append(missingPackFiles, oid)
+		}
+	}
+	sort.Strings(missingPackFiles)
+
+	if len(missingPackFiles) > 0 {
+		result.Status = DiagnosticFail
+		result.Message = fmt.Sprintf("%d pack file(s) missing (ex: %s)", len(missingPackFiles), missingPackFiles[0])
+		result.Hint = "Run nt pull to retrieve missing objects from the remote"
+		return result
+	}
+	result.Status = DiagnosticPass
+	result.Message = fmt.Sprintf("%d pack file(s) present", len(CurrentDB().index.PackFiles))
+	return result
+}
+
+// diagnoseRemote checks the remote is reachable.
+func diagnoseRemote() Diagnostic {
+	result := Diagnostic{Name: "remote"}
+
+	configRemote := CurrentConfig().ConfigFile.Remote
+	if configRemote.Type == "" {
+		result.Status = DiagnosticWarn
+		result.Message = "no remote configured"
+		result.Hint = "Configure a [remote] in .nt/config to back up your notes"
+		return result
+	}
+	origin, err := NewRemote(configRemote)
+	if err == nil {
+		_, err = origin.ObjectExists("info/commit-graph")
+	}
+	if err != nil {
+		result.Status = DiagnosticFail
+		result.Message = err.Error()
+		result.Hint = "Check the [remote] settings in .nt/config and your network connection"
+		return result
+	}
+	result.Status = DiagnosticPass
+	result.Message = fmt.Sprintf("%s remote is reachable", configRemote.Type)
+	return result
+}
+
+// diagnoseDanglingMedias checks no notes reference missing medias.
+func diagnoseDanglingMedias() Diagnostic {
+	result := Diagnostic{Name: "medias"}
+
+	medias, err := QueryMedias(CurrentDB().Client(), "WHERE dangling = 1 ORDER BY relative_path")
+	if err != nil {
+		result.Status = DiagnosticFail
+		result.Message = err.Error()
+		return result
+	}
+	if len(medias) > 0 {
+		result.Status = DiagnosticWarn
+		result.Message = fmt.Sprintf("%d dangling media(s) (ex: %s)", len(medias), medias[0].RelativePath)
+		result.Hint = "Restore the missing files or remove the links (see nt lint)"
+		return result
+	}
+	result.Status = DiagnosticPass
+	result.Message = "no dangling medias"
+	return result
+}
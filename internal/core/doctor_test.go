@@ -0,0 +1,83 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+	err = CurrentDB().Commit("initial commit")
+	require.NoError(t, err)
+
+	statuses := func() map[string]DiagnosticStatus {
+		results := make(map[string]DiagnosticStatus)
+		for _, diagnostic := range CurrentRepository().Diagnose() {
+			results[diagnostic.Name] = diagnostic.Status
+		}
+		return results
+	}
+
+	assert.Equal(t, map[string]DiagnosticStatus{
+		"config":   DiagnosticPass,
+		"database": DiagnosticPass,
+		"search":   DiagnosticPass,
+		"index":    DiagnosticPass,
+		"remote":   DiagnosticWarn, // No remote
+		"medias":   DiagnosticPass,
+	}, statuses())
+
+	// Configure a remote
+	CurrentConfig().ConfigFile.Remote = ConfigRemote{
+		Type: "fs",
+		Dir:  t.TempDir(),
+	}
+	assert.Equal(t, DiagnosticPass, statuses()["remote"])
+	CurrentConfig().ConfigFile.Remote.Dir = filepath.Join(t.TempDir(), "missing")
+	assert.Equal(t, DiagnosticFail, statuses()["remote"])
+
+	// Change the tokenizer without reindexing
+	CurrentConfig().ConfigFile.Core.SearchTokenizer = FTSTokenizerTrigram
+	assert.Equal(t, DiagnosticWarn, statuses()["search"])
+	require.NoError(t, CurrentRepository().Reindex())
+	assert.Equal(t, DiagnosticPass, statuses()["search"])
+
+	// Reference a missing media
+	MustWriteFile(t, "python.md", `# Python
+
+## Note: Logo
+
+![Logo](./medias/python.svg)
+`)
+	err = CurrentRepository().Add("python.md")
+	require.NoError(t, err)
+	assert.Equal(t, DiagnosticWarn, statuses()["medias"])
+
+	// Remove a pack file
+	for oid := range CurrentDB().index.PackFiles {
+		require.NoError(t, os.Remove(filepath.Join(CurrentConfig().RootDirectory, ".nt/objects", OIDToPath(oid))))
+	}
+	assert.Equal(t, DiagnosticFail, statuses()["index"])
+
+	// Outdate the database schema (pending migrations must not be applied by the check)
+	latestVersion, err := latestMigrationVersion()
+	require.NoError(t, err)
+	_, err = CurrentDB().Client().Exec(`UPDATE schema_migrations SET version = ?`, latestVersion-1)
+	require.NoError(t, err)
+	assert.Equal(t, DiagnosticWarn, statuses()["database"])
+	assert.Equal(t, DiagnosticWarn, statuses()["database"]) // still pending
+	_, err = CurrentDB().Client().Exec(`UPDATE schema_migrations SET version = ?, dirty = 1`, latestVersion)
+	require.NoError(t, err)
+	assert.Equal(t, DiagnosticFail, statuses()["database"])
+
+	// Break the configuration
+	CurrentConfig().ConfigFile.Core.Compression = "unknown"
+	assert.Equal(t, DiagnosticFail, statuses()["config"])
+}
@@ -0,0 +1,84 @@
+package core
+
+import "sync"
+
+// ObjectChangeEvent describes an object staged by an add, committed, or retrieved by a pull.
+type ObjectChangeEvent struct {
+	OID          string
+	Kind         string
+	RelativePath string
+	// One of added, modified, deleted
+	Change State
+	// The commit including the change (empty when staged)
+	CommitOID string
+}
+
+type objectChangeListener struct {
+	id int
+	fn func(ObjectChangeEvent)
+}
+
+var (
+	objectChangeListenersMutex sync.RWMutex
+	objectChangeListeners      []objectChangeListener
+	objectChangeListenersSeq   int
+)
+
+// OnObjectChange registers a function called after every object change.
+// Listeners are called in their registration order. The returned function unregisters the listener.
+func OnObjectChange(fn func(evt ObjectChangeEvent)) func() {
+	objectChangeListenersMutex.Lock()
+	defer objectChangeListenersMutex.Unlock()
+	objectChangeListenersSeq++
+	id := objectChangeListenersSeq
+	objectChangeListeners = append(objectChangeListeners, objectChangeListener{id: id, fn: fn})
+	return func() {
+		objectChangeListenersMutex.Lock()
+		defer objectChangeListenersMutex.Unlock()
+		for i, listener := range objectChangeListeners {
+			if listener.id == id {
+				objectChangeListeners = append(objectChangeListeners[:i:i], objectChangeListeners[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// hasObjectChangeListeners returns true when events must be emitted.
+func hasObjectChangeListeners() bool {
+	objectChangeListenersMutex.RLock()
+	defer objectChangeListenersMutex.RUnlock()
+	return len(objectChangeListeners) > 0
+}
+
+// newObjectChangeEvent creates a new event for a changed object.
+func newObjectChangeEvent(obj StatefulObject, change State, commitOID string) ObjectChangeEvent {
+	return ObjectChangeEvent{
+		OID:          obj.UniqueOID(),
+		Kind:         obj.Kind(),
+		RelativePath: objectRelativePath(obj),
+		Change:       change,
+		CommitOID:    commitOID,
+	}
+}
+
+// emitObjectChange notifies all listeners.
+func emitObjectChange(evt ObjectChangeEvent) {
+	objectChangeListenersMutex.RLock()
+	listeners := objectChangeListeners
+	objectChangeListenersMutex.RUnlock()
+	for _, listener := range listeners {
+		listener.fn(evt)
+	}
+}
+
+// emitPackFileChanges notifies all listeners about the objects of a committed pack file.
+func emitPackFileChanges(packFile *PackFile, commitOID string) {
+	if !hasObjectChangeListeners() {
+		// Avoid reading objects
+		return
+	}
+	for _, packObject := range packFile.PackObjects {
+		emitObjectChange(newObjectChangeEvent(packObject.ReadObject(), packObject.State, commitOID))
+	}
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnObjectChange(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	var events []ObjectChangeEvent
+	unregister := OnObjectChange(func(evt ObjectChangeEvent) {
+		events = append(events, evt)
+	})
+	defer unregister()
+
+	// Staged objects
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	note := MustFindNoteByPathAndTitle(t, "go.md", "Reference: Golang History")
+	assert.Contains(t, events, ObjectChangeEvent{
+		OID:          note.OID,
+		Kind:         "note",
+		RelativePath: "go.md",
+		Change:       Added,
+	})
+	stagedCount := len(events)
+
+	// Committed objects
+	events = nil
+	err = CurrentDB().Commit("initial commit")
+	require.NoError(t, err)
+	require.Len(t, events, stagedCount)
+	assert.Contains(t, events, ObjectChangeEvent{
+		OID:          note.OID,
+		Kind:         "note",
+		RelativePath: "go.md",
+		Change:       Added,
+		CommitOID:    CurrentDB().Head().OID,
+	})
+
+	// Modified objects
+	events = nil
+	MustWriteFile(t, "go.md", `# Go
+
+## Reference: Golang History
+
+Edited.
+`)
+	err = CurrentRepository().Add("go.md")
+	require.NoError(t, err)
+	assert.Contains(t, events, ObjectChangeEvent{
+		OID:          note.OID,
+		Kind:         "note",
+		RelativePath: "go.md",
+		Change:       Modified,
+	})
+
+	// No longer notified
+	unregister()
+	events = nil
+	err = CurrentDB().Commit("second commit")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,360 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/markdown"
+	"github.com/julien-sobczak/the-notewriter/pkg/text"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportState is the content of the file .nt/export-state.
+type ExportState struct {
+	LastExport time.Time `yaml:"last_export"`
+}
+
+// ExportJSON writes every note under the given paths as newline-delimited JSON.
+// Notes are streamed to support large repositories.
+// Optional kinds restrict the exported notes and a non-zero since date ignores notes not updated since.
+// Notes deleted since this date are also written with only their OID and deletion date.
+// A non-empty media base URL replaces OID links to medias by public URLs (see Note.ReplaceMediasByURL).
+func (r *Repository) ExportJSON(w io.Writer, paths []string, kinds []NoteKind, since time.Time, mediaBaseURL string) error {
+	relativePaths, err := r.relativePaths(paths)
+	if err != nil {
+		return err
+	}
+	whereClause, args := notesWhereClause(relativePaths, kinds)
+	whereClause += " ORDER BY relative_path, line"
+
+	encoder := json.NewEncoder(w)
+	err = QueryNotesFunc(CurrentDB().Client(), func(note *Note) error {
+		if !since.IsZero() && !note.UpdatedAt.After(since) {
+			return nil
+		}
+		if mediaBaseURL != "" {
+			return encoder.Encode(note.RepresentationWithMediaBaseURL(mediaBaseURL))
+		}
+		return encoder.Encode(note.Representation())
+	}, whereClause, args...)
+	if err != nil {
+		return err
+	}
+
+	if since.IsZero() {
+		// Nothing was exported before
+		return nil
+	}
+	deletedNotes, err := CurrentDB().DeletedNotesSince(since)
+	if err != nil {
+		return err
+	}
+	exportedOIDs := make(map[string]bool)
+	for _, note := range deletedNotes {
+		if exportedOIDs[note.OID] || !matchRelativePaths(note.RelativePath, relativePaths) {
+			continue
+		}
+		if len(kinds) > 0 && !slices.Contains(kinds, note.NoteKind) {
+			continue
+		}
+		exportedOIDs[note.OID] = true
+		if err := encoder.Encode(&NoteRepresentation{OID: note.OID, DeletedAt: &note.DeletedAt}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChangedSince returns the notes under the given paths updated after the given time,
+// and the OIDs of notes deleted since.
+func (r *Repository) ChangedSince(t time.Time, paths []string) ([]*Note, []string, error) {
+	relativePaths, err := r.relativePaths(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	whereClause, args := notesWhereClause(relativePaths, nil)
+	var notes []*Note
+	err = QueryNotesFunc(CurrentDB().Client(), func(note *Note) error {
+		// Dates are compared in Go as their text representation is not sortable
+		if note.UpdatedAt.After(t) {
+			notes = append(notes, note)
+		}
+		return nil
+	}, whereClause, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deletedNotes, err := CurrentDB().DeletedNotesSince(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	var deletedOIDs []string
+	for _, note := range deletedNotes {
+		if matchRelativePaths(note.RelativePath, relativePaths) && !slices.Contains(deletedOIDs, note.OID) {
+			deletedOIDs = append(deletedOIDs, note.OID)
+		}
+	}
+
+	return notes, deletedOIDs, nil
+}
+
+// LastExportTime returns the date of the last export (zero if none).
+func (r *Repository) LastExportTime() (time.Time, error) {
+	data, err := os.ReadFile(r.exportStatePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	var state ExportState
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return time.Time{}, fmt.Errorf("invalid export state: %w", err)
+	}
+	return state.LastExport, nil
+}
+
+// SaveExportTime persists the date of the last export.
+func (r *Repository) SaveExportTime(t time.Time) error {
+	data, err := yaml.Marshal(&ExportState{LastExport: t})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.exportStatePath(), data, 0644)
+}
+
+func (r *Repository) exportStatePath() string {
+	return filepath.Join(CurrentConfig().RootDirectory, ".nt/export-state")
+}
+
+// relativePaths converts paths to relative paths (empty when covering the whole repository).
+func (r *Repository) relativePaths(paths []string) ([]string, error) {
+	var results []string
+	for _, path := range r.normalizePaths(paths...) {
+		relativePath, err := r.GetFileRelativePath(path)
+		if err != nil {
+			return nil, err
+		}
+		if relativePath == "." || relativePath == "" {
+			// The whole repository
+			return nil, nil
+		}
+		results = append(results, relativePath)
+	}
+	return results, nil
+}
+
+// notesWhereClause returns the SQL condition to filter notes by relative paths and kinds.
+func notesWhereClause(relativePaths []string, kinds []NoteKind) (string, []any) {
+	var conditions []string
+	var args []any
+
+	if len(relativePaths) > 0 {
+		var pathConditions []string
+		for _, relativePath := range relativePaths {
+			pathConditions = append(pathConditions, `(relative_path = ? OR relative_path LIKE ? ESCAPE '\')`)
+			args = append(args, relativePath, escapeLike(relativePath)+"/%")
+		}
+		conditions = append(conditions, "("+strings.Join(pathConditions, " OR ")+")")
+	}
+
+	if len(kinds) > 0 {
+		var kindsSQL []string
+		for _, kind := range kinds {
+			kindsSQL = append(kindsSQL, "?")
+			args = append(args, kind)
+		}
+		conditions = append(conditions, fmt.Sprintf("kind IN (%s)", strings.Join(kindsSQL, ",")))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ExportCombinedMarkdown writes the notes under the given paths as a single Markdown document.
+// Files are written in the same order as Add, preceded by a table of contents.
+// Note headings are shifted according to their depth and wikilinks between
+// exported notes are replaced by links to in-document anchors.
+func (r *Repository) ExportCombinedMarkdown(paths []string, w io.Writer) error {
+	type exportedFile struct {
+		file  *File
+		notes []*Note
+		lines []string // Original lines to determine the file title
+	}
+	var files []*exportedFile
+	fileAnchors := make(map[string]string) // wikilink => anchor
+	noteAnchors := make(map[string]string) // wikilink => anchor
+
+	err := r.walk(r.normalizePaths(paths...), func(path string, stat fs.FileInfo) error {
+		relativePath, err := r.GetFileRelativePath(path)
+		if err != nil {
+			return err
+		}
+		file, err := r.FindFileByRelativePath(relativePath)
+		if err != nil {
+			return err
+		}
+		if file == nil {
+			// Not added yet
+			return nil
+		}
+		notes, err := r.FindNotesByFileOID(file.OID)
+		if err != nil {
+			return err
+		}
+		slices.SortFunc(notes, func(a, b *Note) bool {
+			return a.Line < b.Line
+		})
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &exportedFile{
+			file:  file,
+			notes: notes,
+			lines: strings.Split(string(content), "\n"),
+		})
+		fileAnchors[file.Wikilink] = file.Slug
+		for _, note := range notes {
+			noteAnchors[note.Wikilink] = note.Slug
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var toc, body strings.Builder
+	toc.WriteString("# Table of Contents\n\n")
+	for _, f := range files {
+		title := fileTitle(f.lines)
+		if title == "" {
+			title = f.file.RelativePath
+		}
+		toc.WriteString(fmt.Sprintf("* [%s](#%s)\n", title, f.file.Slug))
+		body.WriteString(fmt.Sprintf("\n<a id=\"%s\"></a>\n\n# %s\n", f.file.Slug, title))
+
+		depths := make(map[string]int) // OID => depth
+		for _, note := range f.notes {
+			depth := 0
+			if parentDepth, ok := depths[note.ParentNoteOID]; ok {
+				depth = parentDepth + 1
+			}
+			depths[note.OID] = depth
+			toc.WriteString(fmt.Sprintf("%s* [%s](#%s)\n", strings.Repeat("  ", depth+1), note.LongTitle, note.Slug))
+
+			level := min(depth+2, 6)
+			// Headings in the note content are relative to the note (= the note is at level 1)
+			_, _, _, content, _, _, _, _, _ := note.parseContentRawWithMedias(func(md string) string { return md })
+			content = shiftHeadings(content, level-1)
+			content = replaceWikilinksByAnchors(content, text.TrimExtension(note.RelativePath), fileAnchors, noteAnchors)
+
+			body.WriteString(fmt.Sprintf("\n<a id=\"%s\"></a>\n\n%s %s\n", note.Slug, strings.Repeat("#", level), note.Title))
+			if content != "" {
+				body.WriteString("\n" + content + "\n")
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, toc.String()); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, body.String())
+	return err
+}
+
+// fileTitle returns the text of the first top-level heading outside the Front Matter and code blocks.
+func fileTitle(lines []string) string {
+	insideFrontMatter := len(lines) > 0 && lines[0] == "---"
+	insideCodeBlock := false
+	for i, line := range lines {
+		if insideFrontMatter {
+			insideFrontMatter = i == 0 || line != "---"
+			continue
+		}
+		if strings.HasPrefix(line, "```") {
+			insideCodeBlock = !insideCodeBlock
+			continue
+		}
+		if insideCodeBlock {
+			continue
+		}
+		if ok, title, level := markdown.IsHeading(line); ok && level == 1 {
+			return title
+		}
+	}
+	return ""
+}
+
+// shiftHeadings changes the level of all headings outside code blocks (between 1 and 6).
+func shiftHeadings(md string, shift int) string {
+	if shift == 0 {
+		return md
+	}
+	lines := strings.Split(md, "\n")
+	insideCodeBlock := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			insideCodeBlock = !insideCodeBlock
+			continue
+		}
+		if insideCodeBlock {
+			continue
+		}
+		if ok, title, level := markdown.IsHeading(line); ok {
+			newLevel := max(1, min(level+shift, 6))
+			lines[i] = strings.Repeat("#", newLevel) + " " + title
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// replaceWikilinksByAnchors rewrites wikilinks targeting exported files or notes as links to anchors.
+// Other wikilinks are replaced by their text.
+func replaceWikilinksByAnchors(md string, fileLink string, fileAnchors, noteAnchors map[string]string) string {
+	return regexWikilink.ReplaceAllStringFunc(md, func(match string) string {
+		wikilink, err := NewWikilink(match)
+		if err != nil {
+			return match
+		}
+		path := text.TrimExtension(wikilink.Path())
+		if wikilink.Internal() {
+			path = fileLink
+		}
+		section := wikilink.Section()
+
+		label := wikilink.Text
+		if label == "" {
+			label = section
+		}
+		if label == "" {
+			label = path
+		}
+
+		anchors := noteAnchors
+		if section == "" {
+			anchors = fileAnchors
+		}
+		for link, anchor := range anchors {
+			linkPath, linkSection, _ := strings.Cut(link, "#")
+			if linkSection != section {
+				continue
+			}
+			if linkPath == path || strings.HasSuffix(linkPath, "/"+path) {
+				return fmt.Sprintf("[%s](#%s)", label, anchor)
+			}
+		}
+		return label
+	})
+}
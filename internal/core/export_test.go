@@ -0,0 +1,261 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportJSON(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	export := func(paths []string, kinds []NoteKind, since time.Time) []*NoteRepresentation {
+		var buf bytes.Buffer
+		err := CurrentRepository().ExportJSON(&buf, paths, kinds, since, "")
+		require.NoError(t, err)
+
+		var results []*NoteRepresentation
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var note NoteRepresentation
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &note))
+			results = append(results, &note)
+		}
+		return results
+	}
+
+	t.Run("All", func(t *testing.T) {
+		notes := export(nil, nil, time.Time{})
+		require.Len(t, notes, 3)
+		note := notes[0]
+		assert.Equal(t, KindReference, note.Kind)
+		assert.Equal(t, "go.md", note.RelativePath)
+		assert.Contains(t, note.Wikilink, "#Reference: Golang History")
+		assert.Contains(t, note.LongTitle, "Golang History")
+		assert.Contains(t, note.Tags, "history")
+		assert.Equal(t, "https://en.wikipedia.org/wiki/Go_(programming_language)", note.Attributes["source"])
+		assert.NotEmpty(t, note.ContentMarkdown)
+		assert.False(t, note.UpdatedAt.IsZero())
+	})
+
+	t.Run("Kinds", func(t *testing.T) {
+		notes := export(nil, []NoteKind{KindFlashcard, KindTodo}, time.Time{})
+		require.Len(t, notes, 2)
+		assert.Equal(t, KindFlashcard, notes[0].Kind)
+		assert.Equal(t, KindTodo, notes[1].Kind)
+	})
+
+	t.Run("Paths", func(t *testing.T) {
+		assert.Len(t, export([]string{"go.md"}, nil, time.Time{}), 3)
+		assert.Empty(t, export([]string{"python.md"}, nil, time.Time{}))
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		assert.Len(t, export(nil, nil, clock.Now().Add(-time.Hour)), 3)
+		assert.Empty(t, export(nil, nil, clock.Now().Add(time.Hour)))
+	})
+}
+
+func TestExportJSONMediaBaseURL(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	MustWriteFile(t, "python.md", `# Python
+
+## Flashcard: Python Logo
+
+What does the **Python logo** represent?
+
+---
+
+Two snakes.
+
+![Logo](./medias/python.svg)
+`)
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	media, err := CurrentRepository().FindMediaByRelativePath("medias/go.svg")
+	require.NoError(t, err)
+	require.NotNil(t, media)
+	mediaURL := MediaURL("https://example.com/objects/", media)
+	assert.Regexp(t, `^https://example.com/objects/[0-9a-f]{2}/[0-9a-f]{40}$`, mediaURL)
+
+	export := func(mediaBaseURL string) string {
+		var buf bytes.Buffer
+		err := CurrentRepository().ExportJSON(&buf, nil, []NoteKind{KindFlashcard}, time.Time{}, mediaBaseURL)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	// OID links are used by default
+	output := export("")
+	assert.Contains(t, output, "oid:"+media.OID)
+	assert.NotContains(t, output, "https://example.com/objects/")
+
+	output = export("https://example.com/objects/")
+	assert.NotContains(t, output, "oid:")
+	assert.Contains(t, output, mediaURL)
+	// Dangling medias use the 404 image
+	assert.Contains(t, output, "https://example.com/objects/"+OIDToPath(missingMediaOID))
+
+	CurrentConfig().ConfigFile.Medias.MissingURL = "https://example.com/404.svg"
+	output = export("https://example.com/objects/")
+	assert.Contains(t, output, "https://example.com/404.svg")
+}
+
+func TestExportCombinedMarkdown(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	MustWriteFile(t, "go.md", `---
+# Front Matter comments are not headings
+tags: [go]
+---
+
+# Go
+
+### Note: History
+
+Go was designed at Google in 2007.
+
+#### Origins
+
+Ken Thompson.
+`)
+	MustWriteFile(t, "links.md", `# Links
+
+## Note: Links
+
+* [[go#Note: History|Go history]]
+* [[go]]
+* [[unknown#Note: Missing]]
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	fileGo, err := CurrentRepository().FindFileByRelativePath("go.md")
+	require.NoError(t, err)
+	fileLinks, err := CurrentRepository().FindFileByRelativePath("links.md")
+	require.NoError(t, err)
+	noteHistory := MustFindNoteByPathAndTitle(t, "go.md", "Note: History")
+	noteLinks := MustFindNoteByPathAndTitle(t, "links.md", "Note: Links")
+
+	var buf bytes.Buffer
+	err = CurrentRepository().ExportCombinedMarkdown(nil, &buf)
+	require.NoError(t, err)
+	actual := buf.String()
+
+	// Table of contents
+	assert.True(t, strings.HasPrefix(actual, "# Table of Contents\n\n"+
+		"* [Go](#"+fileGo.Slug+")\n"+
+		"  * [History](#"+noteHistory.Slug+")\n"+
+		"* [Links](#"+fileLinks.Slug+")\n"+
+		"  * [Links](#"+noteLinks.Slug+")\n"), actual)
+
+	// Headings are shifted
+	assert.Contains(t, actual, "<a id=\""+noteHistory.Slug+"\"></a>\n\n## Note: History\n\n"+
+		"Go was designed at Google in 2007.\n\n### Origins\n\nKen Thompson.\n")
+
+	// Wikilinks are resolved
+	assert.Contains(t, actual, "* [Go history](#"+noteHistory.Slug+")\n"+
+		"* [go](#"+fileGo.Slug+")\n"+
+		"* Note: Missing\n")
+
+	// Files are written in order
+	assert.Less(t, strings.Index(actual, "# Go\n"), strings.Index(actual, "# Links\n"))
+
+	// Restrict to a path
+	buf.Reset()
+	err = CurrentRepository().ExportCombinedMarkdown([]string{"links.md"}, &buf)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "# Go\n")
+	assert.Contains(t, buf.String(), "* Go history\n") // Not exported
+}
+
+func TestChangedSince(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	lastExport := FreezeNow(t)
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+	err = CurrentDB().Commit("initial commit")
+	require.NoError(t, err)
+
+	flashcard := MustFindNoteByPathAndTitle(t, "go.md", "Flashcard: Golang Logo")
+
+	notes, deletedOIDs, err := CurrentRepository().ChangedSince(lastExport, nil)
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+	assert.Empty(t, deletedOIDs)
+
+	// Edit a note and delete another one (the note "TODO: Conferences" moves but is unchanged)
+	FreezeAt(t, lastExport.Add(time.Minute))
+	MustWriteFile(t, "go.md", `---
+tags:
+- go
+---
+
+# Go
+
+## Reference: Golang History
+
+Go was designed at Google in 2007.
+
+## TODO: Conferences
+
+* [Gophercon Europe](https://gophercon.eu/) `+"`#reminder-2023-06-26`"+`
+`)
+	err = CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	notes, deletedOIDs, err = CurrentRepository().ChangedSince(lastExport, nil)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "Reference: Golang History", notes[0].Title)
+	assert.Equal(t, []string{flashcard.OID}, deletedOIDs)
+
+	// Deletions are still reported once committed
+	err = CurrentDB().Commit("second commit")
+	require.NoError(t, err)
+	_, deletedOIDs, err = CurrentRepository().ChangedSince(lastExport, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{flashcard.OID}, deletedOIDs)
+
+	// Deleted notes are exported with their deletion date
+	var buf bytes.Buffer
+	err = CurrentRepository().ExportJSON(&buf, nil, nil, lastExport, "")
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `"shortTitleRaw":"Golang History"`)
+	assert.Contains(t, buf.String(), `{"oid":"`+flashcard.OID+`"`)
+
+	// Only notes under the given paths are considered
+	notes, deletedOIDs, err = CurrentRepository().ChangedSince(lastExport, []string{"python.md"})
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+	assert.Empty(t, deletedOIDs)
+}
+
+func TestExportState(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	lastExport, err := CurrentRepository().LastExportTime()
+	require.NoError(t, err)
+	assert.True(t, lastExport.IsZero())
+
+	now := time.Date(2023, time.Month(6), 1, 12, 30, 0, 0, time.UTC)
+	err = CurrentRepository().SaveExportTime(now)
+	require.NoError(t, err)
+
+	lastExport, err = CurrentRepository().LastExportTime()
+	require.NoError(t, err)
+	assert.True(t, now.Equal(lastExport))
+}
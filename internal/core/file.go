@@ -75,6 +75,9 @@ type File struct {
 	DeletedAt     time.Time `yaml:"deleted_at,omitempty"`
 	LastCheckedAt time.Time `yaml:"-"`
 
+	// Previous relative path when the file was renamed
+	renamedFrom string
+
 	new   bool
 	stale bool
 }
@@ -94,7 +97,23 @@ func NewOrExistingFile(parent *File, path string) (*File, error) {
 		return existingFile, nil
 	}
 
-	return NewFileFromPath(parent, path)
+	parsedFile, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reuse the file when renamed without being edited to preserve OIDs
+	renamedFile, err := CurrentRepository().FindRenamedFile(helpers.Hash(parsedFile.Bytes))
+	if err != nil {
+		return nil, err
+	}
+	if renamedFile != nil {
+		renamedFile.rename(parsedFile)
+		renamedFile.update(parent)
+		return renamedFile, nil
+	}
+
+	return NewFileFromParsedFile(parent, parsedFile), nil
 }
 
 /* Creation */
@@ -302,7 +321,7 @@ func (f *File) update(parent *File) error {
 	}
 
 	// Check if local file has changed
-	if f.MTime != parsedFile.LStat.ModTime() || f.Size != parsedFile.LStat.Size() {
+	if !f.MTime.Equal(parsedFile.LStat.ModTime()) || f.Size != parsedFile.LStat.Size() {
 		// file change
 		f.stale = true
 
@@ -326,6 +345,15 @@ func (f *File) update(parent *File) error {
 	return nil
 }
 
+// rename moves the file to the path of the given parsed file.
+func (f *File) rename(parsedFile *ParsedFileOld) {
+	f.renamedFrom = f.RelativePath
+	f.RelativePath = parsedFile.RelativePath
+	f.Wikilink = text.TrimExtension(parsedFile.RelativePath)
+	f.Slug = parsedFile.Slug
+	f.stale = true
+}
+
 /* State Management */
 
 func (f *File) New() bool {
@@ -542,7 +570,7 @@ func (f *File) GetNotes() []*Note {
 		if parentNoteIndices[i] != -1 {
 			parent = notes[parentNoteIndices[i]]
 		}
-		note := NewOrExistingNote(f, parent, currentNote)
+		note := NewOrExistingNote(f, parent, currentNote, parsedNotes)
 		if note.HasTag("ignore") {
 			// Do not add notes marked as ignorable
 			continue
@@ -692,12 +720,13 @@ func ParseNotes(fileBody string, fileSlug string) []*ParsedNoteOld {
 		tags, attributes := ExtractBlockTagsAndAttributes(noteContent)
 
 		// Determine slug from attribute or define a default one otherwise
-		slug := markdown.Slug(fileSlug, string(section.kind), section.shortTitle)
+		attributeSlug := ""
 		if value, ok := attributes["slug"]; ok {
 			if v, ok := value.(string); ok {
-				slug = v
+				attributeSlug = v
 			}
 		}
+		slug := DetermineNoteSlug(fileSlug, attributeSlug, section.kind, section.shortTitle)
 
 		parsedNote := &ParsedNoteOld{
 			Level:          section.level,
@@ -718,9 +747,7 @@ func ParseNotes(fileBody string, fileSlug string) []*ParsedNoteOld {
 
 // Hash returns the current hash to use when searching for an existing note in database to avoid recreating it.
 func (n *ParsedNoteOld) Hash() string {
-	raw := strings.TrimSpace(n.Body)
-	hash := helpers.Hash([]byte(raw))
-	return hash
+	return HashNoteContent(n.Body)
 }
 
 // Wikilinks returns the wikilinks present in the note.
@@ -821,13 +848,32 @@ func ParseFile(path string) (*ParsedFileOld, error) {
 		return nil, err
 	}
 
+	file, err := ParseFileFromBytes(relativePath, contentBytes)
+	if err != nil {
+		return nil, err
+	}
+	file.Stat = stat
+	file.LStat = lstat
+	return file, nil
+}
+
+// ParseFileFromBytes parses a file content without reading the disk (ex: unsaved editor buffer).
+// Stat and LStat are not set.
+func ParseFileFromBytes(relativePath string, contentBytes []byte) (*ParsedFileOld, error) {
 	var rawFrontMatter bytes.Buffer
 	var rawContent bytes.Buffer
 	frontMatterStarted := false
 	frontMatterEnded := false
 	bodyStarted := false
 	bodyStartLineNumber := 0
-	for i, line := range strings.Split(strings.TrimSuffix(string(contentBytes), "\n"), "\n") {
+
+	// Convert alternative formats (ex: AsciiDoc) to Markdown
+	markdownBytes, err := FileParserFor(relativePath).Parse(contentBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range strings.Split(strings.TrimSuffix(string(markdownBytes), "\n"), "\n") {
 		if strings.HasPrefix(line, "---") {
 			if bodyStarted {
 				// Flashcard Front/Back line separator
@@ -870,11 +916,12 @@ func ParseFile(path string) (*ParsedFileOld, error) {
 	}
 
 	body := strings.TrimSpace(rawContent.String())
-	// Extract title
+	// Extract title (= the first top-level heading)
 	title := ""
 	for _, line := range strings.Split(body, "\n") {
-		if ok, longTitle, _ := markdown.IsHeading(line); ok {
+		if ok, longTitle, level := markdown.IsHeading(line); ok && level == 1 {
 			title = longTitle
+			break
 		}
 	}
 	_, _, shortTitle := isSupportedNote(title)
@@ -888,10 +935,8 @@ func ParseFile(path string) (*ParsedFileOld, error) {
 	}
 
 	return &ParsedFileOld{
-		AbsolutePath:   absolutePath,
+		AbsolutePath:   CurrentRepository().GetAbsolutePath(relativePath),
 		RelativePath:   relativePath,
-		Stat:           stat,
-		LStat:          lstat,
 		Slug:           slug,
 		Title:          title,
 		ShortTitle:     shortTitle,
@@ -1185,6 +1230,20 @@ func (r *Repository) FindFileByRelativePath(relativePath string) (*File, error)
 	return QueryFile(CurrentDB().Client(), `WHERE relative_path = ?`, relativePath)
 }
 
+// FindRenamedFile returns the file with the given hash that no longer exists at its relative path.
+func (r *Repository) FindRenamedFile(hash string) (*File, error) {
+	files, err := QueryFiles(CurrentDB().Client(), `WHERE hashsum = ?`, hash)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if _, err := os.Lstat(r.GetAbsolutePath(file.RelativePath)); os.IsNotExist(err) {
+			return file, nil
+		}
+	}
+	return nil, nil
+}
+
 func (r *Repository) FindFilesByRelativePathPrefix(relativePathPrefix string) ([]*File, error) {
 	return QueryFiles(CurrentDB().Client(), `WHERE relative_path LIKE ?`, relativePathPrefix+"%")
 }
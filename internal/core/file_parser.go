@@ -0,0 +1,161 @@
+package core
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// FileParser converts the raw content of a file into the equivalent Markdown document.
+//
+// Every other step (Front Matter, sections, notes, flashcards, links, ...) works on Markdown.
+// Alternative formats only need to translate their syntax. Implementations must preserve
+// line numbers (one output line per input line) so that reported lines match the original file.
+type FileParser interface {
+	Parse(content []byte) ([]byte, error)
+}
+
+// MarkdownParser is the default parser. Markdown files are used as is.
+type MarkdownParser struct{}
+
+func (MarkdownParser) Parse(content []byte) ([]byte, error) {
+	return content, nil
+}
+
+var (
+	fileParsersMu sync.RWMutex
+	fileParsers   = map[string]FileParser{
+		"md":       MarkdownParser{},
+		"markdown": MarkdownParser{},
+		"adoc":     AsciiDocParser{},
+		"asciidoc": AsciiDocParser{},
+		"org":      OrgParser{},
+	}
+)
+
+// RegisterFileParser registers the parser to use for files with the given extension (ex: "adoc").
+// Files must still be included using the setting `core.extensions`.
+func RegisterFileParser(extension string, parser FileParser) {
+	fileParsersMu.Lock()
+	defer fileParsersMu.Unlock()
+	fileParsers[normalizeParserExtension(extension)] = parser
+}
+
+// FileParserFor returns the parser registered for the extension of the given file.
+// Markdown is used when no parser is registered.
+func FileParserFor(path string) FileParser {
+	fileParsersMu.RLock()
+	defer fileParsersMu.RUnlock()
+	if parser, ok := fileParsers[normalizeParserExtension(filepath.Ext(path))]; ok {
+		return parser
+	}
+	return MarkdownParser{}
+}
+
+func normalizeParserExtension(extension string) string {
+	return strings.ToLower(strings.TrimPrefix(extension, ".")) // ".ADOC" => "adoc"
+}
+
+/* AsciiDoc */
+
+var (
+	regexAsciiDocHeading    = regexp.MustCompile(`^(={1,6})\s+(.+?)\s*$`)
+	regexAsciiDocSourceAttr = regexp.MustCompile(`^\[source(?:,\s*([^,\]]+))?.*\]\s*$`)
+)
+
+// AsciiDocParser supports a minimal subset of AsciiDoc:
+// section titles (`= Title`, `== Section`), listing blocks (`----`)
+// with an optional `[source,lang]` attribute, and line comments (`//`).
+type AsciiDocParser struct{}
+
+func (AsciiDocParser) Parse(content []byte) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+
+	insideFrontMatter := false
+	insideListing := false
+	language := ""
+	for i, line := range lines {
+		// Keep the optional YAML Front Matter unchanged
+		if i == 0 && strings.TrimSpace(line) == "---" {
+			insideFrontMatter = true
+			continue
+		}
+		if insideFrontMatter {
+			if strings.TrimSpace(line) == "---" {
+				insideFrontMatter = false
+			}
+			continue
+		}
+
+		if strings.TrimSpace(line) == "----" {
+			if insideListing {
+				lines[i] = "```"
+			} else {
+				lines[i] = "```" + language
+			}
+			insideListing = !insideListing
+			language = ""
+			continue
+		}
+		if insideListing {
+			continue
+		}
+
+		if match := regexAsciiDocSourceAttr.FindStringSubmatch(line); match != nil {
+			// The language is moved on the opening fence
+			language = strings.TrimSpace(match[1])
+			lines[i] = ""
+			continue
+		}
+		language = ""
+
+		if strings.HasPrefix(line, "//") {
+			// Comments are not rendered
+			lines[i] = ""
+			continue
+		}
+
+		if match := regexAsciiDocHeading.FindStringSubmatch(line); match != nil {
+			lines[i] = strings.Repeat("#", len(match[1])) + " " + match[2]
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+/* Org */
+
+var regexOrgHeading = regexp.MustCompile(`^(\*{1,6})\s+(.+?)\s*$`)
+
+// OrgParser supports a minimal subset of Org mode:
+// headlines (`* Title`, `** Section`) and source blocks (`#+BEGIN_SRC lang`).
+type OrgParser struct{}
+
+func (OrgParser) Parse(content []byte) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+
+	insideSource := false
+	for i, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		upperLine := strings.ToUpper(trimmedLine)
+		if !insideSource && strings.HasPrefix(upperLine, "#+BEGIN_SRC") {
+			lines[i] = "```" + strings.TrimSpace(trimmedLine[len("#+BEGIN_SRC"):])
+			insideSource = true
+			continue
+		}
+		if insideSource {
+			if strings.HasPrefix(upperLine, "#+END_SRC") {
+				lines[i] = "```"
+				insideSource = false
+			}
+			continue
+		}
+
+		if match := regexOrgHeading.FindStringSubmatch(line); match != nil {
+			lines[i] = strings.Repeat("#", len(match[1])) + " " + match[2]
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n")), nil
+}
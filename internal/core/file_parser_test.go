@@ -0,0 +1,102 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileParser(t *testing.T) {
+	var tests = []struct {
+		name     string // name
+		path     string // input
+		content  string // input
+		expected string // output
+	}{
+		{
+			name:     "Markdown",
+			path:     "go.md",
+			content:  "# Go\n\n## Note: Basics\n",
+			expected: "# Go\n\n## Note: Basics\n",
+		},
+		{
+			name: "AsciiDoc",
+			path: "go.ADOC",
+			content: `= Go
+// Draft
+
+== Note: Hello World
+
+[source,go]
+----
+== Not a heading
+----
+`,
+			expected: "# Go\n\n\n## Note: Hello World\n\n\n```go\n== Not a heading\n```\n",
+		},
+		{
+			name: "Org",
+			path: "go.org",
+			content: `* Go
+
+** Note: Hello World
+
+#+BEGIN_SRC go
+* Not a heading
+#+END_SRC
+`,
+			expected: "# Go\n\n## Note: Hello World\n\n```go\n* Not a heading\n```\n",
+		},
+		{
+			name:     "Unknown extension",
+			path:     "go.txt",
+			content:  "= Go\n",
+			expected: "= Go\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := FileParserFor(tt.path).Parse([]byte(tt.content))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func TestParseAsciiDocFile(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+
+	content := `---
+tags: [go]
+---
+= Go
+
+== Note: Hello World
+
+Basic program.
+
+== Flashcard: Go Keywords
+
+How many keywords?
+
+---
+
+25
+`
+	MustWriteFile(t, "go.adoc", content)
+	parsedFile, err := ParseFile(filepath.Join(root, "go.adoc"))
+	require.NoError(t, err)
+	assert.Equal(t, "Go", parsedFile.Title)
+	assert.Equal(t, []byte(content), parsedFile.Bytes) // Original content is preserved
+
+	file := NewFileFromParsedFile(nil, parsedFile)
+	assert.Equal(t, []string{"go"}, file.GetTags())
+	notes := file.GetNotes()
+	require.Len(t, notes, 2)
+	assert.Equal(t, "Note: Hello World", notes[0].Title)
+	assert.Equal(t, 6, notes[0].Line)
+	assert.Equal(t, KindFlashcard, notes[1].NoteKind)
+	assert.Equal(t, 10, notes[1].Line)
+}
@@ -487,7 +487,7 @@ func TestFileSave(t *testing.T) {
 	assert.Equal(t, f.Mode, actual.Mode)
 	assert.Equal(t, f.Size, actual.Size)
 	assert.Equal(t, f.Hash, actual.Hash)
-	assert.Equal(t, f.MTime, actual.MTime)
+	assert.Equal(t, f.MTime.UTC(), actual.MTime.UTC()) // Time zones differ between the file system and the database
 	assert.WithinDuration(t, clock.Now(), actual.CreatedAt, 1*time.Second)
 	assert.WithinDuration(t, clock.Now(), actual.UpdatedAt, 1*time.Second)
 	assert.WithinDuration(t, clock.Now(), actual.LastCheckedAt, 1*time.Second)
@@ -777,6 +777,114 @@ func TestInheritance(t *testing.T) {
 	}, goroutineNote.GetAttributes())
 }
 
+func TestInheritDepth(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	CurrentConfig().LintFile.Schemas = []ConfigLintSchema{
+		{
+			Name: "Projects",
+			Attributes: []*ConfigLintSchemaAttribute{
+				{Name: "tags", Type: "array", Required: BoolPointer(false), Inherit: BoolPointer(true), InheritDepth: IntPointer(1)},
+				{Name: "subject", Type: "string", Required: BoolPointer(false), Inherit: BoolPointer(true), InheritDepth: IntPointer(0)},
+			},
+		},
+	}
+
+	MustWriteFile(t, "projects.md", `---
+tags: [project]
+subject: work
+---
+# Projects
+
+## Note: Alpha
+
+`+"`#alpha`"+`
+
+The alpha project.
+
+### Note: Alpha Tasks
+
+The tasks of the alpha project.
+`)
+	err := CurrentRepository().Add("projects.md")
+	require.NoError(t, err)
+
+	alphaNote := MustFindNoteByPathAndTitle(t, "projects.md", "Note: Alpha")
+	assert.EqualValues(t, []string{"project", "alpha"}, alphaNote.GetTags())
+	assert.NotContains(t, alphaNote.GetAttributes(), "subject") // Self only
+
+	tasksNote := MustFindNoteByPathAndTitle(t, "projects.md", "Note: Alpha Tasks")
+	assert.EqualValues(t, []string{"alpha"}, tasksNote.GetTags()) // File tags stop at direct children
+	assert.EqualValues(t, []interface{}{"alpha"}, tasksNote.GetAttributes()["tags"])
+	assert.NotContains(t, tasksNote.GetAttributes(), "subject")
+}
+
+func TestComputedAttributes(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	CurrentConfig().LintFile.Schemas = []ConfigLintSchema{
+		{
+			Name: "Books",
+			Path: "books/",
+			Attributes: []*ConfigLintSchemaAttribute{
+				{Name: "author", Type: "string", Required: BoolPointer(false), Inherit: BoolPointer(true)},
+				{Name: "source", Type: "string", Required: BoolPointer(false), Inherit: BoolPointer(true), Value: `{{.author}}, _{{.title}}_`},
+				{Name: "pages", Type: "number", Required: BoolPointer(false), Inherit: BoolPointer(true), Value: `{{.length}}`},
+			},
+		},
+	}
+	require.NoError(t, CurrentConfig().Check())
+
+	require.NoError(t, os.MkdirAll(filepath.Join(CurrentConfig().RootDirectory, "books"), 0755))
+	MustWriteFile(t, "books/steve-jobs.md", `---
+author: Walter Isaacson
+---
+# Steve Jobs
+
+## Reference: Steve Jobs
+
+`+"`@length: 656`"+`
+
+A biography.
+
+## Note: Without Length
+
+A note.
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	reference := MustFindNoteByPathAndTitle(t, "books/steve-jobs.md", "Reference: Steve Jobs")
+	assert.Equal(t, "Walter Isaacson, _Steve Jobs_", reference.GetAttribute("source"))
+	assert.Equal(t, int64(656), reference.GetAttribute("pages")) // Cast to the declared type
+
+	note := MustFindNoteByPathAndTitle(t, "books/steve-jobs.md", "Note: Without Length")
+	assert.Equal(t, "Walter Isaacson, _Without Length_", note.GetAttribute("source"))
+	assert.Nil(t, note.GetAttribute("pages")) // Missing attribute "length"
+
+	// Computed attributes cannot be set manually
+	MustWriteFile(t, "books/invalid.md", `# Invalid
+
+## Reference: Invalid
+
+`+"`@source: Me`"+`
+`)
+	file, err := ParseFile(filepath.Join(CurrentConfig().RootDirectory, "books/invalid.md"))
+	require.NoError(t, err)
+	violations, err := CheckAttribute(file, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*Violation{
+		{
+			Name:         "check-attribute",
+			Message:      `attribute "source" in note "Reference: Invalid" in file "books/invalid.md" is computed and cannot be set`,
+			RelativePath: "books/invalid.md",
+			Line:         5,
+		},
+	}, violations)
+
+	// Templates are validated
+	CurrentConfig().LintFile.Schemas[0].Attributes[1].Value = `{{.author`
+	assert.ErrorContains(t, CurrentConfig().Check(), `invalid value "{{.author" for attribute "source"`)
+}
+
 func TestFeatures(t *testing.T) {
 
 	t.Run("Relations", func(t *testing.T) {
@@ -280,6 +280,11 @@ func (f *Flashcard) update(file *File, note *Note) {
 		f.stale = true
 	}
 
+	if f.RelativePath != note.RelativePath {
+		f.RelativePath = note.RelativePath
+		f.stale = true
+	}
+
 	if !reflect.DeepEqual(f.Tags, note.GetTags()) {
 		f.Tags = note.GetTags()
 		f.stale = true
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Tokenizers supported by the full-text index
+const (
+	// Split on spaces and punctuation, ignoring case and diacritics (default)
+	FTSTokenizerUnicode61 = "unicode61"
+	// Like unicode61 but match English words sharing the same stem (ex: "run" matches "running")
+	FTSTokenizerPorter = "porter"
+	// Match any substring of at least 3 characters (ex: for languages without spaces)
+	FTSTokenizerTrigram = "trigram"
+)
+
+// FTS5 tokenize option for every supported tokenizer
+var ftsTokenizerDefinitions = map[string]string{
+	FTSTokenizerUnicode61: "unicode61",
+	FTSTokenizerPorter:    "porter unicode61",
+	FTSTokenizerTrigram:   "trigram",
+}
+
+// Ex: tokenize='porter unicode61'
+var regexFTSTokenize = regexp.MustCompile(`tokenize\s*=\s*'([^']*)'`)
+
+// ConfiguredFTSTokenizer returns the tokenizer to use for the full-text index.
+func (c *Config) ConfiguredFTSTokenizer() string {
+	if c.ConfigFile.Core.SearchTokenizer == "" {
+		return FTSTokenizerUnicode61
+	}
+	return c.ConfigFile.Core.SearchTokenizer
+}
+
+// FTSTokenizer returns the tokenizer used by the current full-text index.
+func (db *DB) FTSTokenizer() (string, error) {
+	return ftsTokenizer(db.Client())
+}
+
+// RebuildFTS recreates the full-text index using the given tokenizer.
+func (db *DB) RebuildFTS(tokenizer string) error {
+	if err := db.BeginTransaction(); err != nil {
+		return err
+	}
+	if err := rebuildFTS(db.Client(), tokenizer); err != nil {
+		db.RollbackTransaction()
+		return err
+	}
+	return db.CommitTransaction()
+}
+
+// Reindex recreates the full-text index using the configured tokenizer
+// and recomputes derived columns missing in existing databases (ex: word counts).
+func (r *Repository) Reindex() error {
+	tokenizer := CurrentConfig().ConfiguredFTSTokenizer()
+	CurrentLogger().Infof("Rebuilding full-text index using tokenizer %q...", tokenizer)
+	if err := CurrentDB().RebuildFTS(tokenizer); err != nil {
+		return err
+	}
+	CurrentLogger().Infof("Updating word counts...")
+	return CurrentDB().UpdateWordCounts()
+}
+
+// ftsTokenizer determines the tokenizer from the definition of the table note_fts.
+func ftsTokenizer(client SQLClient) (string, error) {
+	var definition string
+	err := client.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'note_fts'`).Scan(&definition)
+	if err != nil {
+		return "", err
+	}
+	match := regexFTSTokenize.FindStringSubmatch(definition)
+	if match == nil {
+		// SQLite default
+		return FTSTokenizerUnicode61, nil
+	}
+	for name, tokenize := range ftsTokenizerDefinitions {
+		if tokenize == match[1] {
+			return name, nil
+		}
+	}
+	return match[1], nil
+}
+
+// rebuildFTS recreates the table note_fts and reindexes all notes.
+// Triggers on the table note are left untouched as they reference the table by name.
+func rebuildFTS(client SQLClient, tokenizer string) error {
+	tokenize, ok := ftsTokenizerDefinitions[tokenizer]
+	if !ok {
+		return fmt.Errorf("unsupported search tokenizer %q", tokenizer)
+	}
+	queries := []string{
+		`DROP TABLE IF EXISTS note_fts;`,
+		fmt.Sprintf(`CREATE VIRTUAL TABLE note_fts USING FTS5(oid UNINDEXED, kind UNINDEXED, short_title, content_text, content='note', content_rowid='rowid', tokenize='%s');`, tokenize),
+		// Read all notes from the external content table
+		`INSERT INTO note_fts(note_fts) VALUES('rebuild');`,
+	}
+	for _, query := range queries {
+		if _, err := client.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initFTSTokenizer applies the configured tokenizer on new databases.
+// Existing databases must be reindexed explicitly as rebuilding the index can take time.
+func initFTSTokenizer(client SQLClient) error {
+	var count int
+	if err := client.QueryRow(`SELECT count(*) FROM note`).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	tokenizer, err := ftsTokenizer(client)
+	if err != nil {
+		return err
+	}
+	configuredTokenizer := CurrentConfig().ConfiguredFTSTokenizer()
+	if strings.EqualFold(tokenizer, configuredTokenizer) {
+		return nil
+	}
+	return rebuildFTS(client, configuredTokenizer)
+}
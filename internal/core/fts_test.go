@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReindex(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	tokenizer, err := CurrentDB().FTSTokenizer()
+	require.NoError(t, err)
+	assert.Equal(t, FTSTokenizerUnicode61, tokenizer)
+
+	// Insert a note
+	file := NewEmptyFile("example.md")
+	parsedNote := MustParseNote("## Reference: FTS5\n\nFull-text search in SQLite", "")
+	note := NewNote(file, nil, parsedNote)
+	err = CurrentDB().BeginTransaction()
+	require.NoError(t, err)
+	err = note.Insert()
+	require.NoError(t, err)
+	err = CurrentDB().CommitTransaction()
+	require.NoError(t, err)
+
+	// Substrings are not matched by default
+	notes, err := CurrentRepository().SearchNotes("kind:reference QLit")
+	require.NoError(t, err)
+	assert.Len(t, notes, 0)
+
+	CurrentConfig().ConfigFile.Core.SearchTokenizer = FTSTokenizerTrigram
+	require.NoError(t, CurrentConfig().Check())
+	err = CurrentRepository().Reindex()
+	require.NoError(t, err)
+
+	tokenizer, err = CurrentDB().FTSTokenizer()
+	require.NoError(t, err)
+	assert.Equal(t, FTSTokenizerTrigram, tokenizer)
+
+	// Existing notes are reindexed
+	notes, err = CurrentRepository().SearchNotes("kind:reference QLit")
+	require.NoError(t, err)
+	assert.Len(t, notes, 1)
+
+	// Triggers still update the index
+	note.updateContent("Full-text search in PostgreSQL")
+	err = CurrentDB().BeginTransaction()
+	require.NoError(t, err)
+	err = note.Update()
+	require.NoError(t, err)
+	err = CurrentDB().CommitTransaction()
+	require.NoError(t, err)
+	notes, err = CurrentRepository().SearchNotes("kind:reference greSQ")
+	require.NoError(t, err)
+	assert.Len(t, notes, 1)
+
+	// Unknown tokenizer
+	CurrentConfig().ConfigFile.Core.SearchTokenizer = "unknown"
+	assert.Error(t, CurrentConfig().Check())
+	assert.Error(t, CurrentDB().RebuildFTS("unknown"))
+}
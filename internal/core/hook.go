@@ -1,12 +1,13 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
-	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/julien-sobczak/the-notewriter/pkg/text"
@@ -29,12 +30,20 @@ func (n *Note) RunHooks(hookNames []string) error {
 		return nil
 	}
 
-	// Start by checking all hook executable exists
+	// Start by checking all hook commands exist
 	hookDir := filepath.Join(CurrentConfig().RootDirectory, ".nt", "hooks")
-	hookExecutables := map[string]string{}
+	hookCommands := map[string]func() *exec.Cmd{}
 	for _, hookNameRaw := range hooks {
 		hookName := hookNameRaw.(string)
 
+		// Search for a command declared in the section [hooks] of .nt/config first
+		if command, ok := CurrentConfig().ConfigFile.Hooks[hookName]; ok {
+			hookCommands[hookName] = func() *exec.Cmd {
+				return shellCommand(command)
+			}
+			continue
+		}
+
 		// Search for an executable file named `hookName(.ext)?` under `.nt/hooks`
 		var matchingExecutableFiles []string
 		filepath.WalkDir(hookDir, func(path string, info fs.DirEntry, err error) error {
@@ -70,7 +79,10 @@ func (n *Note) RunHooks(hookNames []string) error {
 		}
 
 		// Found the match!
-		hookExecutables[hookName] = matchingExecutableFiles[0]
+		exe := matchingExecutableFiles[0]
+		hookCommands[hookName] = func() *exec.Cmd {
+			return exec.Command(exe)
+		}
 	}
 
 	// Trigger the hook commands
@@ -82,9 +94,8 @@ func (n *Note) RunHooks(hookNames []string) error {
 			continue
 		}
 
-		exe := hookExecutables[hookName]
 		CurrentLogger().Infof("Running hook %q on %s...", hookName, n)
-		err := n.executeHook(exe)
+		err := n.executeHook(hookName, hookCommands[hookName]())
 		if err != nil {
 			return err
 		}
@@ -93,32 +104,38 @@ func (n *Note) RunHooks(hookNames []string) error {
 	return nil
 }
 
-// executeHook executes the given executable file path.
-func (n *Note) executeHook(exe string) error {
+// shellCommand returns a command to execute a command line declared in the configuration.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// executeHook executes the given hook command.
+func (n *Note) executeHook(hookName string, subProcess *exec.Cmd) error {
 	// We will write the JSON representation of the note to stding
 	noteJson := n.FormatToJSON()
 
-	// See https://stackoverflow.com/a/23167416
-	subProcess := exec.Command(exe)
-	stdin, err := subProcess.StdinPipe()
-	if err != nil {
-		return err
-	}
-	subProcess.Stdout = os.Stdout
-	subProcess.Stderr = os.Stderr
-
-	if err = subProcess.Start(); err != nil {
-		return err
-	}
-	io.WriteString(stdin, noteJson)
-	err = stdin.Close()
-	if err != nil {
-		return err
-	}
-	err = subProcess.Wait()
-	if err != nil {
-		return err
+	// Expose the main note properties to avoid parsing stdin for simple hooks
+	subProcess.Dir = CurrentConfig().RootDirectory
+	subProcess.Env = append(os.Environ(),
+		"NT_HOOK="+hookName,
+		"NT_NOTE_OID="+n.OID,
+		"NT_NOTE_RELATIVE_PATH="+n.RelativePath,
+		"NT_NOTE_WIKILINK="+n.Wikilink,
+	)
+
+	// Capture the output to report it when the hook fails
+	var output bytes.Buffer
+	subProcess.Stdin = strings.NewReader(noteJson)
+	subProcess.Stdout = &output
+	subProcess.Stderr = &output
+
+	if err := subProcess.Run(); err != nil {
+		return fmt.Errorf("hook %q failed on %s: %w\n%s", hookName, n, err, strings.TrimSpace(output.String()))
 	}
+	os.Stdout.Write(output.Bytes())
 
 	return nil
 }
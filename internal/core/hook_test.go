@@ -1,8 +1,11 @@
 package core
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -78,4 +81,55 @@ func TestHooks(t *testing.T) {
 		require.ErrorContains(t, err, "exit status 1")
 	})
 
+	t.Run("Config", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestHooks")
+		CurrentConfig().ConfigFile.Hooks = map[string]string{
+			"missing": `echo "$NT_HOOK $NT_NOTE_OID $NT_NOTE_RELATIVE_PATH" > hook.out`,
+		}
+
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+
+		notes, err := CurrentRepository().SearchNotes(`@title:missing`)
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		note := notes[0]
+		err = note.RunHooks(nil)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(root, "hook.out"))
+		require.NoError(t, err)
+		assert.Equal(t, "missing "+note.OID+" hooks.md\n", string(data))
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestHooks")
+		CurrentConfig().ConfigFile.Hooks = map[string]string{
+			"missing": `echo "Unable to publish" >&2; exit 2`,
+		}
+
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+
+		notes, err := CurrentRepository().SearchNotes(`@title:missing`)
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		err = notes[0].RunHooks(nil)
+		require.ErrorContains(t, err, "exit status 2")
+		require.ErrorContains(t, err, "Unable to publish")
+	})
+
+	t.Run("Ignore errors", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestHooks")
+
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+
+		err = CurrentDB().Commit("hooks failing")
+		require.Error(t, err)
+
+		CurrentConfig().IgnoreHookErrors = true
+		err = CurrentDB().Commit("hooks failing")
+		require.NoError(t, err)
+	})
 }
@@ -10,11 +10,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+	"github.com/julien-sobczak/the-notewriter/pkg/filesystem"
 	"gopkg.in/yaml.v3"
 )
 
@@ -125,6 +127,40 @@ func (sa *StagingArea) ContainsFile(relpath string) (*StagingObject, bool) {
 	return nil, false
 }
 
+// Clone returns a copy of the staging area.
+func (sa StagingArea) Clone() StagingArea {
+	var result StagingArea
+	for _, obj := range sa {
+		clone := *obj
+		result = append(result, &clone)
+	}
+	return result
+}
+
+// summarizeChanges lists the files and medias staged since the given staging area.
+func (sa StagingArea) summarizeChanges(before StagingArea) *AddSummary {
+	summary := new(AddSummary)
+	for _, obj := range sa {
+		if obj.Kind != "file" && obj.Kind != "media" {
+			continue
+		}
+		if previous, ok := before.ReadStagingObject(obj.OID); ok && bytes.Equal(previous.Data, obj.Data) {
+			// Already staged
+			continue
+		}
+		relativePath := stagingObjectRelativePath(obj)
+		switch obj.State {
+		case Added:
+			summary.Added = append(summary.Added, relativePath)
+		case Modified:
+			summary.Modified = append(summary.Modified, relativePath)
+		case Deleted:
+			summary.Deleted = append(summary.Deleted, relativePath)
+		}
+	}
+	return summary
+}
+
 // Count returns the number of objects inside the staging area.
 func (sa *StagingArea) Count() int {
 	return len(*sa)
@@ -197,14 +233,10 @@ func (i *Index) CloneForRemote() *Index {
 }
 
 // Save persists the index on disk.
+// The file is replaced atomically to never leave a corrupted index after a crash.
 func (i *Index) Save() error {
 	path := filepath.Join(CurrentConfig().RootDirectory, ".nt/index")
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	return i.Write(f)
+	return filesystem.WriteFileAtomic(path, i.Write)
 }
 
 // ReadIndexObject searches for the given object in the index.
@@ -231,6 +263,73 @@ func (i *Index) FindPackFileContaining(objectOID string) (string, bool) {
 	return indexFile.PackFileOID, true
 }
 
+// MinOIDPrefixLength is the minimal number of characters accepted to abbreviate an OID.
+const MinOIDPrefixLength = 4
+
+// ErrUnknownOID is returned when no OID starts with a given prefix.
+var ErrUnknownOID = errors.New("unknown OID")
+
+// AmbiguousOIDError is returned when several OIDs start with a given prefix.
+type AmbiguousOIDError struct {
+	Prefix     string
+	Candidates []string
+}
+
+func (e *AmbiguousOIDError) Error() string {
+	return fmt.Sprintf("short OID %s is ambiguous (candidates: %s)", e.Prefix, strings.Join(e.Candidates, ", "))
+}
+
+// ResolveOID returns the full OID of the object, pack file, or commit starting with the given prefix.
+func (i *Index) ResolveOID(prefix string) (string, error) {
+	return resolveOIDPrefix(prefix, i.knownOIDs())
+}
+
+// knownOIDs returns the OIDs of all objects, pack files, and commits referenced by the index.
+func (i *Index) knownOIDs() []string {
+	var oids []string
+	for _, obj := range i.Objects {
+		oids = append(oids, obj.OID)
+	}
+	for _, obj := range i.StagingArea {
+		oids = append(oids, obj.OID)
+	}
+	for packFileOID, commitOID := range i.PackFiles {
+		oids = append(oids, packFileOID, commitOID)
+	}
+	return oids
+}
+
+// resolveOIDPrefix searches the single OID starting with the given prefix.
+func resolveOIDPrefix(prefix string, oids []string) (string, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if len(prefix) < MinOIDPrefixLength {
+		return "", fmt.Errorf("short OID %q must contain at least %d characters", prefix, MinOIDPrefixLength)
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, oid := range oids {
+		if seen[oid] || !strings.HasPrefix(oid, prefix) {
+			continue
+		}
+		seen[oid] = true
+		candidates = append(candidates, oid)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%w %s", ErrUnknownOID, prefix)
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+		return "", &AmbiguousOIDError{
+			Prefix:     prefix,
+			Candidates: candidates,
+		}
+	}
+}
+
 // IsOrphanBlob checks if the blob has already beeing deleted.
 func (i *Index) IsOrphanBlob(oid string) bool {
 	for _, b := range i.OrphanBlobs {
@@ -279,6 +378,7 @@ func (i *Index) StageObject(obj StatefulObject) error {
 			// Do not update other properties
 			// Ex: when staging a media after the generation of blobs,
 			// the state must stay "added" even if the media has already been saved in database since.
+			// The change was already notified when the object was first staged.
 			i.StagingArea[j].Data = newStagingObject.Data
 			return nil
 		}
@@ -286,6 +386,7 @@ func (i *Index) StageObject(obj StatefulObject) error {
 
 	// Otherwise, append the new object
 	i.StagingArea = append(i.StagingArea, newStagingObject)
+	emitObjectChange(newObjectChangeEvent(obj, newStagingObject.State, ""))
 
 	return nil
 }
@@ -344,6 +445,19 @@ func (i *Index) putPackObject(commitOID string, packFileOID string, obj *PackObj
 		// Simply updates the commit OID for existing objects
 		indexObject.CommitOID = commitOID
 		indexObject.PackFileOID = packFileOID
+		if obj.Kind == "file" {
+			// Update mapping path -> object for renamed files
+			file := new(File)
+			obj.Data.Unmarshal(file)
+			if file.RelativePath != "" && i.filesRef[file.RelativePath] != indexObject {
+				for relativePath, fileRef := range i.filesRef {
+					if fileRef == indexObject {
+						delete(i.filesRef, relativePath)
+					}
+				}
+				i.filesRef[file.RelativePath] = indexObject
+			}
+		}
 		return
 	}
 
@@ -609,12 +723,7 @@ func (c *CommitGraph) SaveTo(path string) error {
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		return err
 	}
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	return c.Write(f)
+	return filesystem.WriteFileAtomic(path, c.Write)
 }
 
 /* Commit */
@@ -624,6 +733,35 @@ type Commit struct {
 	CTime     time.Time    `yaml:"ctime"`
 	MTime     time.Time    `yaml:"mtime"`
 	PackFiles PackFileRefs `yaml:"packfiles"`
+	// Optional author who created the commit (missing in old commits)
+	Author *CommitAuthor `yaml:"author,omitempty"`
+	// Optional message describing the changes (missing in old commits)
+	Message string `yaml:"message,omitempty"`
+}
+
+// CommitAuthor identifies the user and the machine creating a commit.
+type CommitAuthor struct {
+	Name string `yaml:"name"`
+	Host string `yaml:"host,omitempty"`
+}
+
+// NewCommitAuthorFromConfig returns the author declared in the configuration (nil if none).
+func NewCommitAuthorFromConfig() *CommitAuthor {
+	configCore := CurrentConfig().ConfigFile.Core
+	if configCore.Author == "" {
+		return nil
+	}
+	return &CommitAuthor{
+		Name: configCore.Author,
+		Host: configCore.AuthorHost,
+	}
+}
+
+func (a CommitAuthor) String() string {
+	if a.Host == "" {
+		return a.Name
+	}
+	return fmt.Sprintf("%s (%s)", a.Name, a.Host)
 }
 
 type PackFileRef struct {
@@ -721,24 +859,100 @@ func (p *PackObject) ReadObject() StatefulObject {
 	return nil
 }
 
-// ObjectData serializes any Object to base64 after zlib compression.
+// ObjectData serializes any Object to base64 after compression.
+//
+// The first byte is a format marker indicating how the remaining bytes were compressed.
+// Objects written before the introduction of the marker are raw zlib streams
+// (always starting with 0x78) and remain readable.
 type ObjectData []byte // alias to serialize to YAML easily
 
+// Format markers present as the first byte of ObjectData
+const (
+	objectDataFormatRaw  byte = 0x00
+	objectDataFormatZlib byte = 0x01
+	// First byte of a zlib stream using the default 32K window
+	objectDataFormatLegacyZlib byte = 0x78
+)
+
+// Supported values for the setting core.compression
+const (
+	CompressionNone    = "none"
+	CompressionFast    = "fast"
+	CompressionDefault = "default"
+	CompressionBest    = "best"
+)
+
 // NewObjectData creates a compressed-string representation of the object.
 func NewObjectData(obj Object) (ObjectData, error) {
 	b := new(bytes.Buffer)
 	if err := obj.Write(b); err != nil {
 		return nil, err
 	}
-	in := b.Bytes()
+	return compressObjectData(b.Bytes(), CurrentConfig().ConfigFile.Core.Compression)
+}
+
+// compressObjectData compresses raw bytes using the given compression setting.
+func compressObjectData(in []byte, compression string) (ObjectData, error) {
+	var level int
+	switch compression {
+	case CompressionNone:
+		return ObjectData(append([]byte{objectDataFormatRaw}, in...)), nil
+	case CompressionFast:
+		level = zlib.BestSpeed
+	case CompressionBest:
+		level = zlib.BestCompression
+	case "", CompressionDefault:
+		level = zlib.DefaultCompression
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
 
 	zb := new(bytes.Buffer)
-	w := zlib.NewWriter(zb)
+	zb.WriteByte(objectDataFormatZlib)
+	w, err := zlib.NewWriterLevel(zb, level)
+	if err != nil {
+		return nil, err
+	}
 	w.Write(in)
 	w.Close()
 	return ObjectData(zb.Bytes()), nil
 }
 
+// decompress returns the raw bytes of the object by detecting the format used.
+func (od ObjectData) decompress() ([]byte, error) {
+	if len(od) == 0 {
+		return nil, errors.New("empty object data")
+	}
+
+	var src []byte
+	switch od[0] {
+	case objectDataFormatRaw:
+		return od[1:], nil
+	case objectDataFormatZlib:
+		src = od[1:]
+	case objectDataFormatLegacyZlib:
+		src = od
+	default:
+		return nil, fmt.Errorf("unknown object data format %#x", od[0])
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Recompress returns a new representation of the same object using the given compression setting.
+func (od ObjectData) Recompress(compression string) (ObjectData, error) {
+	in, err := od.decompress()
+	if err != nil {
+		return nil, err
+	}
+	return compressObjectData(in, compression)
+}
+
 func (od ObjectData) MarshalYAML() (interface{}, error) {
 	return base64.StdEncoding.EncodeToString(od), nil
 }
@@ -757,14 +971,11 @@ func (od ObjectData) Unmarshal(target interface{}) error {
 	if target == nil {
 		return fmt.Errorf("cannot unmarshall in nil target")
 	}
-	src := bytes.NewReader(od)
-	dest := new(bytes.Buffer)
-	r, err := zlib.NewReader(src)
+	in, err := od.decompress()
 	if err != nil {
 		return err
 	}
-	io.Copy(dest, r)
-	r.Close()
+	dest := bytes.NewBuffer(in)
 
 	if f, ok := target.(*File); ok {
 		f.Read(dest)
@@ -967,6 +1178,15 @@ func (p *PackFile) Write(w io.Writer) error {
 	return err
 }
 
+// Size returns the size in bytes of the pack file once written on disk.
+func (p *PackFile) Size() (int64, error) {
+	b := new(bytes.Buffer)
+	if err := p.Write(b); err != nil {
+		return 0, err
+	}
+	return int64(b.Len()), nil
+}
+
 // Save writes a new pack file inside .nt/objects.
 func (p *PackFile) Save() error {
 	path := filepath.Join(CurrentConfig().RootDirectory, ".nt/objects/"+OIDToPath(p.OID))
@@ -974,16 +1194,12 @@ func (p *PackFile) Save() error {
 }
 
 // SaveTo writes a new pack file to the given location.
+// The file is written atomically so that a crash never leaves a partial pack file.
 func (p *PackFile) SaveTo(path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
 		return err
 	}
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	return p.Write(f)
+	return filesystem.WriteFileAtomic(path, p.Write)
 }
 
 func (p *PackFile) Blobs() []*BlobRef {
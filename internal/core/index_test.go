@@ -2,7 +2,10 @@ package core
 
 import (
 	"bytes"
+	"compress/zlib"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -12,6 +15,7 @@ import (
 
 	"github.com/julien-sobczak/the-notewriter/internal/helpers"
 	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+	"github.com/julien-sobczak/the-notewriter/pkg/filesystem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -222,6 +226,92 @@ func TestObjectData(t *testing.T) {
 	assert.Equal(t, "TODO: Backlog", noteDest.Title)
 }
 
+func TestObjectDataCompression(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	fileSrc := NewEmptyFile("todo.md")
+	noteParsedSrc := MustParseNote("## TODO: Backlog\n\n* [ ] Test ObjectData", "")
+	noteSrc := NewNote(fileSrc, nil, noteParsedSrc)
+
+	for _, compression := range []string{CompressionNone, CompressionFast, CompressionDefault, CompressionBest} {
+		t.Run(compression, func(t *testing.T) {
+			CurrentConfig().ConfigFile.Core.Compression = compression
+			data, err := NewObjectData(noteSrc)
+			require.NoError(t, err)
+
+			noteDest := new(Note)
+			err = data.Unmarshal(noteDest)
+			require.NoError(t, err)
+			assert.Equal(t, "TODO: Backlog", noteDest.Title)
+		})
+	}
+
+	t.Run("Legacy format without marker", func(t *testing.T) {
+		b := new(bytes.Buffer)
+		require.NoError(t, noteSrc.Write(b))
+		zb := new(bytes.Buffer)
+		w := zlib.NewWriter(zb)
+		w.Write(b.Bytes())
+		w.Close()
+		data := ObjectData(zb.Bytes())
+
+		noteDest := new(Note)
+		err := data.Unmarshal(noteDest)
+		require.NoError(t, err)
+		assert.Equal(t, "TODO: Backlog", noteDest.Title)
+
+		// Recompress to the new format
+		data, err = data.Recompress(CompressionBest)
+		require.NoError(t, err)
+		assert.Equal(t, objectDataFormatZlib, data[0])
+		noteDest = new(Note)
+		err = data.Unmarshal(noteDest)
+		require.NoError(t, err)
+		assert.Equal(t, "TODO: Backlog", noteDest.Title)
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		data := ObjectData([]byte{0x42, 0x00})
+		err := data.Unmarshal(new(Note))
+		require.ErrorContains(t, err, "unknown object data format")
+	})
+}
+
+// BenchmarkObjectDataCompression compares the size of objects using the different compression settings.
+func BenchmarkObjectDataCompression(b *testing.B) {
+	var contents [][]byte
+	err := filepath.Walk("testdata/example/", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, content)
+		return nil
+	})
+	require.NoError(b, err)
+
+	for _, compression := range []string{CompressionNone, CompressionFast, CompressionDefault, CompressionBest} {
+		b.Run(compression, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				size = 0
+				for _, content := range contents {
+					data, err := compressObjectData(content, compression)
+					require.NoError(b, err)
+					size += len(data)
+				}
+			}
+			b.ReportMetric(float64(size), "bytes")
+		})
+	}
+}
+
 func TestPackFile(t *testing.T) {
 
 	// Make tests reproductible
@@ -254,13 +344,13 @@ objects:
       state: added
       mtime: 2023-01-01T01:12:30Z
       desc: 'note "Reference: Golang History" [93267c32147a4ab7a1100ce82faab56a99fca1cd]'
-      data: eJzEUsFq20AQvesrBvngBCJrJTu2vNimNxd6KSGnlqKMtKPVYmlXrNZxA/34Ikt24jYNlAa6t5l58+bN2zFKcFhO4/kin8bRbIEzzBYYRYzllMQFYnY7x+WyyDHKhddWe8lBmsBSQZZ0ToE0FWoZlKp1xj55haoo/TvSBi1pl2rjhk7f93ZKCw7nKZ5TriIO47tThsP2OBg+9oPHXmW0TAfcZc1rS2PdH2qWKnTqkdIGXdntNqmFd1A7VSm94zCWZvTGUHTOqmzvqOUeAEBr9rbDlc41LQ9D0pOOqyGhcGKsDLso3Jr0qrFGWqxrpWXace5R0vWRw6Ec2LoXgDQvgpPPR+CrCz23n1uf2yqliUPi5Ua7znSLBw4/giPqYTSgHrw+/vCP2ww8X3uB365ONNJMBD2GwuQh+CNpQmn8azhgC4JaJTUJyJ7gzmRkHWwtqZZqsjddBj6rHd0AagGfSMN9aeqmNRrQwdYYWREoDTFji8l5xRLbkgOjDJN5nmSMJTmL4ziZL6m4nd4mYpZglsSzqCjyZLi0tEa7E+agOYxHv316DyldXXFYldHmsr4Ky2gzYBx9d2d/f/mnLrW+eGfJL6b/H/PGz+71WzabFUJpqVj7rwjxwaGV5NZ+mlWod35/muuTvsGhVYib91a6CpvNWWxv9+Dzu9+TJXQkUnQcYhZPAxYFLLpnEY9iPmVfvH0j3gb8DAAA//9fO8O2
+      data: AXicxFJNa9tMEL7rVwzywQnE1oe/5MU2780v9FJCTi1FGWlHq8XSjlit4wb644ts2YlpGigNVOgyu888XxJrKWA5ieeLfBJH0wVOMVtgFIVhTklcIGazOS6XRY5RLr222isBikeWCrJkchoprtCoUalbx/bZK3RF6Z+RNmjJuNSw6zd939tpIwVcVDynXUUChvfnEwHbozD8fxIeehUblfa46zuvLdm639xZqtDpJ0obdGWXbVxL76B3utJmJ2CoePCOKDpndbZ31AoPAKDlve3Mlc41rQgCMuOOqyGpccxWBd0UbDm9aSwri3WtjUo7Q3tUdHvkcKh6tu4dgeJXw7nnbnw70Mv6ZfVlrdKGBCRezsZ1pVs8CPgxOqIeBz3q0TvN//1lmp7n66nxbzdnGsVjSU+B5DwAf6A4UOzfwgFbkNRqZUhC9gz3nJF1sLWkW6rJ3nUn8Fnv6A7QSPhEBh5KrpuWDaCDLbOqCLSBOAwX40vEEttSQEgZJvM8ycIwycM4jpP5korZZJbIaYJZEk+josiT/k9La7Q7yQcjYDj45aOfIKWrKwGrMtpc36+CMtr0NI6+u0u/17Dj0frquVh+pf5vyhu+tHdK2WxWCKWlYu2/YcQHh1aRW/tpVqHZ+XCMvz776xtaBbj5aKeroNlczJ7q7nv+YCHvwFamOe+NExDNvNwSOpIpOgFxGE9GYTQKo4cwElEsJuEXb9/I9wE/BwBRdsjE
     - oid: 93267c32147a4ab7a1100ce82faab56a99fca1cd
       kind: flashcard
       state: added
       mtime: 2023-01-01T01:12:30Z
       desc: flashcard "Golang Logo" [93267c32147a4ab7a1100ce82faab56a99fca1cd]
-      data: eJykkE9r20AQxe/6FFOd2gXZu5Jq14us4lMvPRYKLUWMtWtJWNKI1TTJIR8+6E+MkkCwCcxpeO/xe48qo2EXhZttHoUq3mKMxy0qJWVuv4UnxOPXDe52pxxVbry+JMcZV1xbDT+oxraAn1SQd6pqm92W1RLfanG2Rq7ubNYhlxoKWjXGYyx67QEABDCQOGo5a9CdDd23Gn6XyGDI9sClBSFm6poKEgKc7ZztbcvfvSPm54XvMRgzDyBEQV1pnRArb3x9+jtU/vd5YI9lfM3JLzNXyU2tIenSl1hJz47aIl3AJev5t2BM1l06cU45M2PSpYdLxAR7ca9Gz7MsaaypEKgye/9adB+w5r0/dPZhnY55Uxm2D/x64EWDN+NO+suwE+mHRs2dRbYmQ9YQyjAKpAqk+iWVVqGO5B/vf2feFzwFAAD//+eX4GM=
+      data: AXicpJBPS8NAEMXv+RRjTrqQdjeJrV3SSE9ePAqCImGa3SahSTZsxj8HP7zkjyVaEYswp+G9x+89UygJq8BfLNPAF+ESQ9wuUQjOU33l7xC3lwtcrXYpilQ5bW4sJVRQqSXcmBLrDG5NZpxdUerktKza0KkWq0uk4kUnDVIuITOzSjmEWSsdAAAPOhJrakoqtHtlXmsJ9zkSKKNboFwDYyN1aTLDGFjdWN3qmq6dLab7ie/d6zM3wFhmmlxbxmZO/zp77Co/nXfsIQ//cvxi5MqpKiVETfwVK2rJmjqLJ3DRfPxNGKN5Ew+cQ87IGDXx5hAxwB7cs97zKYsqrQoEU6i1+yPo8XEXsKS123V2YR73eUMZ0m/0feBJg6NxB/1h2IH0X6OmViNplSBJ8LkfeFx4XNxxIYUvA/7gPDfqd8HHAOeX4GM=
 `), strings.TrimSpace(cYAML))
 
 		// Unmarshall YAML
@@ -399,6 +489,40 @@ Guido van Rossum
 		assert.Equal(t, 2, idx.StagingArea.CountByState(Added))
 	})
 
+	t.Run("Partial write", func(t *testing.T) {
+		// Make tests reproductible
+		UseSequenceOID(t)
+
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		f, err := NewFileFromPath(nil, filepath.Join(root, "go.md"))
+		require.NoError(t, err)
+
+		idx := NewIndex()
+		idx.StageObject(f.GetNotes()[0])
+		err = idx.Save()
+		require.NoError(t, err)
+
+		// Simulate a crash while writing a new version of the index
+		path := filepath.Join(root, ".nt/index")
+		err = filesystem.WriteFileAtomic(path, func(w io.Writer) error {
+			if _, err := io.WriteString(w, "objects:\n  - oid: "); err != nil {
+				return err
+			}
+			return errors.New("crash")
+		})
+		require.Error(t, err)
+
+		// The previous index must survive
+		idx = ReadIndex()
+		assert.Equal(t, 1, idx.StagingArea.CountByState(Added))
+		entries, err := os.ReadDir(filepath.Join(root, ".nt"))
+		require.NoError(t, err)
+		for _, entry := range entries {
+			assert.NotContains(t, entry.Name(), ".tmp-")
+		}
+	})
+
 	t.Run("Diff", func(t *testing.T) {
 		// The two indices we will compare
 		i1 := NewIndex()
@@ -480,3 +604,76 @@ func NewIndexOrphanBlobWithOID(oid string) *IndexOrphanBlob {
 		MediaOID: "848be7af-8d4e-4405-8a5c-58c9a9efaace",
 	}
 }
+
+func TestResolveOID(t *testing.T) {
+
+	t.Run("Index", func(t *testing.T) {
+		idx := &Index{
+			Objects: []*IndexObject{
+				{OID: "4a7b9c36e5e4c4b4b4d1f14b6b07e1ce4bcd8a3e"},
+				{OID: "4a7b1f8f1c9ce16d2ad94ee0cf1fb5de3e09a32b"},
+			},
+			StagingArea: StagingArea{
+				{PackObject: PackObject{OID: "93267c32147a4ab7a1100ce82faab56a99fca1cd"}},
+			},
+			PackFiles: map[string]string{
+				"c1ab3c2e4db4b3cc4a1cd1c9c6e5d8ee4c9df6ae": "e6b1d5e1db3c9fce8bcd0e3e9ba0c37d5f4f8a01",
+			},
+		}
+
+		oid, err := idx.ResolveOID("4a7b9")
+		require.NoError(t, err)
+		assert.Equal(t, "4a7b9c36e5e4c4b4b4d1f14b6b07e1ce4bcd8a3e", oid)
+		oid, err = idx.ResolveOID("9326")
+		require.NoError(t, err)
+		assert.Equal(t, "93267c32147a4ab7a1100ce82faab56a99fca1cd", oid)
+		oid, err = idx.ResolveOID("C1AB3C") // case-insensitive
+		require.NoError(t, err)
+		assert.Equal(t, "c1ab3c2e4db4b3cc4a1cd1c9c6e5d8ee4c9df6ae", oid)
+		oid, err = idx.ResolveOID("e6b1d5e1db3c9fce8bcd0e3e9ba0c37d5f4f8a01")
+		require.NoError(t, err)
+		assert.Equal(t, "e6b1d5e1db3c9fce8bcd0e3e9ba0c37d5f4f8a01", oid)
+
+		// Ambiguous prefix
+		_, err = idx.ResolveOID("4a7b")
+		var ambiguousErr *AmbiguousOIDError
+		require.ErrorAs(t, err, &ambiguousErr)
+		assert.Equal(t, []string{
+			"4a7b1f8f1c9ce16d2ad94ee0cf1fb5de3e09a32b",
+			"4a7b9c36e5e4c4b4b4d1f14b6b07e1ce4bcd8a3e",
+		}, ambiguousErr.Candidates)
+
+		// Unknown prefix
+		_, err = idx.ResolveOID("ffff")
+		assert.ErrorIs(t, err, ErrUnknownOID)
+
+		// Too short prefix
+		_, err = idx.ResolveOID("4a7")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUnknownOID)
+	})
+
+	t.Run("DB", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+		err := CurrentRepository().Add("go.md")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+
+		note := MustFindNoteByPathAndTitle(t, "go.md", "Reference: Golang History")
+		oid, err := CurrentDB().ResolveOID(note.OID[:10])
+		require.NoError(t, err)
+		assert.Equal(t, note.OID, oid)
+
+		head := CurrentDB().Head()
+		oid, err = CurrentDB().ResolveOID(head.OID[:10])
+		require.NoError(t, err)
+		assert.Equal(t, head.OID, oid)
+
+		// Notes can be found using a short OID
+		actual, err := CurrentRepository().ResolveNote(note.OID[:10])
+		require.NoError(t, err)
+		require.NotNil(t, actual)
+		assert.Equal(t, note.OID, actual.OID)
+	})
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,6 +18,7 @@ type Link struct {
 	OID string `yaml:"oid"`
 
 	NoteOID string `yaml:"note_oid"`
+	Note    *Note  `yaml:"-"` // Lazy-loaded
 
 	// The filepath of the file containing the note (denormalized field)
 	RelativePath string `yaml:"relative_path"`
@@ -153,6 +157,10 @@ func (l *Link) update(note *Note, text, url, title, goName string) {
 		l.NoteOID = note.OID
 		l.stale = true
 	}
+	if l.RelativePath != note.RelativePath {
+		l.RelativePath = note.RelativePath
+		l.stale = true
+	}
 	if l.Text != text {
 		l.Text = text
 		l.stale = true
@@ -171,6 +179,21 @@ func (l *Link) update(note *Note, text, url, title, goName string) {
 	}
 }
 
+// GetNote returns the note declaring the link.
+func (l *Link) GetNote() *Note {
+	if l.NoteOID == "" {
+		return nil
+	}
+	if l.Note == nil {
+		note, err := CurrentRepository().LoadNoteByOID(l.NoteOID)
+		if err != nil {
+			log.Fatalf("Unable to find note %q: %v", l.NoteOID, err)
+		}
+		l.Note = note
+	}
+	return l.Note
+}
+
 /* State Management */
 
 func (l *Link) New() bool {
@@ -312,6 +335,78 @@ func (r *Repository) FindLinkByGoName(goName string) (*Link, error) {
 	return QueryLink(CurrentDB().Client(), "WHERE go_name = ?", goName)
 }
 
+// ResolveGoLink returns the link with the given Go name (nil if none).
+// A leading "go/" is ignored when no Go name matches exactly (ex: URLs from a go/ redirector).
+func (r *Repository) ResolveGoLink(goName string) (*Link, error) {
+	link, err := r.FindLinkByGoName(goName)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil && strings.HasPrefix(goName, "go/") {
+		return r.FindLinkByGoName(strings.TrimPrefix(goName, "go/"))
+	}
+	return link, nil
+}
+
+// GoLinksHandler returns an HTTP handler redirecting /<goname> to the URL of the Go link.
+// Links are read from the database on every request to reflect the last added notes.
+func (r *Repository) GoLinksHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		goName := strings.Trim(req.URL.Path, "/")
+		if goName == "" {
+			http.NotFound(w, req)
+			return
+		}
+		link, err := r.ResolveGoLink(goName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if link == nil {
+			http.NotFound(w, req)
+			return
+		}
+		http.Redirect(w, req, link.URL, http.StatusFound)
+	})
+}
+
+// FindGoLinks returns the links whose Go name, URL, or text contains the query, sorted by Go name.
+// All links are returned when the query is empty.
+func (r *Repository) FindGoLinks(query string) ([]*Link, error) {
+	pattern := "%" + query + "%"
+	return QueryLinks(CurrentDB().Client(), `WHERE go_name LIKE ? OR url LIKE ? OR "text" LIKE ? ORDER BY go_name`, pattern, pattern, pattern)
+}
+
+// FindDuplicateGoNames returns the notes declaring every Go name present in different notes.
+// Only the last processed note is kept in database for a Go name declared multiple times.
+func (r *Repository) FindDuplicateGoNames() (map[string][]*Note, error) {
+	notes, err := QueryNotes(CurrentDB().Client(), `WHERE content_raw LIKE ? ORDER BY relative_path, line`, "%#go/%")
+	if err != nil {
+		return nil, err
+	}
+
+	notesByGoName := make(map[string][]*Note)
+	for _, note := range notes {
+		for _, goLink := range parseGoLinks(note.ContentRaw) {
+			if !slices.Contains(notesByGoName[goLink.GoName], note) {
+				notesByGoName[goLink.GoName] = append(notesByGoName[goLink.GoName], note)
+			}
+		}
+	}
+
+	results := make(map[string][]*Note)
+	for goName, goNameNotes := range notesByGoName {
+		if len(goNameNotes) > 1 {
+			results[goName] = goNameNotes
+		}
+	}
+	return results, nil
+}
+
 func (r *Repository) FindLinksByText(text string) ([]*Link, error) {
 	return QueryLinks(CurrentDB().Client(), "WHERE text = ?", text)
 }
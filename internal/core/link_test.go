@@ -2,6 +2,8 @@ package core
 
 import (
 	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -50,3 +52,86 @@ updated_at: 2023-01-01T01:12:30Z
 	})
 
 }
+
+func TestFindGoLinks(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+	MustWriteFile(t, "go.md", `# Go
+
+## Note: Links
+
+* [Go](https://go.dev "#go/go")
+* [Go Playground](https://go.dev/play "Playground #go/play")
+* [Python](https://www.python.org)
+`)
+	MustWriteFile(t, "golang.md", `# Golang
+
+## Note: Playground
+
+* [Playground](https://play.golang.org "#go/play")
+`)
+	require.NoError(t, CurrentRepository().Add("."))
+
+	links, err := CurrentRepository().FindGoLinks("")
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "go", links[0].GoName)
+	assert.Equal(t, "https://go.dev", links[0].URL)
+	assert.Equal(t, "Note: Links", links[0].GetNote().Title)
+	assert.Equal(t, "play", links[1].GoName)
+
+	links, err = CurrentRepository().FindGoLinks("play")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "play", links[0].GoName)
+
+	duplicates, err := CurrentRepository().FindDuplicateGoNames()
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	require.Len(t, duplicates["play"], 2)
+	assert.Equal(t, "go.md", duplicates["play"][0].RelativePath)
+	assert.Equal(t, "golang.md", duplicates["play"][1].RelativePath)
+}
+
+func TestGoLinksHandler(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+	MustWriteFile(t, "go.md", `# Go
+
+## Note: Links
+
+* [Go](https://go.dev/doc/ "#go/go")
+* [Go Playground](https://go.dev/play/ "#go/go/playground")
+`)
+	require.NoError(t, CurrentRepository().Add("."))
+	handler := CurrentRepository().GoLinksHandler()
+
+	var tests = []struct {
+		method   string
+		path     string
+		status   int
+		location string
+	}{
+		{method: http.MethodGet, path: "/go", status: http.StatusFound, location: "https://go.dev/doc/"},
+		{method: http.MethodGet, path: "/go/playground", status: http.StatusFound, location: "https://go.dev/play/"},
+		{method: http.MethodHead, path: "/go/", status: http.StatusFound, location: "https://go.dev/doc/"},
+		{method: http.MethodGet, path: "/go/go/playground", status: http.StatusFound, location: "https://go.dev/play/"},
+		{method: http.MethodGet, path: "/unknown", status: http.StatusNotFound},
+		{method: http.MethodGet, path: "/", status: http.StatusNotFound},
+		{method: http.MethodPost, path: "/go", status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, tt.location, recorder.Header().Get("Location"))
+		})
+	}
+
+	// Links are read on every request
+	MustWriteFile(t, "python.md", "# Python\n\n## Note: Links\n\n* [Python](https://www.python.org \"#go/python\")\n")
+	require.NoError(t, CurrentRepository().Add("."))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/python", nil))
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, "https://www.python.org", recorder.Header().Get("Location"))
+}
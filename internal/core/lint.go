@@ -1,15 +1,19 @@
 package core
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
-	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/julien-sobczak/the-notewriter/internal/helpers"
 	"github.com/julien-sobczak/the-notewriter/pkg/markdown"
 	"github.com/julien-sobczak/the-notewriter/pkg/resync"
 	"github.com/julien-sobczak/the-notewriter/pkg/text"
@@ -21,6 +25,8 @@ type LintResult struct {
 	AffectedFiles int
 	Warnings      []*Violation
 	Errors        []*Violation
+	// Files that cannot be parsed (keep-going mode only)
+	FileErrors FileErrors
 }
 
 // Append merges new violations into the current result.
@@ -35,6 +41,17 @@ func (r *LintResult) Append(violations ...*Violation) {
 	}
 }
 
+// Suggestions returns the proposed fixes for violations.
+func (r LintResult) Suggestions() []string {
+	var results []string
+	for _, violation := range append(r.Errors, r.Warnings...) {
+		if violation.Suggestion != "" {
+			results = append(results, fmt.Sprintf("%s:%d: use %s (%s)", violation.RelativePath, violation.Line, violation.Suggestion, violation.Message))
+		}
+	}
+	return results
+}
+
 func (r LintResult) String() string {
 	var res strings.Builder
 	res.WriteString(fmt.Sprintf("%d invalid files on %d analyzed files (%d errors, %d warnings)\n",
@@ -48,9 +65,176 @@ func (r LintResult) String() string {
 	for _, violation := range r.Warnings {
 		res.WriteString(fmt.Sprintf("[WARNING] %s (%s:%d)\n", violation.Message, violation.RelativePath, violation.Line))
 	}
+	for _, fileError := range r.FileErrors {
+		res.WriteString(fmt.Sprintf("[ERROR] %v (%s)\n", fileError.Err, fileError.RelativePath))
+	}
 	return res.String()
 }
 
+// LintThreshold determines when the violations must make the linter fail (ex: in CI).
+type LintThreshold struct {
+	// Minimal severity of violations to fail: "error", "warning", or "none" to never fail.
+	FailOn string
+	// Maximal number of warnings accepted (negative means unlimited).
+	MaxWarnings int
+}
+
+// NewLintThreshold validates the threshold settings.
+func NewLintThreshold(failOn string, maxWarnings int) (*LintThreshold, error) {
+	if failOn == "" {
+		failOn = "none"
+	}
+	if !slices.Contains([]string{"none", "warning", "error"}, failOn) {
+		return nil, fmt.Errorf("invalid severity %q (expected none, warning, or error)", failOn)
+	}
+	return &LintThreshold{
+		FailOn:      failOn,
+		MaxWarnings: maxWarnings,
+	}, nil
+}
+
+// Check returns an error when the result exceeds the threshold.
+func (t LintThreshold) Check(r *LintResult) error {
+	switch t.FailOn {
+	case "warning":
+		if len(r.Errors) > 0 || len(r.Warnings) > 0 {
+			return fmt.Errorf("%d errors and %d warnings found", len(r.Errors), len(r.Warnings))
+		}
+	case "error":
+		if len(r.Errors) > 0 {
+			return fmt.Errorf("%d errors found", len(r.Errors))
+		}
+	}
+	if t.MaxWarnings >= 0 && len(r.Warnings) > t.MaxWarnings {
+		return fmt.Errorf("%d warnings found (max is %d)", len(r.Warnings), t.MaxWarnings)
+	}
+	return nil
+}
+
+/* SARIF */
+
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// LintToSARIF writes the violations in SARIF 2.1.0 format (ex: to report violations in code scanning UIs).
+func (r *Repository) LintToSARIF(result *LintResult, w io.Writer) error {
+	lintFile := CurrentConfig().LintFile
+
+	results := []sarifResult{}
+	ruleNames := make(map[string]bool)
+	for _, violation := range append(result.Errors, result.Warnings...) {
+		ruleNames[violation.Name] = true
+
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{
+					URI: violation.RelativePath,
+				},
+			},
+		}
+		if violation.Line > 0 { // SARIF lines start at 1
+			location.PhysicalLocation.Region = &sarifRegion{
+				StartLine: violation.Line,
+			}
+		}
+
+		level := "error"
+		if lintFile.Severity(violation.Name) == "warning" {
+			level = "warning"
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    violation.Name,
+			Level:     level,
+			Message:   sarifMessage{Text: violation.Message},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	rules := []sarifRule{}
+	for name := range ruleNames {
+		rules = append(rules, sarifRule{ID: name})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	report := sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "the-notewriter",
+						InformationURI: "https://github.com/julien-sobczak/the-notewriter",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
 type Violation struct {
 	// The name of the violation
 	Name string
@@ -60,10 +244,14 @@ type Violation struct {
 	RelativePath string
 	// The line number in the file containing the violation
 	Line int
+	// An optional fix to apply (ex: a unique slug)
+	Suggestion string
 }
 
 type LintRuleDefinition struct {
 	Eval LintRule
+	// Severity used when not overridden in .nt/lint (default to "error")
+	Severity string
 }
 
 // LintRule describes the interface that rules must conform.
@@ -95,16 +283,52 @@ var LintRules = map[string]LintRuleDefinition{
 		Eval: NoteTitleMatch,
 	},
 
+	// Headings must not skip a level relative to their parent
+	"consistent-heading-levels": {
+		Eval: ConsistentHeadingLevels,
+	},
+
+	// Files must contain the headings declared by their file type
+	"file-structure": {
+		Eval: FileStructure,
+	},
+
+	// Enforce a maximum nesting depth between typed notes
+	"max-note-depth": {
+		Eval: MaxNoteDepth,
+	},
+
+	// Enforce a maximum line length
+	"max-line-length": {
+		Eval: MaxLineLength,
+	},
+
+	// Forbid trailing whitespace
+	"no-trailing-whitespace": {
+		Eval: NoTrailingWhitespace,
+	},
+
 	// Forbid untyped notes
 	"no-free-note": {
 		Eval: NoFreeNote,
 	},
 
+	// Flashcards must contain exactly one front/back separator
+	"require-flashcard-separator": {
+		Eval: RequireFlashcardSeparator,
+	},
+
 	// Path to media files must exist
 	"no-dangling-media": {
 		Eval: NoDanglingMedia,
 	},
 
+	// Media files must not be duplicated under different names
+	"no-duplicate-media": {
+		Eval:     NoDuplicateMedia,
+		Severity: "warning",
+	},
+
 	// Links between notes must exist
 	"no-dead-wikilink": {
 		Eval: NoDeadWikilink,
@@ -175,8 +399,8 @@ func GetSchemaAttributes(relativePath string, kind NoteKind) []*ConfigLintSchema
 	//
 	// We must use the second schema when both apply.
 
-	var matchingSchemas []ConfigLintSchema
-	for _, schema := range CurrentConfig().LintFile.Schemas {
+	var matchingSchemas []declaredSchema
+	for i, schema := range CurrentConfig().LintFile.Schemas {
 		if schema.Path != "" && !strings.HasPrefix(relativePath, schema.Path) {
 			// Path does not match
 			continue
@@ -185,7 +409,7 @@ func GetSchemaAttributes(relativePath string, kind NoteKind) []*ConfigLintSchema
 			// Kind does not match
 			continue
 		}
-		matchingSchemas = append(matchingSchemas, schema)
+		matchingSchemas = append(matchingSchemas, declaredSchema{ConfigLintSchema: schema, Order: i})
 	}
 	if len(matchingSchemas) == 0 {
 		// No attributes defined in schemas
@@ -193,17 +417,8 @@ func GetSchemaAttributes(relativePath string, kind NoteKind) []*ConfigLintSchema
 	}
 
 	// Sort from most specific to least specific
-	slices.SortFunc(matchingSchemas, func(a, b ConfigLintSchema) bool {
-		// Most specific path first
-		if a.Path != b.Path {
-			return strings.HasPrefix(a.Path, b.Path)
-		}
-		if a.Kind != "" && b.Kind == "" {
-			return true
-		} else if a.Kind == "" && b.Kind != "" {
-			return false
-		}
-		return false // both have same priority... (NB: SortFunc is not stable...)
+	slices.SortFunc(matchingSchemas, func(a, b declaredSchema) bool {
+		return compareSchemaSpecificity(a, b) < 0
 	})
 
 	resultsMap := make(map[string]*ConfigLintSchemaAttribute)
@@ -227,6 +442,33 @@ func GetSchemaAttributes(relativePath string, kind NoteKind) []*ConfigLintSchema
 	return results
 }
 
+// declaredSchema is a schema with its position in the lint file.
+type declaredSchema struct {
+	ConfigLintSchema
+	Order int
+}
+
+// compareSchemaSpecificity returns a negative number when a is more specific than b.
+// Schemas applying to the same note have paths prefixing each other, so the longest path
+// is the most specific, then schemas restricted to a kind. Equally-specific schemas are
+// ordered by name, then by declaration order, so that attributes are resolved the same way
+// on every run.
+func compareSchemaSpecificity(a, b declaredSchema) int {
+	if len(a.Path) != len(b.Path) {
+		return len(b.Path) - len(a.Path)
+	}
+	if (a.Kind != "") != (b.Kind != "") {
+		if a.Kind != "" {
+			return -1
+		}
+		return 1
+	}
+	if a.Name != b.Name {
+		return strings.Compare(a.Name, b.Name)
+	}
+	return a.Order - b.Order
+}
+
 /* Rules */
 
 // NoDuplicateNoteTitle implements the rule "no-duplicate-note-title".
@@ -279,11 +521,17 @@ func NoDuplicateSlug(file *ParsedFileOld, args []string) ([]*Violation, error) {
 
 		// Check if not already in use
 		if _, ok := slugInventory[slug]; ok {
+			// Propose a unique slug
+			uniqueSlug := DisambiguateSlug(slug, func(slug string) bool {
+				return slugInventory[slug]
+			})
+			slugInventory[uniqueSlug] = true
 			violations = append(violations, &Violation{
 				Name:         "no-duplicate-slug",
 				Message:      fmt.Sprintf("duplicated slug %q", slug),
 				RelativePath: file.RelativePath,
 				Line:         file.AbsoluteBodyLine(note.Line),
+				Suggestion:   fmt.Sprintf("`@slug: %s`", uniqueSlug),
 			})
 		} else {
 			if markdown.Slug(slug) != slug {
@@ -307,12 +555,9 @@ func NoDuplicateSlug(file *ParsedFileOld, args []string) ([]*Violation, error) {
 func MinLinesBetweenNotes(file *ParsedFileOld, args []string) ([]*Violation, error) {
 	var violations []*Violation
 
-	if len(args) != 1 {
-		return nil, errors.New("only a single argument is required")
-	}
-	minLines, err := strconv.Atoi(args[0])
+	minLines, kind, err := parseLinesBetweenNotesArgs(args)
 	if err != nil {
-		return nil, fmt.Errorf("argument %s must be an integer", args[0])
+		return nil, err
 	}
 
 	body := file.Body
@@ -324,6 +569,9 @@ func MinLinesBetweenNotes(file *ParsedFileOld, args []string) ([]*Violation, err
 			// No need to check space before the first note. Only between successive notes
 			continue
 		}
+		if kind != "" && note.Kind != kind {
+			continue
+		}
 
 		for j := 1; j <= minLines; j++ {
 			lineNumber := note.Line - j
@@ -346,12 +594,9 @@ func MinLinesBetweenNotes(file *ParsedFileOld, args []string) ([]*Violation, err
 func MaxLinesBetweenNotes(file *ParsedFileOld, args []string) ([]*Violation, error) {
 	var violations []*Violation
 
-	if len(args) != 1 {
-		return nil, errors.New("only a single argument is required")
-	}
-	maxLines, err := strconv.Atoi(args[0])
+	maxLines, kind, err := parseLinesBetweenNotesArgs(args)
 	if err != nil {
-		return nil, fmt.Errorf("argument %s must be an integer", args[0])
+		return nil, err
 	}
 
 	body := file.Body
@@ -359,6 +604,9 @@ func MaxLinesBetweenNotes(file *ParsedFileOld, args []string) ([]*Violation, err
 
 	notes := ParseNotes(body, file.Slug)
 	for _, note := range notes {
+		if kind != "" && note.Kind != kind {
+			continue
+		}
 
 		countBlankLinesBefore := 0
 
@@ -391,6 +639,23 @@ func MaxLinesBetweenNotes(file *ParsedFileOld, args []string) ([]*Violation, err
 	return violations, nil
 }
 
+// parseLinesBetweenNotesArgs parses the arguments of the rules "min-lines-between-notes" and "max-lines-between-notes":
+// a number of lines and an optional note kind to restrict the notes to check (ex: [2, flashcard]).
+func parseLinesBetweenNotesArgs(args []string) (int, NoteKind, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return 0, "", errors.New("a number of lines and an optional note kind are required")
+	}
+	lines, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("argument %s must be an integer", args[0])
+	}
+	var kind NoteKind
+	if len(args) == 2 {
+		kind = NoteKind(args[1])
+	}
+	return lines, kind, nil
+}
+
 // NoteTitleMatch implements the rule "note-title-match".
 func NoteTitleMatch(file *ParsedFileOld, args []string) ([]*Violation, error) {
 	var violations []*Violation
@@ -443,6 +708,294 @@ func NoFreeNote(file *ParsedFileOld, args []string) ([]*Violation, error) {
 	return violations, nil
 }
 
+// ConsistentHeadingLevels implements the rule "consistent-heading-levels".
+func ConsistentHeadingLevels(file *ParsedFileOld, args []string) ([]*Violation, error) {
+	var violations []*Violation
+
+	// Ignore headings inside code blocks (ex: a sample Markdown code block)
+	body := markdown.CleanCodeBlocks(file.Body)
+
+	previousLevel := 0
+	for i, line := range strings.Split(body, "\n") {
+		ok, title, level := markdown.IsHeading(line)
+		if !ok {
+			continue
+		}
+		if previousLevel > 0 && level > previousLevel+1 {
+			violations = append(violations, &Violation{
+				Name:         "consistent-heading-levels",
+				RelativePath: file.RelativePath,
+				Message:      fmt.Sprintf("heading %q skips a level (expected level %d but got %d)", title, previousLevel+1, level),
+				Line:         file.AbsoluteBodyLine(i + 1),
+			})
+		}
+		previousLevel = level
+	}
+
+	return violations, nil
+}
+
+// fileHeading is a heading with its subheadings.
+type fileHeading struct {
+	Level      int
+	Kind       NoteKind
+	ShortTitle string
+	Line       int // Relative to the file body
+	Children   []*fileHeading
+}
+
+// parseFileHeadings returns the headings under the file title as a tree.
+func parseFileHeadings(body string) []*fileHeading {
+	root := &fileHeading{}
+	parents := []*fileHeading{root}
+
+	// Ignore headings inside code blocks (ex: a sample Markdown code block)
+	body = markdown.CleanCodeBlocks(body)
+	for i, line := range strings.Split(body, "\n") {
+		ok, title, level := markdown.IsHeading(line)
+		if !ok {
+			continue
+		}
+		_, kind, shortTitle := isSupportedNote(title)
+		heading := &fileHeading{
+			Level:      level,
+			Kind:       kind,
+			ShortTitle: shortTitle,
+			Line:       i + 1,
+		}
+		for parents[len(parents)-1].Level >= level {
+			parents = parents[:len(parents)-1]
+		}
+		parent := parents[len(parents)-1]
+		parent.Children = append(parent.Children, heading)
+		parents = append(parents, heading)
+	}
+
+	// Skip the file title
+	if len(root.Children) == 1 && root.Children[0].Level == 1 {
+		return root.Children[0].Children
+	}
+	return root.Children
+}
+
+// FileStructure implements the rule "file-structure".
+func FileStructure(file *ParsedFileOld, args []string) ([]*Violation, error) {
+	var violations []*Violation
+
+	var headings []*fileHeading
+	for _, fileType := range CurrentConfig().LintFile.FileTypes {
+		if fileType.Path != "" && !strings.HasPrefix(file.RelativePath, fileType.Path) {
+			// Path does not match
+			continue
+		}
+		if headings == nil {
+			headings = parseFileHeadings(file.Body)
+		}
+		newViolations, err := checkFileHeadings(file, fileType.Name, 1, fileType.Headings, headings)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, newViolations...)
+	}
+
+	return violations, nil
+}
+
+// checkFileHeadings reports the specifications not satisfied by headings sharing the same parent.
+func checkFileHeadings(file *ParsedFileOld, fileTypeName string, parentLine int, specs []*ConfigLintHeading, headings []*fileHeading) ([]*Violation, error) {
+	var violations []*Violation
+
+	for _, spec := range specs {
+		match, err := regexp.Compile(spec.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in file type %q: %v", spec.Match, fileTypeName, err)
+		}
+
+		var matchingHeadings []*fileHeading
+		for _, heading := range headings {
+			if spec.Kind != "" && string(heading.Kind) != spec.Kind {
+				continue
+			}
+			if !match.MatchString(heading.ShortTitle) {
+				continue
+			}
+			matchingHeadings = append(matchingHeadings, heading)
+		}
+
+		if spec.Required && len(matchingHeadings) == 0 {
+			violations = append(violations, &Violation{
+				Name:         "file-structure",
+				RelativePath: file.RelativePath,
+				Message:      fmt.Sprintf("missing required %s in file type %q", spec, fileTypeName),
+				Line:         file.AbsoluteBodyLine(parentLine),
+			})
+		}
+		if !spec.AllowMultiple && len(matchingHeadings) > 1 {
+			for _, heading := range matchingHeadings[1:] {
+				violations = append(violations, &Violation{
+					Name:         "file-structure",
+					RelativePath: file.RelativePath,
+					Message:      fmt.Sprintf("duplicated %s in file type %q", spec, fileTypeName),
+					Line:         file.AbsoluteBodyLine(heading.Line),
+				})
+			}
+		}
+
+		for _, heading := range matchingHeadings {
+			newViolations, err := checkFileHeadings(file, fileTypeName, heading.Line, spec.Children, heading.Children)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, newViolations...)
+		}
+	}
+
+	return violations, nil
+}
+
+// MaxNoteDepth implements the rule "max-note-depth".
+func MaxNoteDepth(file *ParsedFileOld, args []string) ([]*Violation, error) {
+	var violations []*Violation
+
+	if len(args) != 1 {
+		return nil, errors.New("only a single argument is required")
+	}
+	maxDepth, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("argument %s must be an integer", args[0])
+	}
+
+	// Typed notes enclosing the current note
+	var parents []*ParsedNoteOld
+
+	notes := ParseNotes(file.Body, file.Slug)
+	for _, note := range notes {
+		for len(parents) > 0 && parents[len(parents)-1].Level >= note.Level {
+			parents = parents[:len(parents)-1]
+		}
+
+		depth := len(parents) + 1
+		if depth > maxDepth {
+			violations = append(violations, &Violation{
+				Name:         "max-note-depth",
+				RelativePath: file.RelativePath,
+				Message:      fmt.Sprintf("note %q is nested too deeply (depth %d but max is %d)", note.Title, depth, maxDepth),
+				Line:         file.AbsoluteBodyLine(note.Line),
+			})
+		}
+
+		if note.Kind != KindFree {
+			parents = append(parents, note)
+		}
+	}
+
+	return violations, nil
+}
+
+// MaxLineLength implements the rule "max-line-length".
+func MaxLineLength(file *ParsedFileOld, args []string) ([]*Violation, error) {
+	var violations []*Violation
+
+	if len(args) != 1 {
+		return nil, errors.New("only a single argument is required")
+	}
+	maxLength, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("argument %s must be an integer", args[0])
+	}
+
+	// Long lines are common in code blocks and tables
+	lines := strings.Split(markdown.CleanCodeBlocks(file.Body), "\n")
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "|") {
+			continue
+		}
+		length := utf8.RuneCountInString(line)
+		if length > maxLength {
+			violations = append(violations, &Violation{
+				Name:         "max-line-length",
+				RelativePath: file.RelativePath,
+				Message:      fmt.Sprintf("line is too long (%d characters but max is %d)", length, maxLength),
+				Line:         file.AbsoluteBodyLine(i + 1),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// NoTrailingWhitespace implements the rule "no-trailing-whitespace".
+func NoTrailingWhitespace(file *ParsedFileOld, args []string) ([]*Violation, error) {
+	var violations []*Violation
+
+	allowLineBreak := false
+	for _, arg := range args {
+		if arg != "allow-line-break" {
+			return nil, fmt.Errorf("unknown argument %s", arg)
+		}
+		allowLineBreak = true
+	}
+
+	lines := strings.Split(file.Body, "\n")
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		trimmedLine := strings.TrimRight(line, " \t")
+		if trimmedLine == line {
+			continue
+		}
+		// Two trailing spaces force a line break in Markdown
+		if allowLineBreak && !text.IsBlank(trimmedLine) && trimmedLine+"  " == line {
+			continue
+		}
+		violations = append(violations, &Violation{
+			Name:         "no-trailing-whitespace",
+			RelativePath: file.RelativePath,
+			Message:      "trailing whitespace",
+			Line:         file.AbsoluteBodyLine(i + 1),
+		})
+	}
+
+	return violations, nil
+}
+
+// RequireFlashcardSeparator implements the rule "require-flashcard-separator".
+func RequireFlashcardSeparator(file *ParsedFileOld, args []string) ([]*Violation, error) {
+	var violations []*Violation
+
+	notes := ParseNotes(file.Body, file.Slug)
+	for _, note := range notes {
+		if note.Kind != KindFlashcard {
+			continue
+		}
+
+		// Ignore separators inside code blocks
+		body := markdown.CleanCodeBlocks(note.Body)
+		countSeparators := 0
+		for _, line := range strings.Split(body, "\n") {
+			if line == "---" {
+				countSeparators++
+			}
+		}
+
+		if countSeparators == 1 {
+			continue
+		}
+		message := fmt.Sprintf("missing separator \"---\" between front and back in flashcard %q", note.Title)
+		if countSeparators > 1 {
+			message = fmt.Sprintf("too many separators \"---\" in flashcard %q (found %d, expected 1)", note.Title, countSeparators)
+		}
+		violations = append(violations, &Violation{
+			Name:         "require-flashcard-separator",
+			RelativePath: file.RelativePath,
+			Message:      message,
+			Line:         file.AbsoluteBodyLine(note.Line),
+		})
+	}
+
+	return violations, nil
+}
+
 // NoDanglingMedia implements the rule "no-dangling-media".
 func NoDanglingMedia(file *ParsedFileOld, args []string) ([]*Violation, error) {
 	var violations []*Violation
@@ -463,12 +1016,99 @@ func NoDanglingMedia(file *ParsedFileOld, args []string) ([]*Violation, error) {
 	return violations, nil
 }
 
+/* Keep an inventory of all referenced medias to find duplicates easily. */
+var mediasInventory map[string][]string // hash => relative paths of medias sharing this content
+var mediasInventoryOnce resync.Once     // Build the inventory on first occurrence only.
+var mediasInventoryErr error            // Error while building the inventory if any.
+
+// loadMediasInventory builds the medias inventory on first call.
+func loadMediasInventory() error {
+	mediasInventoryOnce.Do(buildMediasInventory)
+	return mediasInventoryErr
+}
+
+func buildMediasInventory() {
+	mediasInventory = make(map[string][]string)
+	mediasInventoryErr = nil
+	paths := []string{CurrentConfig().RootDirectory}
+	err := CurrentRepository().walk(paths, func(path string, stat fs.FileInfo) error {
+		relativePath, err := CurrentRepository().GetFileRelativePath(path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, media := range ParseMedias(relativePath, string(data)) {
+			hash, err := helpers.HashFromFile(media.AbsolutePath)
+			if err != nil {
+				// Dangling medias are reported by the rule "no-dangling-media"
+				continue
+			}
+			if !slices.Contains(mediasInventory[hash], media.RelativePath) {
+				mediasInventory[hash] = append(mediasInventory[hash], media.RelativePath)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		mediasInventoryErr = fmt.Errorf("unable to build medias inventory: %w", err)
+	}
+}
+
+// NoDuplicateMedia implements the rule "no-duplicate-media".
+func NoDuplicateMedia(file *ParsedFileOld, args []string) ([]*Violation, error) {
+	if err := loadMediasInventory(); err != nil {
+		return nil, err
+	}
+
+	var violations []*Violation
+
+	medias := ParseMedias(file.RelativePath, file.Body)
+	checked := make(map[string]bool) // A media referenced multiple times is reported once
+	for _, media := range medias {
+		if checked[media.RelativePath] {
+			continue
+		}
+		checked[media.RelativePath] = true
+
+		hash, err := helpers.HashFromFile(media.AbsolutePath)
+		if err != nil {
+			continue
+		}
+		for _, otherRelativePath := range mediasInventory[hash] {
+			if otherRelativePath == media.RelativePath {
+				continue
+			}
+			violations = append(violations, &Violation{
+				Name:         "no-duplicate-media",
+				RelativePath: file.RelativePath,
+				Message:      fmt.Sprintf("media %s has the same content as %s (consider using a single file)", media.RelativePath, otherRelativePath),
+				Line:         file.AbsoluteBodyLine(media.Line),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
 /* Keep an inventory of all Markdown sections to determine easily if a wikilink is dead.  */
 var sectionsInventory map[string][]string // path without extension => section titles (without the leading characters)
 var sectionsInventoryOnce resync.Once     // Build the inventory on first occurrence only.
+var sectionsInventoryErr error            // Error while building the inventory if any.
+
+// loadSectionsInventory builds the sections inventory on first call.
+func loadSectionsInventory() error {
+	sectionsInventoryOnce.Do(buildSectionsInventory)
+	return sectionsInventoryErr
+}
 
 func buildSectionsInventory() {
 	sectionsInventory = make(map[string][]string)
+	sectionsInventoryErr = nil
 	paths := []string{CurrentConfig().RootDirectory}
 	err := CurrentRepository().walk(paths, func(path string, stat fs.FileInfo) error {
 		relativePath, err := CurrentRepository().GetFileRelativePath(path)
@@ -496,13 +1136,15 @@ func buildSectionsInventory() {
 		return nil
 	})
 	if err != nil {
-		log.Fatalf("Unable to build sections inventory: %v", err)
+		sectionsInventoryErr = fmt.Errorf("unable to build sections inventory: %w", err)
 	}
 }
 
 // NoDeadWikilink implements the rule "no-dead-wikilink".
 func NoDeadWikilink(file *ParsedFileOld, args []string) ([]*Violation, error) {
-	sectionsInventoryOnce.Do(buildSectionsInventory)
+	if err := loadSectionsInventory(); err != nil {
+		return nil, err
+	}
 
 	var violations []*Violation
 
@@ -565,7 +1207,9 @@ func NoExtensionWikilink(file *ParsedFileOld, args []string) ([]*Violation, erro
 
 // NoAmbiguousWikilink implements the rule "no-ambiguous-wikilink"
 func NoAmbiguousWikilink(file *ParsedFileOld, args []string) ([]*Violation, error) {
-	sectionsInventoryOnce.Do(buildSectionsInventory)
+	if err := loadSectionsInventory(); err != nil {
+		return nil, err
+	}
 
 	var violations []*Violation
 
@@ -660,6 +1304,9 @@ func RequireQuoteTag(file *ParsedFileOld, args []string) ([]*Violation, error) {
 func CheckAttribute(file *ParsedFileOld, args []string) ([]*Violation, error) {
 	var violations []*Violation
 
+	// Computed attributes set on the file are reported once
+	reportedFileAttributes := make(map[string]bool)
+
 	notes := ParseNotes(file.Body, file.Slug)
 	for _, note := range notes {
 
@@ -669,6 +1316,30 @@ func CheckAttribute(file *ParsedFileOld, args []string) ([]*Violation, error) {
 			allowedNames := []string{definition.Name}
 			allowedNames = append(allowedNames, definition.Aliases...)
 
+			if definition.Value != "" {
+				// Computed attributes cannot be set manually
+				for _, name := range allowedNames {
+					if _, presentOnFile := file.FileAttributes[name]; presentOnFile && !reportedFileAttributes[name] {
+						reportedFileAttributes[name] = true
+						violations = append(violations, &Violation{
+							Name:         "check-attribute",
+							RelativePath: file.RelativePath,
+							Message:      fmt.Sprintf("attribute %q in file %q is computed and cannot be set", name, file.RelativePath),
+							Line:         text.LineNumber(file.Content(), name+":"),
+						})
+					}
+					if _, presentOnNote := note.NoteAttributes[name]; presentOnNote {
+						violations = append(violations, &Violation{
+							Name:         "check-attribute",
+							RelativePath: file.RelativePath,
+							Message:      fmt.Sprintf("attribute %q in note %q in file %q is computed and cannot be set", name, note.Title, file.RelativePath),
+							Line:         file.BodyLine + note.Line - 1 + text.LineNumber(note.Body, "@"+name),
+						})
+					}
+				}
+				continue
+			}
+
 			found := false
 
 			for _, name := range allowedNames {
@@ -846,13 +1517,7 @@ func (f *ParsedFileOld) Lint(ruleNames []string) ([]*Violation, error) {
 		}
 
 		// Check path restrictions
-		matchAllIncludes := true
-		for _, include := range configRule.Includes {
-			if !include.Match(f.RelativePath) {
-				matchAllIncludes = false
-			}
-		}
-		if !matchAllIncludes {
+		if !configRule.Includes.Include(f.RelativePath) {
 			continue
 		}
 
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -86,6 +89,55 @@ func TestGetSchemaAttributes(t *testing.T) {
 	}, definitions)
 }
 
+func TestGetSchemaAttributesDeterministic(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
+
+	alpha := ConfigLintSchema{
+		Name: "Alpha",
+		Path: "references/",
+		Attributes: []*ConfigLintSchemaAttribute{
+			{Name: "author", Type: "string", Required: BoolPointer(true)},
+		},
+	}
+	beta := ConfigLintSchema{
+		Name: "Beta",
+		Path: "references/",
+		Attributes: []*ConfigLintSchemaAttribute{
+			{Name: "author", Type: "array", Required: BoolPointer(false)},
+		},
+	}
+	generic := ConfigLintSchema{
+		Name: "Generic",
+		Attributes: []*ConfigLintSchemaAttribute{
+			{Name: "author", Type: "object"},
+		},
+	}
+
+	for _, schemas := range [][]ConfigLintSchema{
+		{generic, alpha, beta},
+		{beta, generic, alpha},
+		{beta, alpha, generic},
+	} {
+		CurrentConfig().LintFile.Schemas = schemas
+		for i := 0; i < 10; i++ {
+			definitions := GetSchemaAttributes("references/book.md", KindNote)
+			require.Len(t, definitions, 1)
+			// Equally-specific schemas are ordered by name
+			assert.Equal(t, "string", definitions[0].Type)
+		}
+	}
+
+	// Same name, the first declared schema wins
+	alphaBis := alpha
+	alphaBis.Attributes = []*ConfigLintSchemaAttribute{
+		{Name: "author", Type: "array"},
+	}
+	CurrentConfig().LintFile.Schemas = []ConfigLintSchema{alphaBis, alpha}
+	definitions := GetSchemaAttributes("references/book.md", KindNote)
+	require.Len(t, definitions, 1)
+	assert.Equal(t, "array", definitions[0].Type)
+}
+
 func TestNoDuplicateNoteTitle(t *testing.T) {
 	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
 
@@ -127,12 +179,14 @@ func TestNoDuplicateSlug(t *testing.T) {
 			RelativePath: "no-duplicate-slug/b.md",
 			Message:      `duplicated slug "b-note-1"`,
 			Line:         11,
+			Suggestion:   "`@slug: b-note-1-2`",
 		},
 		{
 			Name:         "no-duplicate-slug",
 			RelativePath: "no-duplicate-slug/b.md",
 			Message:      `duplicated slug "a-note-1"`,
 			Line:         23,
+			Suggestion:   "`@slug: a-note-1-2`",
 		},
 	}, violations)
 
@@ -186,6 +240,18 @@ func TestMinLinesBetweenNotes(t *testing.T) {
 			Line:         15,
 		},
 	}, violations)
+
+	// Restrict to a kind
+	violations, err = MinLinesBetweenNotes(file, []string{"2", "note"})
+	require.NoError(t, err)
+	assert.Len(t, violations, 2)
+	violations, err = MinLinesBetweenNotes(file, []string{"2", "flashcard"})
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// Invalid arguments
+	_, err = MinLinesBetweenNotes(file, []string{"2", "note", "flashcard"})
+	assert.Error(t, err)
 }
 
 func TestMaxLinesBetweenNotes(t *testing.T) {
@@ -210,6 +276,18 @@ func TestMaxLinesBetweenNotes(t *testing.T) {
 			Line:         16,
 		},
 	}, violations)
+
+	// Restrict to a kind
+	violations, err = MaxLinesBetweenNotes(file, []string{"2", "note"})
+	require.NoError(t, err)
+	assert.Len(t, violations, 2)
+	violations, err = MaxLinesBetweenNotes(file, []string{"2", "flashcard"})
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// Invalid arguments
+	_, err = MaxLinesBetweenNotes(file, []string{"2", "note", "flashcard"})
+	assert.Error(t, err)
 }
 
 func TestNoteTitleMatch(t *testing.T) {
@@ -293,6 +371,258 @@ func TestNoFreeNote(t *testing.T) {
 	}, violations)
 }
 
+func TestLintIncludes(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+	CurrentConfig().LintFile.Rules = []ConfigLintRule{
+		{
+			Name:     "no-free-note",
+			Includes: GlobPaths{"references/**", "projects/", "!references/drafts/**"},
+		},
+	}
+	content := "# Title\n\n## A free note\n\nNot allowed.\n"
+
+	var tests = []struct {
+		path       string
+		violations int
+	}{
+		{path: "references/go.md", violations: 1},
+		{path: "projects/go.md", violations: 1},
+		{path: "references/drafts/go.md", violations: 0},
+		{path: "archives/go.md", violations: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			result, err := CurrentRepository().LintReader(tt.path, strings.NewReader(content), nil)
+			require.NoError(t, err)
+			assert.Len(t, append(result.Errors, result.Warnings...), tt.violations)
+		})
+	}
+}
+
+func TestLintToSARIF(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+	CurrentConfig().LintFile.Rules = []ConfigLintRule{
+		{
+			Name: "no-duplicate-note-title",
+		},
+		{
+			Name:     "no-free-note",
+			Severity: "warning",
+		},
+	}
+	content := "# Title\n\n## A free note\n\nNot allowed.\n\n## Note: Name\n\nFirst\n\n## Note: Name\n\nSecond\n"
+	result, err := CurrentRepository().LintReader("go.md", strings.NewReader(content), nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = CurrentRepository().LintToSARIF(result, &buf)
+	require.NoError(t, err)
+
+	var report struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &report)
+	require.NoError(t, err)
+	assert.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs, 1)
+	run := report.Runs[0]
+	assert.Equal(t, "the-notewriter", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "no-duplicate-note-title", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "no-free-note", run.Tool.Driver.Rules[1].ID)
+
+	require.Len(t, run.Results, 2)
+	// Errors come first
+	assert.Equal(t, "no-duplicate-note-title", run.Results[0].RuleID)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "go.md", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "no-free-note", run.Results[1].RuleID)
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Equal(t, 3, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestConsistentHeadingLevels(t *testing.T) {
+	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
+
+	file, err := ParseFile(filepath.Join(root, "consistent-heading-levels.md"))
+	require.NoError(t, err)
+
+	violations, err := ConsistentHeadingLevels(file, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*Violation{
+		{
+			Name:         "consistent-heading-levels",
+			RelativePath: "consistent-heading-levels.md",
+			Message:      `heading "Sub-sub-note: Skipped" skips a level (expected level 4 but got 5)`,
+			Line:         7,
+		},
+		{
+			Name:         "consistent-heading-levels",
+			RelativePath: "consistent-heading-levels.md",
+			Message:      `heading "Note: Skipped" skips a level (expected level 3 but got 4)`,
+			Line:         15,
+		},
+	}, violations)
+}
+
+func TestFileStructure(t *testing.T) {
+	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
+
+	file, err := ParseFile(filepath.Join(root, "file-structure/weekly-meeting.md"))
+	require.NoError(t, err)
+
+	violations, err := FileStructure(file, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*Violation{
+		{
+			Name:         "file-structure",
+			RelativePath: "file-structure/weekly-meeting.md",
+			Message:      `duplicated heading matching "^Decisions$" in file type "Meeting"`,
+			Line:         12,
+		},
+		{
+			Name:         "file-structure",
+			RelativePath: "file-structure/weekly-meeting.md",
+			Message:      `missing required todo heading in file type "Meeting"`,
+			Line:         8,
+		},
+		{
+			Name:         "file-structure",
+			RelativePath: "file-structure/weekly-meeting.md",
+			Message:      `missing required heading matching "^Actions$" in file type "Meeting"`,
+			Line:         1,
+		},
+	}, violations)
+
+	// File types only apply to matching paths
+	file, err = ParseFile(filepath.Join(root, "max-note-depth.md"))
+	require.NoError(t, err)
+	violations, err = FileStructure(file, nil)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestMaxNoteDepth(t *testing.T) {
+	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
+
+	file, err := ParseFile(filepath.Join(root, "max-note-depth.md"))
+	require.NoError(t, err)
+
+	violations, err := MaxNoteDepth(file, []string{"2"})
+	require.NoError(t, err)
+	require.Equal(t, []*Violation{
+		{
+			Name:         "max-note-depth",
+			RelativePath: "max-note-depth.md",
+			Message:      `note "Note: Too Deep" is nested too deeply (depth 3 but max is 2)`,
+			Line:         11,
+		},
+	}, violations)
+
+	_, err = MaxNoteDepth(file, []string{"two"})
+	require.Error(t, err)
+}
+
+func TestMaxLineLength(t *testing.T) {
+	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
+
+	file, err := ParseFile(filepath.Join(root, "max-line-length.md"))
+	require.NoError(t, err)
+
+	violations, err := MaxLineLength(file, []string{"40"})
+	require.NoError(t, err)
+	require.Equal(t, []*Violation{
+		{
+			Name:         "max-line-length",
+			RelativePath: "max-line-length.md",
+			Message:      `line is too long (47 characters but max is 40)`,
+			Line:         5,
+		},
+	}, violations)
+
+	_, err = MaxLineLength(file, []string{"forty"})
+	require.Error(t, err)
+}
+
+func TestNoTrailingWhitespace(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	// Trailing whitespace is not stored in a golden file to not be removed by editors
+	content := "---\ntags: [go]\n---\n# Rule `no-trailing-whitespace`\n\n## Note: Whitespace\n\nTrailing space \nLine break  \nTrailing tab\t\n  \nValid\n"
+	file, err := ParseFileFromBytes("no-trailing-whitespace.md", []byte(content))
+	require.NoError(t, err)
+
+	violations, err := NoTrailingWhitespace(file, nil)
+	require.NoError(t, err)
+	var lines []int
+	for _, violation := range violations {
+		assert.Equal(t, "no-trailing-whitespace", violation.Name)
+		assert.Equal(t, "no-trailing-whitespace.md", violation.RelativePath)
+		lines = append(lines, violation.Line)
+	}
+	assert.Equal(t, []int{8, 9, 10, 11}, lines)
+
+	// Two trailing spaces are a Markdown line break
+	violations, err = NoTrailingWhitespace(file, []string{"allow-line-break"})
+	require.NoError(t, err)
+	lines = nil
+	for _, violation := range violations {
+		lines = append(lines, violation.Line)
+	}
+	assert.Equal(t, []int{8, 10, 11}, lines)
+
+	_, err = NoTrailingWhitespace(file, []string{"unknown"})
+	require.Error(t, err)
+}
+
+func TestRequireFlashcardSeparator(t *testing.T) {
+	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
+
+	file, err := ParseFile(filepath.Join(root, "require-flashcard-separator.md"))
+	require.NoError(t, err)
+
+	violations, err := RequireFlashcardSeparator(file, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*Violation{
+		{
+			Name:         "require-flashcard-separator",
+			RelativePath: "require-flashcard-separator.md",
+			Message:      `missing separator "---" between front and back in flashcard "Flashcard: Missing Separator"`,
+			Line:         11,
+		},
+		{
+			Name:         "require-flashcard-separator",
+			RelativePath: "require-flashcard-separator.md",
+			Message:      `too many separators "---" in flashcard "Flashcard: Too Many Separators" (found 2, expected 1)`,
+			Line:         17,
+		},
+	}, violations)
+}
+
 func TestNoDanglingMedia(t *testing.T) {
 	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
 
@@ -317,6 +647,34 @@ func TestNoDanglingMedia(t *testing.T) {
 	}, violations)
 }
 
+func TestNoDuplicateMedia(t *testing.T) {
+	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
+
+	file, err := ParseFile(filepath.Join(root, "no-duplicate-media.md"))
+	require.NoError(t, err)
+
+	violations, err := NoDuplicateMedia(file, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*Violation{
+		{
+			Name:         "no-duplicate-media",
+			RelativePath: "no-duplicate-media.md",
+			Message:      `media no-duplicate-media/pic.gif has the same content as no-duplicate-media/copy.gif (consider using a single file)`,
+			Line:         3,
+		},
+		{
+			Name:         "no-duplicate-media",
+			RelativePath: "no-duplicate-media.md",
+			Message:      `media no-duplicate-media/copy.gif has the same content as no-duplicate-media/pic.gif (consider using a single file)`,
+			Line:         6,
+		},
+	}, violations)
+
+	// Reported as warning by default
+	assert.Equal(t, "warning", CurrentConfig().LintFile.Severity("no-duplicate-media"))
+	assert.Equal(t, "error", CurrentConfig().LintFile.Severity("no-dangling-media"))
+}
+
 func TestNoDeadWikilink(t *testing.T) {
 	root := SetUpRepositoryFromGoldenDirNamed(t, "TestLint")
 
@@ -444,3 +802,42 @@ func TestCheckAttribute(t *testing.T) {
 		},
 	}, violations)
 }
+
+func TestLintThreshold(t *testing.T) {
+	violation := &Violation{Name: "no-dead-wikilink", RelativePath: "go.md", Line: 1}
+	clean := &LintResult{}
+	withWarnings := &LintResult{Warnings: []*Violation{violation, violation}}
+	withErrors := &LintResult{Errors: []*Violation{violation}}
+
+	var tests = []struct {
+		name        string      // name
+		failOn      string      // input
+		maxWarnings int         // input
+		result      *LintResult // input
+		failed      bool        // output
+	}{
+		{"Never fail by default", "", -1, withErrors, false},
+		{"Fail on errors", "error", -1, withErrors, true},
+		{"Ignore warnings when failing on errors", "error", -1, withWarnings, false},
+		{"Fail on warnings", "warning", -1, withWarnings, true},
+		{"Fail on errors when failing on warnings", "warning", -1, withErrors, true},
+		{"Max warnings not exceeded", "none", 2, withWarnings, false},
+		{"Max warnings exceeded", "none", 1, withWarnings, true},
+		{"No violations", "warning", 0, clean, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, err := NewLintThreshold(tt.failOn, tt.maxWarnings)
+			require.NoError(t, err)
+			err = threshold.Check(tt.result)
+			if tt.failed {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, err := NewLintThreshold("info", -1)
+	assert.Error(t, err)
+}
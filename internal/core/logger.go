@@ -1,7 +1,15 @@
 package core
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/julien-sobczak/the-notewriter/pkg/resync"
 )
@@ -15,12 +23,20 @@ var (
 type VerboseLevel int
 
 const (
-	VerboseOff VerboseLevel = iota
+	VerboseQuiet VerboseLevel = iota - 1 // Only fatal errors
+	VerboseOff
 	VerboseInfo
 	VerboseDebug
 	VerboseTrace
 )
 
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
 func CurrentLogger() *Logger {
 	loggerOnce.Do(func() {
 		loggerSingleton = NewLogger()
@@ -30,11 +46,15 @@ func CurrentLogger() *Logger {
 
 type Logger struct {
 	verbose VerboseLevel
+	format  LogFormat
+	out     io.Writer // Only used by structured logging
 }
 
 func NewLogger() *Logger {
 	return &Logger{
 		verbose: VerboseOff,
+		format:  LogFormatText,
+		out:     os.Stderr,
 	}
 }
 
@@ -44,6 +64,51 @@ func (l *Logger) SetVerboseLevel(level VerboseLevel) *Logger {
 	return l
 }
 
+// SetFormat overrides the default output format (text or json).
+func (l *Logger) SetFormat(format LogFormat) error {
+	if format != LogFormatText && format != LogFormatJSON {
+		return fmt.Errorf("unsupported log format %q", format)
+	}
+	l.format = format
+	return nil
+}
+
+// SetOutput overrides the destination of structured logs.
+func (l *Logger) SetOutput(w io.Writer) *Logger {
+	l.out = w
+	return l
+}
+
+// logEntry represents a structured log entry.
+type logEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"msg"`
+	File    string    `json:"file"`
+}
+
+func (l *Logger) print(level string, msg string) {
+	if l.format != LogFormatJSON {
+		log.Print(msg)
+		return
+	}
+	entry := logEntry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: strings.TrimSuffix(msg, "\n"),
+	}
+	// Skip print() and the public method
+	if _, file, line, ok := runtime.Caller(2); ok {
+		entry.File = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Print(msg)
+		return
+	}
+	fmt.Fprintln(l.out, string(data))
+}
+
 func (l *Logger) Fatal(v ...any) {
 	log.Fatalln(v...)
 }
@@ -52,41 +117,45 @@ func (l *Logger) Fatalf(format string, v ...any) {
 }
 
 func (l *Logger) Warn(v ...any) {
-	log.Println(v...)
+	if l.verbose > VerboseQuiet {
+		l.print("warn", fmt.Sprintln(v...))
+	}
 }
 func (l *Logger) Warnf(format string, v ...any) {
-	log.Printf(format, v...)
+	if l.verbose > VerboseQuiet {
+		l.print("warn", fmt.Sprintf(format, v...))
+	}
 }
 
 func (l *Logger) Info(v ...any) {
 	if l.verbose >= VerboseInfo {
-		log.Println(v...)
+		l.print("info", fmt.Sprintln(v...))
 	}
 }
 func (l *Logger) Infof(format string, v ...any) {
 	if l.verbose >= VerboseInfo {
-		log.Printf(format, v...)
+		l.print("info", fmt.Sprintf(format, v...))
 	}
 }
 
 func (l *Logger) Debug(v ...any) {
 	if l.verbose >= VerboseDebug {
-		log.Println(v...)
+		l.print("debug", fmt.Sprintln(v...))
 	}
 }
 func (l *Logger) Debugf(format string, v ...any) {
 	if l.verbose >= VerboseDebug {
-		log.Printf(format, v...)
+		l.print("debug", fmt.Sprintf(format, v...))
 	}
 }
 
 func (l *Logger) Trace(v ...any) {
 	if l.verbose >= VerboseTrace {
-		log.Println(v...)
+		l.print("trace", fmt.Sprintln(v...))
 	}
 }
 func (l *Logger) Tracef(format string, v ...any) {
 	if l.verbose >= VerboseTrace {
-		log.Printf(format, v...)
+		l.print("trace", fmt.Sprintf(format, v...))
 	}
 }
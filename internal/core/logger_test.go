@@ -0,0 +1,54 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+
+	t.Run("Levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewLogger().SetOutput(&buf)
+		require.NoError(t, logger.SetFormat(LogFormatJSON))
+
+		logger.Info("hidden")
+		logger.Warn("visible")
+		logger.SetVerboseLevel(VerboseDebug)
+		logger.Debugf("Processing %s...\n", "go.md")
+		logger.Trace("hidden")
+		logger.SetVerboseLevel(VerboseQuiet)
+		logger.Warnf("hidden")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"msg":"visible"`)
+		assert.Contains(t, lines[1], `"msg":"Processing go.md..."`)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewLogger().SetOutput(&buf).SetVerboseLevel(VerboseInfo)
+		require.NoError(t, logger.SetFormat(LogFormatJSON))
+
+		logger.Infof("Reprocessing note %s...", "go.md")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "info", entry["level"])
+		assert.Equal(t, "Reprocessing note go.md...", entry["msg"])
+		assert.Contains(t, entry["file"], "logger_test.go:")
+		assert.NotEmpty(t, entry["time"])
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		err := NewLogger().SetFormat("xml")
+		assert.ErrorContains(t, err, "unsupported log format")
+	})
+
+}
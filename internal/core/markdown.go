@@ -61,13 +61,19 @@ func ParseMarkdownFile(path string) (*MarkdownFile, error) {
 		return nil, err
 	}
 
+	// Convert alternative formats (ex: AsciiDoc) to Markdown
+	markdownAsBytes, err := FileParserFor(relativePath).Parse(contentAsBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	var rawFrontMatter bytes.Buffer
 	var rawBody bytes.Buffer
 	frontMatterStarted := false
 	frontMatterEnded := false
 	bodyStarted := false
 	bodyLine := 0
-	for i, line := range strings.Split(strings.TrimSuffix(string(contentAsBytes), "\n"), "\n") {
+	for i, line := range strings.Split(strings.TrimSuffix(string(markdownAsBytes), "\n"), "\n") {
 		if strings.HasPrefix(line, "---") {
 			if bodyStarted {
 				// Flashcard Front/Back line separator
@@ -89,6 +89,10 @@ type Media struct {
 
 // DetectMediaKind returns the media kind based on a file path.
 func DetectMediaKind(filename string) MediaKind {
+	// Kinds declared in .nt/config take precedence
+	if kind, ok := CurrentConfig().ConfigFile.MediaKindFor(filename); ok {
+		return kind
+	}
 	ext := filepath.Ext(filename)
 	for _, audioExt := range AudioExtensions {
 		if strings.EqualFold(ext, audioExt) {
@@ -142,6 +146,12 @@ func (m *Media) update() {
 	stat, err := os.Stat(abspath)
 	dangling := errors.Is(err, os.ErrNotExist)
 
+	// Kinds declared in .nt/config may have changed
+	if kind := DetectMediaKind(m.RelativePath); m.MediaKind != kind {
+		m.MediaKind = kind
+		m.stale = true
+	}
+
 	// Special case when file didn't exist or no longer exist
 	if m.Dangling != dangling {
 		m.Dangling = dangling
@@ -173,7 +183,7 @@ func (m *Media) update() {
 		m.stale = true
 	}
 	mTime := stat.ModTime()
-	if m.MTime != mTime {
+	if !m.MTime.Equal(mTime) {
 		m.MTime = mTime
 		m.stale = true
 	}
@@ -192,7 +202,7 @@ func (m *Media) UpdateBlobs() {
 	src := CurrentRepository().GetAbsolutePath(m.RelativePath)
 
 	tmpDir := CurrentConfig().TempDir()
-	converter := CurrentConfig().Converter()
+	converter := CurrentConfig().ConverterFor(m.MediaKind)
 
 	// Old blobs will be gc later if not referenced.
 	m.BlobRefs = nil
@@ -209,6 +219,14 @@ func (m *Media) UpdateBlobs() {
 		m.BlobRefs = append(m.BlobRefs, blob)
 
 	case KindPicture:
+		if _, ok := converter.(*medias.SvgoConverter); ok {
+			// Vector pictures are only optimized
+			dest := filepath.Join(tmpDir, filepath.Base(src)+".original.svg")
+			toAVIF(converter, src, dest, medias.OriginalSize())
+			m.BlobRefs = append(m.BlobRefs, MustWriteBlob(dest, []string{"original", "lossless"}))
+			break
+		}
+
 		// Convert to AVIF (widely supported in desktop and mobiles as of 2023)
 
 		dimensions, _ := medias.ReadImageDimensions(src)
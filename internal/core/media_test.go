@@ -12,6 +12,13 @@ import (
 )
 
 func TestDetectMediaKind(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+	CurrentConfig().ConfigFile.Medias.Kinds = map[string]string{
+		"mkv":   "video",
+		".HEIC": "picture",
+		"wav":   "document",
+	}
+
 	var tests = []struct {
 		name     string    // name
 		filename string    // input
@@ -42,6 +49,21 @@ func TestDetectMediaKind(t *testing.T) {
 			filename: "case.PNG",
 			kind:     KindPicture,
 		},
+		{
+			name:     "Custom extension",
+			filename: "movie.mkv",
+			kind:     KindVideo,
+		},
+		{
+			name:     "Custom extension with dot",
+			filename: "photo.heic",
+			kind:     KindPicture,
+		},
+		{
+			name:     "Overridden extension",
+			filename: "podcast.wav",
+			kind:     KindDocument,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/markdown"
+	"github.com/julien-sobczak/the-notewriter/pkg/text"
+	godiffpatch "github.com/sourcegraph/go-diff-patch"
+)
+
+// MergeNotes appends the content of the source note to the target note and removes the source note.
+// Wikilinks pointing to the source note are updated to point to the target note.
+// The patch of modified files is returned. Files are not written when dryRun is true,
+// otherwise modified files are added like with Add.
+func (r *Repository) MergeNotes(targetWikilink, sourceWikilink string, dryRun bool) (string, error) {
+	target, err := r.ResolveNote(targetWikilink)
+	if err != nil {
+		return "", err
+	}
+	if target == nil {
+		return "", fmt.Errorf("no note found for %q", targetWikilink)
+	}
+	source, err := r.ResolveNote(sourceWikilink)
+	if err != nil {
+		return "", err
+	}
+	if source == nil {
+		return "", fmt.Errorf("no note found for %q", sourceWikilink)
+	}
+	if source.OID == target.OID {
+		return "", errors.New("cannot merge a note with itself")
+	}
+
+	// Original and new file contents by relative path
+	originalContents := make(map[string]string)
+	newContents := make(map[string]string)
+	readFile := func(relativePath string) (string, error) {
+		if content, ok := newContents[relativePath]; ok {
+			return content, nil
+		}
+		content, err := os.ReadFile(r.GetAbsolutePath(relativePath))
+		if err != nil {
+			return "", err
+		}
+		originalContents[relativePath] = string(content)
+		return string(content), nil
+	}
+
+	// Remove the source note
+	content, err := readFile(source.RelativePath)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(content, "\n")
+	sourceStart, sourceEnd, err := noteSectionRange(lines, source)
+	if err != nil {
+		return "", err
+	}
+	targetLine := target.Line
+	if target.RelativePath == source.RelativePath {
+		if targetLine-1 > sourceStart && targetLine-1 < sourceEnd {
+			return "", fmt.Errorf("cannot merge note %q into one of its subnotes", source.Title)
+		}
+		if targetLine-1 >= sourceEnd {
+			targetLine -= sourceEnd - sourceStart
+		}
+	}
+	body := trimBlankLines(lines[sourceStart+1 : sourceEnd])
+	var newLines []string
+	newLines = append(newLines, lines[:sourceStart]...)
+	newLines = append(newLines, lines[sourceEnd:]...)
+	newContents[source.RelativePath] = strings.Join(newLines, "\n")
+
+	// Append the source content to the target note
+	content, err = readFile(target.RelativePath)
+	if err != nil {
+		return "", err
+	}
+	lines = strings.Split(content, "\n")
+	targetStart, targetEnd, err := noteSectionRange(lines, &Note{Wikilink: target.Wikilink, Line: targetLine})
+	if err != nil {
+		return "", err
+	}
+	last := targetEnd - 1
+	for last > targetStart && text.IsBlank(lines[last]) {
+		last--
+	}
+	newLines = nil
+	newLines = append(newLines, lines[:last+1]...)
+	if len(body) > 0 {
+		newLines = append(newLines, "")
+		newLines = append(newLines, body...)
+	}
+	newLines = append(newLines, lines[last+1:]...)
+	newContents[target.RelativePath] = strings.Join(newLines, "\n")
+
+	// Update wikilinks in all files
+	err = r.walk([]string{CurrentConfig().RootDirectory}, func(path string, stat fs.FileInfo) error {
+		relativePath, err := r.GetFileRelativePath(path)
+		if err != nil {
+			return err
+		}
+		content, err := readFile(relativePath)
+		if err != nil {
+			return err
+		}
+		newContent := replaceNoteWikilinks(content, relativePath, source, target)
+		if newContent != content {
+			newContents[relativePath] = newContent
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var relativePaths []string
+	for relativePath := range newContents {
+		relativePaths = append(relativePaths, relativePath)
+	}
+	sort.Strings(relativePaths)
+
+	var patch strings.Builder
+	for _, relativePath := range relativePaths {
+		patch.WriteString(godiffpatch.GeneratePatch(relativePath, originalContents[relativePath], newContents[relativePath]))
+	}
+	if dryRun {
+		return patch.String(), nil
+	}
+
+	for _, relativePath := range relativePaths {
+		absolutePath := r.GetAbsolutePath(relativePath)
+		stat, err := os.Stat(absolutePath)
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(absolutePath, []byte(newContents[relativePath]), stat.Mode()); err != nil {
+			return "", err
+		}
+	}
+	return patch.String(), r.Add(relativePaths...)
+}
+
+// noteSectionRange returns the index of the heading line of a note and the index of the first line after its section.
+func noteSectionRange(lines []string, note *Note) (int, int, error) {
+	_, title, _ := strings.Cut(note.Wikilink, "#")
+	start := note.Line - 1
+	if start < 0 || start >= len(lines) {
+		return 0, 0, fmt.Errorf("note %q not found at line %d (run nt add first)", title, note.Line)
+	}
+	ok, heading, level := markdown.IsHeading(lines[start])
+	if !ok || strings.TrimSpace(heading) != title {
+		return 0, 0, fmt.Errorf("note %q not found at line %d (run nt add first)", title, note.Line)
+	}
+
+	end := len(lines)
+	insideCodeBlock := false
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "```") {
+			insideCodeBlock = !insideCodeBlock
+			continue
+		}
+		if insideCodeBlock {
+			continue
+		}
+		if ok, _, headingLevel := markdown.IsHeading(lines[i]); ok && headingLevel <= level {
+			end = i
+			break
+		}
+	}
+	return start, end, nil
+}
+
+// trimBlankLines removes leading and trailing blank lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && text.IsBlank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && text.IsBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// replaceNoteWikilinks rewrites the wikilinks pointing to the source note to point to the target note.
+func replaceNoteWikilinks(content string, relativePath string, source, target *Note) string {
+	sourceFileLink, sourceSection, _ := strings.Cut(source.Wikilink, "#")
+	targetFileLink, targetSection, _ := strings.Cut(target.Wikilink, "#")
+	fileLink := text.TrimExtension(relativePath)
+
+	return regexWikilink.ReplaceAllStringFunc(content, func(match string) string {
+		wikilink, err := NewWikilink(match)
+		if err != nil || wikilink.Section() != sourceSection {
+			return match
+		}
+		path := text.TrimExtension(wikilink.Path())
+		if wikilink.Internal() {
+			path = fileLink
+		}
+		if path != sourceFileLink && !strings.HasSuffix(sourceFileLink, "/"+path) {
+			return match
+		}
+
+		newLink := targetFileLink + "#" + targetSection
+		if wikilink.Internal() && fileLink == targetFileLink {
+			newLink = "#" + targetSection
+		}
+		if wikilink.Piped() {
+			return "[[" + newLink + "|" + wikilink.Text + "]]"
+		}
+		return "[[" + newLink + "]]"
+	})
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeNotes(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+
+	MustWriteFile(t, "go.md", `# Go
+
+## Note: History
+
+Go was designed at Google in 2007.
+
+## Note: Release
+
+Go 1.0 was released in 2012.
+
+See [[#Note: History]].
+
+## Note: Other
+
+Nothing to merge.
+`)
+	MustWriteFile(t, "links.md", `# Links
+
+## Note: Links
+
+* [[go#Note: Release|Go 1.0]]
+* [[go#Note: Other]]
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	readFile := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		require.NoError(t, err)
+		return string(content)
+	}
+	originalGo := readFile("go.md")
+	originalLinks := readFile("links.md")
+
+	// Dry run
+	patch, err := CurrentRepository().MergeNotes("go#Note: History", "go#Note: Release", true)
+	require.NoError(t, err)
+	assert.Contains(t, patch, "-## Note: Release")
+	assert.Contains(t, patch, "+* [[go#Note: History|Go 1.0]]")
+	assert.Equal(t, originalGo, readFile("go.md"))
+	assert.Equal(t, originalLinks, readFile("links.md"))
+
+	// Merge
+	_, err = CurrentRepository().MergeNotes("go#Note: History", "go#Note: Release", false)
+	require.NoError(t, err)
+	assert.Equal(t, `# Go
+
+## Note: History
+
+Go was designed at Google in 2007.
+
+Go 1.0 was released in 2012.
+
+See [[#Note: History]].
+
+## Note: Other
+
+Nothing to merge.
+`, readFile("go.md"))
+	assert.Equal(t, `# Links
+
+## Note: Links
+
+* [[go#Note: History|Go 1.0]]
+* [[go#Note: Other]]
+`, readFile("links.md"))
+
+	// Changes are staged
+	note, err := CurrentRepository().FindNoteByWikilink("go#Note: Release")
+	require.NoError(t, err)
+	assert.Nil(t, note)
+	note = MustFindNoteByPathAndTitle(t, "go.md", "Note: History")
+	assert.Contains(t, note.ContentRaw, "Go 1.0 was released in 2012.")
+
+	// Invalid merges
+	_, err = CurrentRepository().MergeNotes("go#Note: History", "go#Note: History", false)
+	assert.Error(t, err)
+	_, err = CurrentRepository().MergeNotes("go#Note: History", "go#Note: Unknown", false)
+	assert.Error(t, err)
+}
@@ -3,12 +3,17 @@ package core
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"io"
 	"log"
+	"math/rand"
+	"net/url"
 	"reflect"
 	"regexp"
 	"regexp/syntax"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -39,6 +44,7 @@ const (
 	KindTodo       NoteKind = "todo"
 	KindArtwork    NoteKind = "artwork"
 	KindSnippet    NoteKind = "snippet"
+	KindChecklist  NoteKind = "checklist"
 	// Edit website/docs/guides/notes.md when adding new kinds
 )
 
@@ -108,6 +114,13 @@ type Note struct {
 	CommentHTML     string `yaml:"comment_html,omitempty"`
 	CommentText     string `yaml:"comment_text,omitempty"`
 
+	// Number of checked and total task list items (ex: "- [x] Passport")
+	ItemsDone  int `yaml:"items_done,omitempty"`
+	ItemsTotal int `yaml:"items_total,omitempty"`
+
+	// Number of words in the content (Markdown syntax excluded)
+	WordCount int `yaml:"word_count,omitempty"`
+
 	// Timestamps to track changes
 	CreatedAt     time.Time `yaml:"created_at"`
 	UpdatedAt     time.Time `yaml:"updated_at"`
@@ -116,12 +129,18 @@ type Note struct {
 
 	new   bool
 	stale bool
+	moved bool // Only the line changed
 }
 
 // NewOrExistingNote loads and updates an existing note or creates a new one if new.
-func NewOrExistingNote(f *File, parent *Note, parsedNote *ParsedNoteOld) *Note {
+// The other notes parsed in the same file are used to find the existing note.
+func NewOrExistingNote(f *File, parent *Note, parsedNote *ParsedNoteOld, parsedNotes []*ParsedNoteOld) *Note {
 	// Try to find an existing note (instead of recreating it from scratch after every change)
-	note, _ := CurrentRepository().FindMatchingNote(f.RelativePath, parsedNote)
+	note, _ := CurrentRepository().FindMatchingNote(f.RelativePath, parsedNote, parsedNotes)
+	if note == nil && f.renamedFrom != "" {
+		// Notes are still saved under the previous path of a renamed file
+		note, _ = CurrentRepository().FindMatchingNote(f.renamedFrom, parsedNote, parsedNotes)
+	}
 	if note != nil {
 		note.update(f, parent, parsedNote)
 		return note
@@ -297,6 +316,14 @@ func (n *Note) Relations() []*Relation {
 		source := n.GetAttribute("source").(string) // Enforced by linter
 		if MatchWikilink(source) {
 			addWikilink(source, "references")
+		} else if isExternalURL(source) {
+			relations = append(relations, &Relation{
+				SourceOID:  n.OID,
+				SourceKind: "note",
+				TargetOID:  source,
+				TargetKind: "url",
+				Type:       "external_source",
+			})
 		}
 	}
 
@@ -327,6 +354,12 @@ func (n *Note) Relations() []*Relation {
 	return relations
 }
 
+// isExternalURL returns true for absolute HTTP(S) URLs.
+func isExternalURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 func (n Note) String() string {
 	return fmt.Sprintf("note %q [%s]", n.Title, n.OID)
 }
@@ -335,7 +368,7 @@ func (n Note) String() string {
 
 func (n *Note) update(f *File, parent *Note, parsedNote *ParsedNoteOld) {
 	// Set basic properties
-	if n.FileOID != f.OID {
+	if n.FileOID != f.OID || n.RelativePath != f.RelativePath {
 		n.FileOID = f.OID
 		n.File = f
 		n.RelativePath = f.RelativePath
@@ -368,10 +401,8 @@ func (n *Note) update(f *File, parent *Note, parsedNote *ParsedNoteOld) {
 	}
 
 	newLine := f.AbsoluteBodyLine(parsedNote.Line)
-	if n.Line != newLine {
-		n.Line = newLine
-		n.stale = true
-	}
+	lineChanged := n.Line != newLine
+	n.Line = newLine
 
 	// Set dynamic properties
 	n.updateLongTitle()              // Require the file and optional parent
@@ -380,6 +411,10 @@ func (n *Note) update(f *File, parent *Note, parsedNote *ParsedNoteOld) {
 
 	if n.stale {
 		n.UpdatedAt = clock.Now()
+	} else if lineChanged {
+		// Save the new line but a note moving in its file is not considered as updated
+		n.stale = true
+		n.moved = true
 	}
 }
 
@@ -396,6 +431,12 @@ func (n *Note) Updated() bool {
 /* Parsing */
 
 func (n *Note) parseContentRaw() (mdTitle, htmlTitle, txtTitle string, mdContent, htmlContent, txtContent string, mdComment, htmlComment, txtComment string) {
+	// Replace local-specific links by generic OID links
+	return n.parseContentRawWithMedias(n.ReplaceMediasByOIDLinks)
+}
+
+// parseContentRawWithMedias is similar to parseContentRaw but uses the given function to rewrite media links.
+func (n *Note) parseContentRawWithMedias(replaceMedias func(md string) string) (mdTitle, htmlTitle, txtTitle string, mdContent, htmlContent, txtContent string, mdComment, htmlComment, txtComment string) {
 	// Always remove block tags and attributes in all formats
 	content := StripBlockTagsAndAttributes(n.ContentRaw)
 
@@ -407,8 +448,7 @@ func (n *Note) parseContentRaw() (mdTitle, htmlTitle, txtTitle string, mdContent
 	// Extract optional personal comment
 	content, comment := markdown.StripComment(content)
 
-	// Replace local-specific links by generic OID links
-	content = n.ReplaceMediasByOIDLinks(content)
+	content = replaceMedias(content)
 
 	if comment != "" {
 		mdComment = strings.TrimSpace(comment)
@@ -438,10 +478,15 @@ func (n *Note) parseContentRaw() (mdTitle, htmlTitle, txtTitle string, mdContent
 		//   > talking and begin doing.
 		//   > — Walt Disney
 
+		source := n.GetAttributeString("source", "")
+		author := attribution
 		if attribution == "" {
 			attribution = n.GetAttributeString("name", n.GetAttributeString("author", ""))
+			author = attribution
+		} else if name, title := markdown.ParseAttribution(attribution); title != "" {
+			// Ex: — J.R.R. Tolkien, _The Fellowship of the Ring_
+			author, source = name, title
 		}
-		source := n.GetAttributeString("source", "")
 		if strings.Contains(source, "[[") {
 			// Ignore source containing wikilink.
 			// Ideally, we would retrieve the correspond note to retrieve its title.
@@ -468,14 +513,14 @@ func (n *Note) parseContentRaw() (mdTitle, htmlTitle, txtTitle string, mdContent
 		%s
 	</blockquote>
 	<figcaption>— %s</figcaption>
-</figure>`, markdown.ToHTML(quote), markdown.ToInlineHTML(attribution))
+</figure>`, markdown.ToHTML(quote), markdown.ToInlineHTML(author))
 		} else {
 			htmlContent += fmt.Sprintf(`<figure>
 	<blockquote>
 		%s
 	</blockquote>
 	<figcaption>— %s <cite>%s</cite></figcaption>
-</figure>`, markdown.ToHTML(quote), markdown.ToInlineHTML(attribution), markdown.ToInlineHTML(source))
+</figure>`, markdown.ToHTML(quote), markdown.ToInlineHTML(author), markdown.ToInlineHTML(source))
 		}
 
 		mdContent = strings.TrimSpace(mdContent)
@@ -537,6 +582,28 @@ func (n *Note) parseContentRaw() (mdTitle, htmlTitle, txtTitle string, mdContent
 
 // ReplaceMediasByOIDLinks replaces all non-dangling links by a OID fake link.
 func (n *Note) ReplaceMediasByOIDLinks(md string) string {
+	return n.replaceMedias(md, func(media *Media) string {
+		if media == nil {
+			// Use a 404 image
+			return "oid:" + missingMediaOID
+		}
+		return "oid:" + media.OID
+	})
+}
+
+// ReplaceMediasByURL replaces all non-dangling links by the URL of their blob under the given base URL
+// (ex: https://example.com/objects/4a/4a2b...). Dangling links use the 404 image.
+func (n *Note) ReplaceMediasByURL(md string, baseURL string) string {
+	return n.replaceMedias(md, func(media *Media) string {
+		if media == nil {
+			return MissingMediaURL(baseURL)
+		}
+		return MediaURL(baseURL, media)
+	})
+}
+
+// replaceMedias replaces the links of all medias by the result of fn (called with nil for dangling links).
+func (n *Note) replaceMedias(md string, fn func(media *Media) string) string {
 	regexMedias := regexp.MustCompile(`!\[.*?\]\((\S*?)(?:\s+"(.*?)")?\)`)
 
 	var result strings.Builder
@@ -544,33 +611,22 @@ func (n *Note) ReplaceMediasByOIDLinks(md string) string {
 	matches := regexMedias.FindAllStringSubmatchIndex(md, -1)
 	for _, match := range matches {
 		result.WriteString(md[prevIndex:match[2]])
+		prevIndex = match[3]
 
 		link := md[match[2]:match[3]]
 		relativePath, err := CurrentRepository().GetNoteRelativePath(n.GetFile().RelativePath, link)
 		if err != nil {
-			// Use a 404 image
-			result.WriteString("oid:" + missingMediaOID)
-			prevIndex = match[3]
+			result.WriteString(fn(nil))
 			continue
 		}
 
 		media, err := CurrentRepository().FindMediaByRelativePath(relativePath)
-		if err != nil || media == nil {
-			// Use a 404 image
-			result.WriteString("oid:" + missingMediaOID)
-			prevIndex = match[3]
+		if err != nil || media == nil || media.Dangling {
+			result.WriteString(fn(nil))
 			continue
 		}
 
-		if media.Dangling {
-			// Use a 404 image
-			result.WriteString("oid:" + missingMediaOID)
-			prevIndex = match[3]
-			continue
-		}
-
-		result.WriteString("oid:" + media.OID)
-		prevIndex = match[3]
+		result.WriteString(fn(media))
 	}
 	// Add remaining text
 	result.WriteString(md[prevIndex:])
@@ -578,16 +634,67 @@ func (n *Note) ReplaceMediasByOIDLinks(md string) string {
 	return result.String()
 }
 
+// MediaURL returns the URL of the main blob of a media under the given base URL.
+// The original blob is preferred when present.
+func MediaURL(baseURL string, media *Media) string {
+	if len(media.BlobRefs) == 0 {
+		return MissingMediaURL(baseURL)
+	}
+	blob := media.BlobRefs[0]
+	for _, blobRef := range media.BlobRefs {
+		if slices.Contains(blobRef.Tags, "original") {
+			blob = blobRef
+			break
+		}
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + OIDToPath(blob.OID)
+}
+
+// MissingMediaURL returns the URL of the 404 image used for dangling medias.
+func MissingMediaURL(baseURL string) string {
+	if missingURL := CurrentConfig().ConfigFile.Medias.MissingURL; missingURL != "" {
+		return missingURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + OIDToPath(missingMediaOID)
+}
+
+// ResolveOIDLinks replaces OID links to medias (ex: oid:4044...) by the absolute paths of media files.
+// HTML media tags (ex: <media oid="4044..." />) are converted to images.
+// Unknown medias are left untouched.
+func (r *Repository) ResolveOIDLinks(content string) string {
+	regexMediaTag := regexp.MustCompile(`<media([^>]*?) oid="([0-9a-f]{40})"`)
+	content = regexMediaTag.ReplaceAllStringFunc(content, func(tag string) string {
+		match := regexMediaTag.FindStringSubmatch(tag)
+		media, err := r.LoadMediaByOID(match[2])
+		if err != nil || media == nil {
+			return tag
+		}
+		return fmt.Sprintf(`<img%s src="%s"`, match[1], html.EscapeString(r.GetAbsolutePath(media.RelativePath)))
+	})
+
+	regexOIDLink := regexp.MustCompile(`oid:([0-9a-f]{40})`)
+	return regexOIDLink.ReplaceAllStringFunc(content, func(link string) string {
+		media, err := r.LoadMediaByOID(strings.TrimPrefix(link, "oid:"))
+		if err != nil || media == nil {
+			return link
+		}
+		return r.GetAbsolutePath(media.RelativePath)
+	})
+}
+
 func (n *Note) updateLongTitle() {
+	settings := CurrentConfig().ConfigFile.Core.LongTitle
 	var titles []string
-	if n.GetFile() != nil && n.GetFile().ShortTitle != "" {
+	if settings.IncludeFileTitle != nil && !*settings.IncludeFileTitle {
+		// Ignore the file title
+	} else if n.GetFile() != nil && n.GetFile().ShortTitle != "" {
 		titles = append(titles, n.GetFile().ShortTitle)
 	}
 	if n.GetParentNote() != nil {
 		titles = append(titles, n.GetParentNote().ShortTitle)
 	}
 	titles = append(titles, n.ShortTitle)
-	newLongTitle := FormatLongTitle(titles...)
+	newLongTitle := settings.Format(titles...)
 	if n.LongTitle != newLongTitle {
 		n.LongTitle = newLongTitle
 		n.stale = true
@@ -623,12 +730,24 @@ func (n *Note) updateSlug() {
 		kind,
 		shortTitle,
 	)
+	if attributeSlug == "" && CurrentConfig().ConfigFile.Core.SlugDisambiguation {
+		newSlug = DisambiguateSlug(newSlug, func(slug string) bool {
+			existingNote, _ := CurrentRepository().FindNoteBySlug(slug)
+			return existingNote != nil && existingNote.OID != n.OID
+		})
+	}
 	if n.Slug != newSlug {
 		n.Slug = newSlug
 		n.stale = true
 	}
 }
 
+const (
+	SlugStrategyPath      = "path"
+	SlugStrategyTitle     = "title"
+	SlugStrategyPathTitle = "path+title"
+)
+
 // DetermineNoteSlug determines the note slug from the attributes.
 func DetermineNoteSlug(fileSlug string, attributeSlug string, kind NoteKind, shortTitle string) string {
 	if attributeSlug != "" {
@@ -637,15 +756,42 @@ func DetermineNoteSlug(fileSlug string, attributeSlug string, kind NoteKind, sho
 	}
 
 	// Slug must be generated
-	return markdown.Slug(fileSlug, string(kind), shortTitle)
+	switch CurrentConfig().ConfigFile.Core.SlugStrategy {
+	case SlugStrategyPath:
+		return markdown.Slug(fileSlug, string(kind))
+	case SlugStrategyTitle:
+		return markdown.Slug(string(kind), shortTitle)
+	default:
+		return markdown.Slug(fileSlug, string(kind), shortTitle)
+	}
+}
+
+// DisambiguateSlug appends a numeric suffix (ex: go-2) until the slug is not already used.
+func DisambiguateSlug(slug string, exists func(slug string) bool) string {
+	candidate := slug
+	for i := 2; exists(candidate); i++ {
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+	return candidate
+}
+
+// HashNoteContent returns the hash of a note content.
+// Formatting-only differences are ignored when normalize_hash is enabled.
+func HashNoteContent(rawContent string) string {
+	content := strings.TrimSpace(rawContent)
+	if CurrentConfig().ConfigFile.Core.NormalizeHash {
+		content = markdown.Normalize(content)
+	}
+	return helpers.Hash([]byte(content))
 }
 
 func (n *Note) updateContent(rawContent string) {
 	prevContentMarkdown := n.ContentMarkdown
 	prevAttributes := n.Attributes
+	prevHash := n.Hash
 
 	n.ContentRaw = strings.TrimSpace(rawContent)
-	n.Hash = helpers.Hash([]byte(n.ContentRaw))
+	n.Hash = HashNoteContent(n.ContentRaw)
 
 	tags, attributes := ExtractBlockTagsAndAttributes(n.ContentRaw)
 
@@ -657,11 +803,7 @@ func (n *Note) updateContent(rawContent string) {
 	}
 
 	// Merge with parent tags
-	if n.ParentNoteOID == "" {
-		tags = mergeTags(n.GetFile().GetTags(), tags)
-	} else {
-		tags = mergeTags(n.GetParentNote().GetTags(), tags)
-	}
+	tags = mergeTags(n.inheritedTags(), tags)
 
 	n.Tags = tags
 	n.Attributes = attributes
@@ -671,6 +813,25 @@ func (n *Note) updateContent(rawContent string) {
 		n.SetAttribute("title", n.ShortTitle)
 	}
 
+	// Append the attribution of quotes in attributes author and source if not already present
+	// Ex: "> — J.R.R. Tolkien, _The Fellowship of the Ring_"
+	if n.NoteKind == KindQuote {
+		content, _ := markdown.StripComment(StripBlockTagsAndAttributes(n.ContentRaw))
+		_, attribution := markdown.ExtractQuote(content)
+		author, source := markdown.ParseAttribution(attribution)
+		if _, ok := attributes["author"]; !ok && author != "" {
+			n.SetAttribute("author", author)
+		}
+		if _, ok := attributes["source"]; !ok && source != "" {
+			n.SetAttribute("source", source)
+		}
+	}
+
+	// Evaluate computed attributes once other attributes are merged
+	for name, value := range ComputeAttributes(n.Attributes, n.RelativePath, n.NoteKind) {
+		n.SetAttribute(name, value)
+	}
+
 	// Reread content as tags and attributes previously defined on the note can influence the output.
 	mdTitle, htmlTitle, txtTitle, mdContent, htmlContent, txtContent, mdComment, htmlComment, txtComment := n.parseContentRaw()
 	n.TitleMarkdown = mdTitle
@@ -679,23 +840,66 @@ func (n *Note) updateContent(rawContent string) {
 	n.ContentMarkdown = mdContent
 	n.ContentHTML = htmlContent
 	n.ContentText = txtContent
+	n.ItemsDone, n.ItemsTotal = markdown.CountTasks(mdContent)
+	n.WordCount = text.CountWords(txtContent)
 	n.CommentMarkdown = mdComment
 	n.CommentHTML = htmlComment
 	n.CommentText = txtComment
 
-	if prevContentMarkdown != n.ContentMarkdown || !reflect.DeepEqual(prevAttributes, n.Attributes) {
+	contentChanged := prevContentMarkdown != n.ContentMarkdown
+	if CurrentConfig().ConfigFile.Core.NormalizeHash {
+		// Ignore formatting-only changes
+		contentChanged = prevHash != n.Hash
+	}
+	if contentChanged || !reflect.DeepEqual(prevAttributes, n.Attributes) {
 		n.stale = true
 	}
 }
 
-// mergeAttributes is similar to generic mergeAttributes function but filter to exclude non-inheritable attributes.
+// mergeAttributes is similar to generic mergeAttributes function but filter to exclude non-inheritable attributes
+// and attributes declared too far up.
 func (n *Note) mergeAttributes(fileAttributes, parentNoteAttributes, noteAttributes map[string]interface{}) map[string]interface{} {
-	inheritableFileAttributes := fileAttributes
+	inheritableFileAttributes := FilterAttributesByInheritDepth(fileAttributes, n.RelativePath, n.NoteKind, len(n.ancestors())+1)
 	inheritableParentNoteAttributes := FilterNonInheritableAttributes(parentNoteAttributes, n.RelativePath, n.NoteKind)
+	inheritableParentNoteAttributes = FilterAttributesByInheritDepth(inheritableParentNoteAttributes, n.RelativePath, n.NoteKind, 1)
 	ownAttributes := noteAttributes
 	return MergeAttributes(inheritableFileAttributes, inheritableParentNoteAttributes, ownAttributes)
 }
 
+// ancestors returns the parent notes, starting with the closest one.
+func (n *Note) ancestors() []*Note {
+	var results []*Note
+	for parent := n.GetParentNote(); parent != nil; parent = parent.GetParentNote() {
+		results = append(results, parent)
+	}
+	return results
+}
+
+// inheritedTags returns the tags of the file and parent notes propagating down to the note.
+func (n *Note) inheritedTags() []string {
+	maxDepth := GetSchemaAttributeInheritDepth("tags", n.RelativePath, n.NoteKind)
+	if maxDepth == nil {
+		// Unlimited inheritance
+		if n.ParentNoteOID == "" {
+			return n.GetFile().GetTags()
+		}
+		return n.GetParentNote().GetTags()
+	}
+
+	ancestors := n.ancestors()
+	var tags []string
+	if len(ancestors)+1 <= *maxDepth {
+		tags = n.GetFile().GetTags()
+	}
+	// Iterate from the farthest to the closest parent to preserve the order of tags
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if i+1 <= *maxDepth {
+			tags = mergeTags(tags, ancestors[i].GetNoteTags())
+		}
+	}
+	return tags
+}
+
 // GetNoteAttributes returns the attributes specifically present on the note.
 func (n *Note) GetNoteAttributes() map[string]interface{} {
 	_, attributes := ExtractBlockTagsAndAttributes(n.ContentRaw)
@@ -803,10 +1007,10 @@ func isSupportedNote(text string) (bool, NoteKind, string) {
 		return true, KindArtwork, m[1]
 	}
 	if m := regexSnippet.FindStringSubmatch(text); m != nil {
-		return true, KindArtwork, m[1]
+		return true, KindSnippet, m[1]
 	}
 	if m := regexChecklist.FindStringSubmatch(text); m != nil {
-		return true, KindArtwork, m[1]
+		return true, KindChecklist, m[1]
 	}
 	if m := regexJournal.FindStringSubmatch(text); m != nil {
 		return true, KindJournal, m[1]
@@ -824,28 +1028,44 @@ func (n *Note) GetMedias() []*Media {
 // GetLinks extracts special links from a note.
 func (n *Note) GetLinks() []*Link {
 	var links []*Link
+	for _, goLink := range parseGoLinks(n.ContentRaw) {
+		link := NewOrExistingLink(n, goLink.Text, goLink.URL, goLink.Title, goLink.GoName)
+		links = append(links, link)
+	}
+	return links
+}
+
+// parsedGoLink represents a link declaring a Go name (ex: [Go](https://go.dev "#go/go")).
+type parsedGoLink struct {
+	Text   string
+	URL    string
+	Title  string
+	GoName string
+}
+
+// parseGoLinks extracts the links declaring a Go name.
+func parseGoLinks(md string) []parsedGoLink {
+	var results []parsedGoLink
 
 	reLink := regexp.MustCompile(`(?:^|[^!])\[(.*?)\]\("?(http[^\s"]*)"?(?:\s+["'](.*?)["'])?\)`)
 	// Note: Markdown images uses the same syntax as links but precedes the link by !
 	reTitle := regexp.MustCompile(`(?:(.*)\s+)?#go\/(\S+).*`)
 
-	matches := reLink.FindAllStringSubmatch(n.ContentRaw, -1)
+	matches := reLink.FindAllStringSubmatch(md, -1)
 	for _, match := range matches {
-		text := match[1]
-		url := match[2]
-		title := match[3]
-		submatch := reTitle.FindStringSubmatch(title)
+		submatch := reTitle.FindStringSubmatch(match[3])
 		if submatch == nil {
 			continue
 		}
-		shortTitle := submatch[1]
-		goName := submatch[2]
-
-		link := NewOrExistingLink(n, text, url, shortTitle, goName)
-		links = append(links, link)
+		results = append(results, parsedGoLink{
+			Text:   match[1],
+			URL:    match[2],
+			Title:  submatch[1],
+			GoName: submatch[2],
+		})
 	}
 
-	return links
+	return results
 }
 
 // GetReminders extracts reminders from the note.
@@ -919,7 +1139,9 @@ func (n *Note) Check() error {
 
 func (n *Note) Save() error {
 	var err error
-	n.UpdatedAt = clock.Now()
+	if !n.moved {
+		n.UpdatedAt = clock.Now()
+	}
 	n.LastCheckedAt = clock.Now()
 	switch n.State() {
 	case Added:
@@ -967,10 +1189,13 @@ func (n *Note) Insert() error {
 			comment_markdown,
 			comment_html,
 			comment_text,
+			items_done,
+			items_total,
+			word_count,
 			created_at,
 			updated_at,
 			last_checked_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 	`
 
 	attributesJSON, err := AttributesJSON(n.Attributes)
@@ -1003,6 +1228,9 @@ func (n *Note) Insert() error {
 		n.CommentMarkdown,
 		n.CommentHTML,
 		n.CommentText,
+		n.ItemsDone,
+		n.ItemsTotal,
+		n.WordCount,
 		timeToSQL(n.CreatedAt),
 		timeToSQL(n.UpdatedAt),
 		timeToSQL(n.LastCheckedAt),
@@ -1011,7 +1239,7 @@ func (n *Note) Insert() error {
 		return err
 	}
 
-	return nil
+	return n.saveTags()
 }
 
 func (n *Note) Update() error {
@@ -1042,6 +1270,9 @@ func (n *Note) Update() error {
 			comment_markdown = ?,
 			comment_html = ?,
 			comment_text = ?,
+			items_done = ?,
+			items_total = ?,
+			word_count = ?,
 			updated_at = ?,
 			last_checked_at = ?
 		WHERE oid = ?;
@@ -1076,21 +1307,85 @@ func (n *Note) Update() error {
 		n.CommentMarkdown,
 		n.CommentHTML,
 		n.CommentText,
+		n.ItemsDone,
+		n.ItemsTotal,
+		n.WordCount,
 		timeToSQL(n.UpdatedAt),
 		timeToSQL(n.LastCheckedAt),
 		n.OID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return n.saveTags()
 }
 
 func (n *Note) Delete() error {
 	CurrentLogger().Debugf("Deleting note %s...", n.Wikilink)
+	if _, err := CurrentDB().Client().Exec(`DELETE FROM note_tag WHERE note_oid = ?;`, n.OID); err != nil {
+		return err
+	}
 	query := `DELETE FROM note WHERE oid = ?;`
 	_, err := CurrentDB().Client().Exec(query, n.OID)
 	return err
 }
 
+// saveTags replaces the tags used to search the note by their canonical form.
+func (n *Note) saveTags() error {
+	if _, err := CurrentDB().Client().Exec(`DELETE FROM note_tag WHERE note_oid = ?;`, n.OID); err != nil {
+		return err
+	}
+	for _, tag := range n.Tags {
+		query := `INSERT OR IGNORE INTO note_tag(note_oid, tag) VALUES (?, ?);`
+		if _, err := CurrentDB().Client().Exec(query, n.OID, text.Fold(tag)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateWordCounts recomputes the word count of all notes from their text content
+// (ex: notes saved before word counts were introduced).
+func (db *DB) UpdateWordCounts() error {
+	wordCounts := make(map[string]int)
+	rows, err := db.Client().Query(`SELECT oid, content_text, word_count FROM note`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var oid string
+		var contentText string
+		var wordCount int
+		if err := rows.Scan(&oid, &contentText, &wordCount); err != nil {
+			rows.Close()
+			return err
+		}
+		if actual := text.CountWords(contentText); actual != wordCount {
+			wordCounts[oid] = actual
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	if len(wordCounts) == 0 {
+		return nil
+	}
+	if err := db.BeginTransaction(); err != nil {
+		return err
+	}
+	for oid, wordCount := range wordCounts {
+		if _, err := db.Client().Exec(`UPDATE note SET word_count = ? WHERE oid = ?;`, wordCount, oid); err != nil {
+			db.RollbackTransaction()
+			return err
+		}
+	}
+	return db.CommitTransaction()
+}
+
 // CountNotes returns the total number of notes.
 func (r *Repository) CountNotes() (int, error) {
 	var count int
@@ -1114,6 +1409,7 @@ func (r *Repository) CountNotesByKind() (map[NoteKind]int, error) {
 		KindTodo:       0,
 		KindArtwork:    0,
 		KindSnippet:    0,
+		KindChecklist:  0,
 	}
 
 	var count int
@@ -1147,6 +1443,9 @@ func (r *Repository) CountNotesByKind() (map[NoteKind]int, error) {
 	if err := CurrentDB().Client().QueryRow(`SELECT count(*) FROM note where kind = ?`, KindSnippet).Scan(&count); err == nil {
 		res[KindSnippet] = count
 	}
+	if err := CurrentDB().Client().QueryRow(`SELECT count(*) FROM note where kind = ?`, KindChecklist).Scan(&count); err == nil {
+		res[KindChecklist] = count
+	}
 
 	return res, nil
 }
@@ -1155,19 +1454,10 @@ func (r *Repository) CountNotesByKind() (map[NoteKind]int, error) {
 func (r *Repository) CountTags() (map[string]int, error) {
 	result := make(map[string]int)
 
-	// See https://www.vivekkalyan.com/splitting-comma-seperated-fields-sqlite
 	rows, err := CurrentDB().Client().Query(`
-		WITH RECURSIVE split(tag, str) AS (
-			SELECT '', tags||',' FROM note
-			UNION ALL SELECT
-			substr(str, 0, instr(str, ',')),
-			substr(str, instr(str, ',')+1)
-			FROM split WHERE str!=''
-		)
-		SELECT distinct tag, count(*)
-		FROM split
-		WHERE tag!=''
-		group by tag;`)
+		SELECT tag, count(*)
+		FROM note_tag
+		GROUP BY tag;`)
 	if err != nil {
 		return nil, err
 	}
@@ -1267,9 +1557,27 @@ func (r *Repository) FindNoteByPathAndTitle(relativePath string, title string) (
 	return QueryNote(CurrentDB().Client(), `WHERE relative_path = ? AND title = ?`, relativePath, title)
 }
 
-func (r *Repository) FindMatchingNote(relativePath string, parsedNote *ParsedNoteOld) (*Note, error) {
+// FindMatchingNote searches for the existing note corresponding to a parsed note.
+// Notes with the same content in the same file are preferred over notes with the same slug or title
+// (ex: renamed headings, swapped titles). The other notes parsed in the same file are used to
+// disambiguate between candidates.
+func (r *Repository) FindMatchingNote(relativePath string, parsedNote *ParsedNoteOld, parsedNotes []*ParsedNoteOld) (*Note, error) {
+	// Try by same content in the same file
+	note, err := r.findMatchingNoteByContent(relativePath, parsedNote, parsedNotes)
+	if err != nil {
+		return nil, err
+	}
+	if note != nil {
+		return note, nil
+	}
+
 	// Try by slug
-	note, _ := r.FindNoteBySlug(parsedNote.Slug)
+	note, _ = r.FindNoteBySlug(parsedNote.Slug)
+	if note != nil && CurrentConfig().ConfigFile.Core.SlugDisambiguation && note.RelativePath != relativePath {
+		// The generated slug may have been disambiguated (ex: go => go-2)
+		// and the matching note may be a different note in another file.
+		note = nil
+	}
 	if note != nil {
 		return note, nil
 	}
@@ -1280,8 +1588,51 @@ func (r *Repository) FindMatchingNote(relativePath string, parsedNote *ParsedNot
 		return note, nil
 	}
 
-	// Last by same title or same content in the same file
-	return QueryNote(CurrentDB().Client(), `WHERE relative_path = ? AND (title = ? OR hashsum = ?)`, relativePath, parsedNote.Title, parsedNote.Hash())
+	// Last by same title in the same file
+	return QueryNote(CurrentDB().Client(), `WHERE relative_path = ? AND title = ?`, relativePath, parsedNote.Title)
+}
+
+// findMatchingNoteByContent searches for a note with the same content in the same file.
+func (r *Repository) findMatchingNoteByContent(relativePath string, parsedNote *ParsedNoteOld, parsedNotes []*ParsedNoteOld) (*Note, error) {
+	hash := parsedNote.Hash()
+	candidates, err := QueryNotes(CurrentDB().Client(), `WHERE relative_path = ? AND hashsum = ?`, relativePath, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingNote *Note
+	for _, candidate := range candidates {
+		if candidate.Title == parsedNote.Title {
+			// Unchanged note
+			return candidate, nil
+		}
+
+		// Ignore candidates still present unchanged under their title (ex: copied note)
+		unchanged := false
+		for _, otherNote := range parsedNotes {
+			if otherNote != parsedNote && otherNote.Title == candidate.Title && otherNote.Hash() == hash {
+				unchanged = true
+				break
+			}
+		}
+		if unchanged {
+			continue
+		}
+
+		// Prefer the closest note when several notes share the same content
+		if matchingNote == nil || lineDistance(candidate.Line, parsedNote.Line) < lineDistance(matchingNote.Line, parsedNote.Line) {
+			matchingNote = candidate
+		}
+	}
+	return matchingNote, nil
+}
+
+// lineDistance returns the number of lines between two lines.
+func lineDistance(line1, line2 int) int {
+	if line1 > line2 {
+		return line1 - line2
+	}
+	return line2 - line1
 }
 
 func (r *Repository) FindNoteByWikilink(wikilink string) (*Note, error) {
@@ -1292,6 +1643,247 @@ func (r *Repository) FindNotesByWikilink(wikilink string) ([]*Note, error) {
 	return QueryNotes(CurrentDB().Client(), `WHERE wikilink LIKE ?`, "%"+wikilink)
 }
 
+// FindNotesByTag returns the notes having the given tag (inherited tags included).
+// Tags must match exactly, ignoring case and diacritics (ex: "go" doesn't match "golang").
+func (r *Repository) FindNotesByTag(tag string) ([]*Note, error) {
+	return r.FindNotesByAllTags(tag)
+}
+
+// FindNotesByAllTags returns the notes having all the given tags.
+func (r *Repository) FindNotesByAllTags(tags ...string) ([]*Note, error) {
+	tagsSQL, tagsArgs := foldedTagsSQL(tags)
+	if len(tagsArgs) == 0 {
+		return nil, nil
+	}
+	args := append(tagsArgs, len(tagsArgs))
+	return QueryNotes(CurrentDB().Client(), `WHERE oid IN (
+		SELECT note_oid FROM note_tag WHERE tag IN (`+tagsSQL+`) GROUP BY note_oid HAVING count(*) = ?
+	) ORDER BY relative_path, line`, args...)
+}
+
+// FindNotesByAnyTag returns the notes having at least one of the given tags.
+func (r *Repository) FindNotesByAnyTag(tags ...string) ([]*Note, error) {
+	tagsSQL, tagsArgs := foldedTagsSQL(tags)
+	if len(tagsArgs) == 0 {
+		return nil, nil
+	}
+	return QueryNotes(CurrentDB().Client(), `WHERE oid IN (
+		SELECT note_oid FROM note_tag WHERE tag IN (`+tagsSQL+`)
+	) ORDER BY relative_path, line`, tagsArgs...)
+}
+
+// foldedTagsSQL returns the placeholders and the arguments to search the given tags in the table note_tag.
+// Duplicate tags after folding are ignored.
+func foldedTagsSQL(tags []string) (string, []any) {
+	var placeholders []string
+	var args []any
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = text.Fold(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		placeholders = append(placeholders, "?")
+		args = append(args, tag)
+	}
+	return strings.Join(placeholders, ","), args
+}
+
+// FindNotesBySourceDomain returns the notes whose attribute "source" is a URL on the given domain.
+// Subdomains are included (ex: "wikipedia.org" matches "en.wikipedia.org").
+func (r *Repository) FindNotesBySourceDomain(domain string) ([]*Note, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	relations, err := QueryRelations(CurrentDB().Client(), `WHERE type = ?`, "external_source")
+	if err != nil {
+		return nil, err
+	}
+
+	var oidsSQL []string
+	var oidsArgs []any
+	for _, relation := range relations {
+		u, err := url.Parse(relation.TargetOID)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			oidsSQL = append(oidsSQL, "?")
+			oidsArgs = append(oidsArgs, relation.SourceOID)
+		}
+	}
+	if len(oidsArgs) == 0 {
+		return nil, nil
+	}
+
+	return QueryNotes(CurrentDB().Client(), "WHERE oid IN ("+strings.Join(oidsSQL, ",")+") ORDER BY relative_path, line", oidsArgs...)
+}
+
+// ResolveEditorLocation returns the absolute file path and the line of the note
+// matching a wikilink, a slug, or an OID. Wikilinks to files resolve to the first line.
+func (r *Repository) ResolveEditorLocation(query string) (string, int, error) {
+	note, err := r.ResolveNote(query)
+	if err != nil {
+		return "", 0, err
+	}
+	if note != nil {
+		return r.GetAbsolutePath(note.RelativePath), note.Line, nil
+	}
+
+	link := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(query), "[["), "]]")
+	if link != "" && !strings.Contains(link, "#") {
+		file, err := r.FindFileByWikilink(link)
+		if err != nil {
+			return "", 0, err
+		}
+		if file != nil {
+			return r.GetAbsolutePath(file.RelativePath), 1, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("no note found for %q", query)
+}
+
+// ResolveNote returns the note matching a wikilink, a slug, or an OID (nil if none).
+// OIDs can be abbreviated using a unique prefix.
+func (r *Repository) ResolveNote(query string) (*Note, error) {
+	query = strings.TrimSpace(query)
+
+	if regexp.MustCompile(`^[0-9a-f]{4,40}$`).MatchString(query) {
+		oid, err := CurrentDB().ResolveOID(query)
+		var ambiguousErr *AmbiguousOIDError
+		if errors.As(err, &ambiguousErr) {
+			return nil, err
+		}
+		if err == nil {
+			note, err := r.LoadNoteByOID(oid)
+			if err != nil {
+				return nil, err
+			}
+			if note != nil {
+				return note, nil
+			}
+		}
+	}
+
+	note, err := r.FindNoteBySlug(query)
+	if err != nil {
+		return nil, err
+	}
+	if note != nil {
+		return note, nil
+	}
+
+	link := strings.TrimSuffix(strings.TrimPrefix(query, "[["), "]]")
+	if !strings.Contains(link, "#") {
+		return nil, nil
+	}
+	notes, err := r.FindNotesByWikilink(link)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) > 1 {
+		return nil, fmt.Errorf("multiple notes found for %q", query)
+	}
+	if len(notes) == 1 {
+		return notes[0], nil
+	}
+	return nil, nil
+}
+
+// FindSnippets returns the snippet notes matching the optional query (see SearchNotes for the syntax).
+func (r *Repository) FindSnippets(query string) ([]*Note, error) {
+	if strings.TrimSpace(query) == "" {
+		return QueryNotes(CurrentDB().Client(), `WHERE kind = ? ORDER BY relative_path, line`, KindSnippet)
+	}
+	return r.SearchNotes(fmt.Sprintf("kind:%s %s", KindSnippet, query))
+}
+
+// FindFuzzyCandidates returns the notes matching the optional query to filter interactively using FuzzyFindNotes.
+func (r *Repository) FindFuzzyCandidates(query string) ([]*Note, error) {
+	if strings.TrimSpace(query) == "" {
+		return QueryNotes(CurrentDB().Client(), `ORDER BY relative_path, line`)
+	}
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// Filter among all matching notes
+	q.Limit = MaxQueryLimit
+	results, err := r.searchNotesByQuery(q, false)
+	if err != nil {
+		return nil, err
+	}
+	var notes []*Note
+	for _, result := range results {
+		notes = append(notes, result.Note)
+	}
+	return notes, nil
+}
+
+// FuzzyFindNotes returns the notes whose long title or wikilink fuzzy matches the pattern, best matches first.
+func FuzzyFindNotes(notes []*Note, pattern string) []*Note {
+	type scoredNote struct {
+		note  *Note
+		score int
+	}
+	var matches []scoredNote
+	for _, note := range notes {
+		score, ok := text.FuzzyMatch(pattern, note.LongTitle)
+		if wikilinkScore, wikilinkOk := text.FuzzyMatch(pattern, note.Wikilink); wikilinkOk && (!ok || wikilinkScore > score) {
+			score, ok = wikilinkScore, true
+		}
+		if ok {
+			matches = append(matches, scoredNote{note: note, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	var result []*Note
+	for _, match := range matches {
+		result = append(result, match.note)
+	}
+	return result
+}
+
+// RandomQuote returns a random quote among the quotes matching the optional query, or nil if no quote matches.
+func (r *Repository) RandomQuote(query string) (*Note, error) {
+	var quotes []*Note
+	if strings.TrimSpace(query) == "" {
+		notes, err := QueryNotes(CurrentDB().Client(), `WHERE kind = ?`, KindQuote)
+		if err != nil {
+			return nil, err
+		}
+		quotes = notes
+	} else {
+		q, err := ParseQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		// Pick among all matching quotes
+		q.Kinds = []string{string(KindQuote)}
+		q.Limit = MaxQueryLimit
+		results, err := r.searchNotesByQuery(q, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			quotes = append(quotes, result.Note)
+		}
+	}
+	if len(quotes) == 0 {
+		return nil, nil
+	}
+	return quotes[rand.Intn(len(quotes))], nil
+}
+
+// FindChecklists returns all checklist notes with their completion.
+func (r *Repository) FindChecklists() ([]*Note, error) {
+	return QueryNotes(CurrentDB().Client(), `WHERE kind = ? ORDER BY relative_path, line`, KindChecklist)
+}
+
 func (r *Repository) FindNotesLastCheckedBefore(point time.Time, path string) ([]*Note, error) {
 	if path == "." {
 		path = ""
@@ -1299,77 +1891,241 @@ func (r *Repository) FindNotesLastCheckedBefore(point time.Time, path string) ([
 	return QueryNotes(CurrentDB().Client(), `WHERE last_checked_at < ? AND relative_path LIKE ?`, timeToSQL(point), path+"%")
 }
 
+// RunSavedSearch returns the notes matching the query of the saved search [search.<name>].
+func (r *Repository) RunSavedSearch(name string) ([]*Note, error) {
+	search, ok := CurrentConfig().ConfigFile.Search[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown saved search %q", name)
+	}
+	return r.SearchNotes(search.Q)
+}
+
 // SearchNotes query notes to find the ones matching a list of criteria.
 //
 // Examples:
 //
 //	tag:favorite kind:reference kind:note path:projects/
+//	@author.name:Pike attr:tags[]=go
+//	kind:todo sort:updated limit:50 offset:50
 func (r *Repository) SearchNotes(q string) ([]*Note, error) {
+	results, err := r.searchNotes(q, false)
+	if err != nil {
+		return nil, err
+	}
+	var notes []*Note
+	for _, result := range results {
+		notes = append(notes, result.Note)
+	}
+	return notes, nil
+}
+
+// Markers surrounding the matched terms in snippets
+const (
+	SnippetStartMarker = "**"
+	SnippetEndMarker   = "**"
+)
+
+// Maximum number of tokens in snippets
+const snippetMaxTokens = 16
+
+// NoteSearchResult is a note matching a search.
+type NoteSearchResult struct {
+	Note *Note
+	// Text around the matched terms, highlighted using SnippetStartMarker and SnippetEndMarker
+	// (empty when the query contains no terms)
+	Snippet string
+}
+
+// SearchNotesWithSnippets works like SearchNotes but also returns snippets explaining why notes matched.
+func (r *Repository) SearchNotesWithSnippets(q string) ([]*NoteSearchResult, error) {
+	return r.searchNotes(q, true)
+}
+
+// searchNotes implements SearchNotes and SearchNotesWithSnippets.
+func (r *Repository) searchNotes(q string, withSnippets bool) ([]*NoteSearchResult, error) {
 	query, err := ParseQuery(q)
 	if err != nil {
 		return nil, err
 	}
+	return r.searchNotesByQuery(query, withSnippets)
+}
+
+// searchNotesByQuery searches notes using an already parsed query.
+func (r *Repository) searchNotesByQuery(query *Query, withSnippets bool) ([]*NoteSearchResult, error) {
+	expression := ftsMatchExpression(query.Terms)
 
-	// Prepare SQL values
+	// Prepare SQL values (user values are always passed as arguments)
 	var querySQL strings.Builder
-	querySQL.WriteString("SELECT note_fts.rowid ")
+	var args []any
+	if withSnippets && expression != "" {
+		// snippet() is only available on full-text queries
+		// Column 3 = content_text
+		querySQL.WriteString("SELECT note_fts.oid, snippet(note_fts, 3, ?, ?, '…', ?) ")
+		args = append(args, SnippetStartMarker, SnippetEndMarker, snippetMaxTokens)
+	} else {
+		querySQL.WriteString("SELECT note_fts.oid, '' ")
+	}
 	querySQL.WriteString("FROM note_fts ")
 	querySQL.WriteString("JOIN note on note.oid = note_fts.oid ")
 	querySQL.WriteString("WHERE note.oid IS NOT NULL ") // useless but simplify the query building
 	if len(query.Kinds) > 0 {
 		var kindsSQL []string
 		for _, kind := range query.Kinds {
-			kindsSQL = append(kindsSQL, fmt.Sprintf(`"%s"`, kind))
+			kindsSQL = append(kindsSQL, "?")
+			args = append(args, kind)
 		}
 		querySQL.WriteString(fmt.Sprintf("AND note.kind IN (%s) ", strings.Join(kindsSQL, ",")))
 	}
 	if len(query.Tags) > 0 {
-		querySQL.WriteString("AND ( ")
+		var tagsSQL []string
 		for _, tag := range query.Tags {
-			querySQL.WriteString(fmt.Sprintf("  note.tags LIKE '%%%s%%' ", tag))
+			// Tags must match exactly, ignoring case and diacritics
+			tagsSQL = append(tagsSQL, `EXISTS (SELECT 1 FROM note_tag WHERE note_tag.note_oid = note.oid AND note_tag.tag = ?)`)
+			args = append(args, text.Fold(tag))
 		}
-		querySQL.WriteString(") ")
+		querySQL.WriteString(fmt.Sprintf("AND ( %s ) ", strings.Join(tagsSQL, " AND ")))
 	}
 	if len(query.Attributes) > 0 {
-		querySQL.WriteString("AND ( ")
+		var attributesSQL []string
 		for name, value := range query.Attributes {
-			querySQL.WriteString(fmt.Sprintf(`  json_extract(note.attributes, "$.%s") = "%s" `, name, value))
+			if !regexAttributePath.MatchString(name) {
+				return nil, fmt.Errorf("invalid attribute path %q", name)
+			}
+			jsonPath, array := attributeJSONPath(name)
+			if array {
+				attributesSQL = append(attributesSQL, `EXISTS (SELECT 1 FROM json_each(note.attributes, ?) WHERE json_each.value = ?)`)
+			} else {
+				attributesSQL = append(attributesSQL, `json_extract(note.attributes, ?) = ?`)
+			}
+			args = append(args, jsonPath, value)
 		}
-		querySQL.WriteString(") ")
+		querySQL.WriteString(fmt.Sprintf("AND ( %s ) ", strings.Join(attributesSQL, " AND ")))
 	}
-	if query.Path != "" {
-		querySQL.WriteString(fmt.Sprintf("AND note.relative_path LIKE '%s' ", query.Path+"%"))
+	if len(query.Paths) > 0 {
+		var pathsSQL []string
+		for _, path := range query.Paths {
+			pathsSQL = append(pathsSQL, `note.relative_path LIKE ? ESCAPE '\'`)
+			args = append(args, escapeLike(path)+"%")
+		}
+		querySQL.WriteString(fmt.Sprintf("AND ( %s ) ", strings.Join(pathsSQL, " OR ")))
 	}
-	if len(query.Terms) > 0 {
-		querySQL.WriteString(fmt.Sprintf("AND note_fts MATCH '%s' ", strings.Join(query.Terms, " AND ")))
+	var relatedOIDs []string
+	if query.Related != "" {
+		note, err := r.FindNoteBySlug(query.Related)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, fmt.Errorf("no note found with slug %q", query.Related)
+		}
+		relatedNotes, err := r.FindRelatedNotes(note.OID, MaxQueryLimit)
+		if err != nil {
+			return nil, err
+		}
+		if len(relatedNotes) == 0 {
+			return nil, nil
+		}
+		var relatedSQL []string
+		for _, relatedNote := range relatedNotes {
+			relatedOIDs = append(relatedOIDs, relatedNote.OID)
+			relatedSQL = append(relatedSQL, "?")
+			args = append(args, relatedNote.OID)
+		}
+		querySQL.WriteString(fmt.Sprintf("AND note.oid IN (%s) ", strings.Join(relatedSQL, ",")))
+	}
+	if expression != "" {
+		querySQL.WriteString("AND note_fts MATCH ? ")
+		args = append(args, expression)
 	}
 
-	querySQL.WriteString("ORDER BY rank LIMIT 10;")
-	CurrentLogger().Debug(querySQL.String())
+	if len(relatedOIDs) > 0 && query.Sort == SortRank && len(query.Terms) == 0 {
+		// Most related notes first
+		querySQL.WriteString("ORDER BY instr(?, note.oid) LIMIT ? OFFSET ?;")
+		args = append(args, strings.Join(relatedOIDs, ","), query.Limit, query.Offset)
+	} else {
+		querySQL.WriteString(fmt.Sprintf("ORDER BY %s LIMIT ? OFFSET ?;", querySortClauses[query.Sort]))
+		args = append(args, query.Limit, query.Offset)
+	}
+	CurrentLogger().Debug(querySQL.String(), args)
 	queryFTS, err := CurrentDB().Client().Prepare(querySQL.String())
 	if err != nil {
 		return nil, err
 	}
-	res, err := queryFTS.Query()
+	res, err := queryFTS.Query(args...)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer res.Close()
-	var ids []string
+	var oids []string
+	var oidsSQL []string
+	var oidsArgs []any
+	snippets := make(map[string]string)
 	for res.Next() {
-		var id int
-		res.Scan(&id)
-		ids = append(ids, fmt.Sprint(id))
-	}
-	if len(ids) == 0 {
+		var oid string
+		var snippet string
+		res.Scan(&oid, &snippet)
+		oids = append(oids, oid)
+		oidsSQL = append(oidsSQL, "?")
+		oidsArgs = append(oidsArgs, oid)
+		snippets[oid] = snippet
+	}
+	if len(oids) == 0 {
 		return nil, nil
 	}
 
-	return QueryNotes(CurrentDB().Client(), "WHERE rowid IN ("+strings.Join(ids, ",")+")")
+	notes, err := QueryNotes(CurrentDB().Client(), "WHERE oid IN ("+strings.Join(oidsSQL, ",")+")", oidsArgs...)
+	if err != nil {
+		return nil, err
+	}
+	// Restore the order of the search
+	slices.SortFunc(notes, func(a, b *Note) bool {
+		return slices.Index(oids, a.OID) < slices.Index(oids, b.OID)
+	})
+	var results []*NoteSearchResult
+	for _, note := range notes {
+		results = append(results, &NoteSearchResult{
+			Note:    note,
+			Snippet: snippets[note.OID],
+		})
+	}
+	return results, nil
+}
+
+// querySortClauses maps query sorts to SQL order clauses.
+var querySortClauses = map[string]string{
+	SortRank:    "rank",
+	SortCreated: "note.created_at DESC",
+	SortUpdated: "note.updated_at DESC",
+	SortTitle:   "note.title",
 }
 
 /* SQL Helpers */
 
+// escapeLike escapes the special characters of a LIKE pattern (using \ as escape character).
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
+// ftsMatchExpression converts search terms to a FTS5 expression.
+// Every term is quoted to be searched literally (FTS5 operators and special characters are ignored)
+// except for a trailing * to search by prefix.
+func ftsMatchExpression(terms []string) string {
+	var expressions []string
+	for _, term := range terms {
+		prefix := ""
+		if strings.HasSuffix(term, "*") {
+			term = strings.TrimRight(term, "*")
+			prefix = "*"
+		}
+		if term == "" {
+			continue
+		}
+		expressions = append(expressions, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`+prefix)
+	}
+	return strings.Join(expressions, " AND ")
+}
+
 func QueryNote(db SQLClient, whereClause string, args ...any) (*Note, error) {
 	var n Note
 	var createdAt string
@@ -1405,6 +2161,9 @@ func QueryNote(db SQLClient, whereClause string, args ...any) (*Note, error) {
 			comment_markdown,
 			comment_html,
 			comment_text,
+			items_done,
+			items_total,
+			word_count,
 			created_at,
 			updated_at,
 			last_checked_at
@@ -1435,6 +2194,9 @@ func QueryNote(db SQLClient, whereClause string, args ...any) (*Note, error) {
 			&n.CommentMarkdown,
 			&n.CommentHTML,
 			&n.CommentText,
+			&n.ItemsDone,
+			&n.ItemsTotal,
+			&n.WordCount,
 			&createdAt,
 			&updatedAt,
 			&lastCheckedAt,
@@ -1461,7 +2223,15 @@ func QueryNote(db SQLClient, whereClause string, args ...any) (*Note, error) {
 
 func QueryNotes(db SQLClient, whereClause string, args ...any) ([]*Note, error) {
 	var notes []*Note
+	err := QueryNotesFunc(db, func(n *Note) error {
+		notes = append(notes, n)
+		return nil
+	}, whereClause, args...)
+	return notes, err
+}
 
+// QueryNotesFunc calls fn for every matching note without loading all notes in memory.
+func QueryNotesFunc(db SQLClient, fn func(*Note) error, whereClause string, args ...any) error {
 	rows, err := db.Query(fmt.Sprintf(`
 		SELECT
 			oid,
@@ -1488,13 +2258,16 @@ func QueryNotes(db SQLClient, whereClause string, args ...any) ([]*Note, error)
 			comment_markdown,
 			comment_html,
 			comment_text,
+			items_done,
+			items_total,
+			word_count,
 			created_at,
 			updated_at,
 			last_checked_at
 		FROM note
 		%s;`, whereClause), args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 	for rows.Next() {
@@ -1530,17 +2303,20 @@ func QueryNotes(db SQLClient, whereClause string, args ...any) ([]*Note, error)
 			&n.CommentMarkdown,
 			&n.CommentHTML,
 			&n.CommentText,
+			&n.ItemsDone,
+			&n.ItemsTotal,
+			&n.WordCount,
 			&createdAt,
 			&updatedAt,
 			&lastCheckedAt,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		attributes, err := UnmarshalAttributes(attributesRaw)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		n.Attributes = attributes
@@ -1548,44 +2324,48 @@ func QueryNotes(db SQLClient, whereClause string, args ...any) ([]*Note, error)
 		n.CreatedAt = timeFromSQL(createdAt)
 		n.UpdatedAt = timeFromSQL(updatedAt)
 		n.LastCheckedAt = timeFromSQL(lastCheckedAt)
-		notes = append(notes, &n)
-	}
-
-	err = rows.Err()
-	if err != nil {
-		return nil, err
+		if err := fn(&n); err != nil {
+			return err
+		}
 	}
 
-	return notes, err
+	return rows.Err()
 }
 
 /* Format */
 
-func (n *Note) FormatToJSON() string {
-	type NoteRepresentation struct {
-		OID                string                 `json:"oid"`
-		Slug               string                 `json:"slug"`
-		RelativePath       string                 `json:"relativePath"`
-		Wikilink           string                 `json:"wikilink"`
-		Attributes         map[string]interface{} `json:"attributes"`
-		Tags               []string               `json:"tags"`
-		ShortTitleRaw      string                 `json:"shortTitleRaw"`
-		ShortTitleMarkdown string                 `json:"shortTitleMarkdown"`
-		ShortTitleHTML     string                 `json:"shortTitleHTML"`
-		ShortTitleText     string                 `json:"shortTitleText"`
-		ContentRaw         string                 `json:"contentRaw"`
-		ContentMarkdown    string                 `json:"contentMarkdown"`
-		ContentHTML        string                 `json:"contentHTML"`
-		ContentText        string                 `json:"contentText"`
-		CreatedAt          time.Time              `json:"createdAt"`
-		UpdatedAt          time.Time              `json:"updatedAt"`
-		DeletedAt          *time.Time             `json:"deletedAt"`
-	}
-	repr := NoteRepresentation{
+// NoteRepresentation is the JSON representation of a note.
+type NoteRepresentation struct {
+	OID                string                 `json:"oid"`
+	Slug               string                 `json:"slug"`
+	Kind               NoteKind               `json:"kind"`
+	RelativePath       string                 `json:"relativePath"`
+	Wikilink           string                 `json:"wikilink"`
+	LongTitle          string                 `json:"longTitle"`
+	Attributes         map[string]interface{} `json:"attributes"`
+	Tags               []string               `json:"tags"`
+	ShortTitleRaw      string                 `json:"shortTitleRaw"`
+	ShortTitleMarkdown string                 `json:"shortTitleMarkdown"`
+	ShortTitleHTML     string                 `json:"shortTitleHTML"`
+	ShortTitleText     string                 `json:"shortTitleText"`
+	ContentRaw         string                 `json:"contentRaw"`
+	ContentMarkdown    string                 `json:"contentMarkdown"`
+	ContentHTML        string                 `json:"contentHTML"`
+	ContentText        string                 `json:"contentText"`
+	CreatedAt          time.Time              `json:"createdAt"`
+	UpdatedAt          time.Time              `json:"updatedAt"`
+	DeletedAt          *time.Time             `json:"deletedAt"`
+}
+
+// Representation returns the representation of the note used when exporting notes.
+func (n *Note) Representation() *NoteRepresentation {
+	repr := &NoteRepresentation{
 		OID:                n.OID,
 		Slug:               n.Slug,
+		Kind:               n.NoteKind,
 		RelativePath:       n.RelativePath,
 		Wikilink:           n.Wikilink,
+		LongTitle:          n.LongTitle,
 		ShortTitleRaw:      n.ShortTitle,
 		ShortTitleMarkdown: markdown.ToMarkdown(n.ShortTitle),
 		ShortTitleHTML:     markdown.ToHTML(n.ShortTitle),
@@ -1602,7 +2382,29 @@ func (n *Note) FormatToJSON() string {
 	if !n.DeletedAt.IsZero() {
 		repr.DeletedAt = &n.DeletedAt
 	}
-	output, _ := json.MarshalIndent(repr, "", " ")
+	return repr
+}
+
+// Excerpt returns a short plain text preview of the note content (ex: for search results).
+func (n *Note) Excerpt(maxChars int) string {
+	return markdown.Excerpt(n.ContentMarkdown, maxChars)
+}
+
+// RepresentationWithMediaBaseURL is similar to Representation but links to medias use public URLs
+// under the given base URL instead of OID links.
+func (n *Note) RepresentationWithMediaBaseURL(baseURL string) *NoteRepresentation {
+	repr := n.Representation()
+	_, _, _, mdContent, htmlContent, txtContent, _, _, _ := n.parseContentRawWithMedias(func(md string) string {
+		return n.ReplaceMediasByURL(md, baseURL)
+	})
+	repr.ContentMarkdown = mdContent
+	repr.ContentHTML = htmlContent
+	repr.ContentText = txtContent
+	return repr
+}
+
+func (n *Note) FormatToJSON() string {
+	output, _ := json.MarshalIndent(n.Representation(), "", " ")
 	return string(output)
 }
 
@@ -1636,8 +2438,13 @@ func (n *Note) FormatToText() string {
 	return sb.String()
 }
 
-// FormatLongTitle formats the long title of a note.
+// FormatLongTitle formats the long title of a note using the default settings.
 func FormatLongTitle(titles ...string) string {
+	return ConfigLongTitle{}.Format(titles...)
+}
+
+// Format formats the long title of a note from the file title to the note title.
+func (c ConfigLongTitle) Format(titles ...string) string {
 	// Implementation: We concatenate the titles but we must avoid duplication.
 	//
 	// Ex:
@@ -1656,6 +2463,7 @@ func FormatLongTitle(titles ...string) string {
 
 	prevTitle := ""
 	longTitle := ""
+	var segments []string
 
 	for i := len(titles) - 1; i >= 0; i-- {
 		title := titles[i]
@@ -1681,8 +2489,18 @@ func FormatLongTitle(titles ...string) string {
 		} else {
 			longTitle = title + NoteLongTitleSeparator + longTitle
 		}
+		segments = append([]string{title}, segments...)
 		prevTitle = title
 	}
 
-	return longTitle
+	// Keep the closest titles to the note
+	if c.MaxSegments > 0 && len(segments) > c.MaxSegments {
+		segments = segments[len(segments)-c.MaxSegments:]
+	}
+
+	separator := c.Separator
+	if separator == "" {
+		separator = NoteLongTitleSeparator
+	}
+	return strings.Join(segments, separator)
 }
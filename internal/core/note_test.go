@@ -2,10 +2,13 @@ package core
 
 import (
 	"bytes"
+	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/julien-sobczak/the-notewriter/pkg/markdown"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -225,6 +228,36 @@ Wisdom is making the right choice without all the information.
 
 }
 
+func TestIsSupportedNote(t *testing.T) {
+	tests := []struct {
+		title         string
+		expectedOk    bool
+		expectedKind  NoteKind
+		expectedTitle string
+	}{
+		{"Reference: Go History", true, KindReference, "Go History"},
+		{"Note: On Go Logo", true, KindNote, "On Go Logo"},
+		{"Flashcard: Goroutines Syntax", true, KindFlashcard, "Goroutines Syntax"},
+		{"Cheatsheet: How to start a goroutine", true, KindCheatsheet, "How to start a goroutine"},
+		{"Quote: Marcus Aurelius on Doing", true, KindQuote, "Marcus Aurelius on Doing"},
+		{"TODO: Backlog", true, KindTodo, "Backlog"},
+		{"Artwork: Vincent van Gogh", true, KindArtwork, "Vincent van Gogh"},
+		{"Snippet: Ideas for post title", true, KindSnippet, "Ideas for post title"},
+		{"Checklist: Travel", true, KindChecklist, "Travel"},
+		{"Journal: 2023-01-01", true, KindJournal, "2023-01-01"},
+		{"snippet: Lowercase", true, KindSnippet, "Lowercase"},
+		{"Free note", false, KindFree, "Free note"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			ok, kind, title := isSupportedNote(tt.title)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedKind, kind)
+			assert.Equal(t, tt.expectedTitle, title)
+		})
+	}
+}
+
 func TestGetLinks(t *testing.T) {
 	var tests = []struct {
 		name     string  // name
@@ -349,7 +382,7 @@ func TestNoteFormat(t *testing.T) {
 
 * [ ] Buy **Lego Christmas** sets to create a village ”#reminder-2025-09”
 `,
-			expectedJSON:     "{\n \"oid\": \"16252dafd6355e678bf8ae44b127f657cd3cdd0e\",\n \"slug\": \"todo-activities\",\n \"relativePath\": \"\",\n \"wikilink\": \"#TODO: **Activities**\",\n \"attributes\": {\n  \"title\": \"**Activities**\"\n },\n \"tags\": null,\n \"shortTitleRaw\": \"**Activities**\",\n \"shortTitleMarkdown\": \"**Activities**\",\n \"shortTitleHTML\": \"\\u003cp\\u003e\\u003cstrong\\u003eActivities\\u003c/strong\\u003e\\u003c/p\\u003e\",\n \"shortTitleText\": \"Activities\",\n \"contentRaw\": \"* [ ] Buy **Lego Christmas** sets to create a village `#reminder-2025-09`\",\n \"contentMarkdown\": \"* [ ] Buy **Lego Christmas** sets to create a village `#reminder-2025-09`\",\n \"contentHTML\": \"\\u003cul\\u003e\\n\\u003cli\\u003e\\u003cinput type=\\\"checkbox\\\" /\\u003e Buy \\u003cstrong\\u003eLego Christmas\\u003c/strong\\u003e sets to create a village \\u003ccode\\u003e#reminder-2025-09\\u003c/code\\u003e\\u003c/li\\u003e\\n\\u003c/ul\\u003e\",\n \"contentText\": \"* [ ] Buy Lego Christmas sets to create a village #reminder-2025-09\",\n \"createdAt\": \"2023-01-01T01:12:30Z\",\n \"updatedAt\": \"2023-01-01T01:12:30Z\",\n \"deletedAt\": null\n}",
+			expectedJSON:     "{\n \"oid\": \"16252dafd6355e678bf8ae44b127f657cd3cdd0e\",\n \"slug\": \"todo-activities\",\n \"kind\": \"todo\",\n \"relativePath\": \"\",\n \"wikilink\": \"#TODO: **Activities**\",\n \"longTitle\": \"**Activities**\",\n \"attributes\": {\n  \"title\": \"**Activities**\"\n },\n \"tags\": null,\n \"shortTitleRaw\": \"**Activities**\",\n \"shortTitleMarkdown\": \"**Activities**\",\n \"shortTitleHTML\": \"\\u003cp\\u003e\\u003cstrong\\u003eActivities\\u003c/strong\\u003e\\u003c/p\\u003e\",\n \"shortTitleText\": \"Activities\",\n \"contentRaw\": \"* [ ] Buy **Lego Christmas** sets to create a village `#reminder-2025-09`\",\n \"contentMarkdown\": \"* [ ] Buy **Lego Christmas** sets to create a village `#reminder-2025-09`\",\n \"contentHTML\": \"\\u003cul\\u003e\\n\\u003cli\\u003e\\u003cinput type=\\\"checkbox\\\" /\\u003e Buy \\u003cstrong\\u003eLego Christmas\\u003c/strong\\u003e sets to create a village \\u003ccode\\u003e#reminder-2025-09\\u003c/code\\u003e\\u003c/li\\u003e\\n\\u003c/ul\\u003e\",\n \"contentText\": \"* [ ] Buy Lego Christmas sets to create a village #reminder-2025-09\",\n \"createdAt\": \"2023-01-01T01:12:30Z\",\n \"updatedAt\": \"2023-01-01T01:12:30Z\",\n \"deletedAt\": null\n}",
 			expectedMarkdown: "# TODO: **Activities**\n\n* [ ] Buy **Lego Christmas** sets to create a village `#reminder-2025-09`",
 			expectedHTML:     "<h1><p>TODO: <strong>Activities</strong></p></h1>\n\n<ul>\n<li><input type=\"checkbox\" /> Buy <strong>Lego Christmas</strong> sets to create a village <code>#reminder-2025-09</code></li>\n</ul>",
 			expectedText:     "TODO: Activities\n\n* [ ] Buy Lego Christmas sets to create a village #reminder-2025-09",
@@ -423,6 +456,526 @@ func TestSearchNotes(t *testing.T) {
 	assert.Len(t, notes, 0)
 }
 
+func TestSearchNotesWithSnippets(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	file := NewEmptyFile("example.md")
+	parsedNote := MustParseNote("## Reference: FTS5\n\nFTS5 is an SQLite virtual table module that provides full-text search functionality to database applications.", "")
+	note := NewNote(file, nil, parsedNote)
+	err := CurrentDB().BeginTransaction()
+	require.NoError(t, err)
+	err = note.Insert()
+	require.NoError(t, err)
+	err = CurrentDB().CommitTransaction()
+	require.NoError(t, err)
+
+	results, err := CurrentRepository().SearchNotesWithSnippets("kind:reference virtual")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, note.OID, results[0].Note.OID)
+	assert.Contains(t, results[0].Snippet, "**virtual**")
+
+	// No snippet without terms
+	results, err = CurrentRepository().SearchNotesWithSnippets("kind:reference")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Snippet)
+}
+
+func TestSearchNotesByAttributes(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	// Insert a note with nested attributes
+	file := NewEmptyFile("example.md")
+	parsedNote := MustParseNote("## Reference: Go\n\nA language", "")
+	note := NewNote(file, nil, parsedNote)
+	note.Attributes = map[string]interface{}{
+		"author": map[string]interface{}{
+			"name": "Pike",
+		},
+		"languages": []interface{}{"go", "c"},
+	}
+	err := CurrentDB().BeginTransaction()
+	require.NoError(t, err)
+	err = note.Insert()
+	require.NoError(t, err)
+	err = CurrentDB().CommitTransaction()
+	require.NoError(t, err)
+
+	tests := []struct {
+		query string
+		count int
+	}{
+		{query: "@author.name:Pike", count: 1},
+		{query: "attr:author.name=Pike", count: 1},
+		{query: "attr:author.name=Thompson", count: 0},
+		{query: "attr:languages[]=go", count: 1},
+		{query: "attr:languages[]=rust", count: 0},
+		{query: "attr:author.name=Pike attr:languages[]=c", count: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			notes, err := CurrentRepository().SearchNotes(tt.query)
+			require.NoError(t, err)
+			assert.Len(t, notes, tt.count)
+		})
+	}
+}
+
+func TestSearchNotesPagination(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	// Insert notes in the reverse order of their titles
+	for i := 12; i >= 1; i-- {
+		file := NewEmptyFile("example.md")
+		note := NewNote(file, nil, MustParseNote(fmt.Sprintf("## Note: %02d\n\nPaginated", i), ""))
+		require.NoError(t, CurrentDB().BeginTransaction())
+		require.NoError(t, note.Insert())
+		require.NoError(t, CurrentDB().CommitTransaction())
+	}
+
+	titles := func(notes []*Note) []string {
+		var results []string
+		for _, note := range notes {
+			results = append(results, note.ShortTitle)
+		}
+		return results
+	}
+
+	notes, err := CurrentRepository().SearchNotes("paginated")
+	require.NoError(t, err)
+	assert.Len(t, notes, DefaultQueryLimit)
+
+	notes, err = CurrentRepository().SearchNotes("paginated sort:title limit:3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01", "02", "03"}, titles(notes))
+
+	notes, err = CurrentRepository().SearchNotes("paginated sort:title limit:5 offset:10")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"11", "12"}, titles(notes))
+}
+
+func TestSearchNotesEscaping(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	insertNote := func(path string, content string, attributes map[string]interface{}) {
+		file := NewEmptyFile(path)
+		note := NewNote(file, nil, MustParseNote(content, ""))
+		note.Attributes = attributes
+		err := CurrentDB().BeginTransaction()
+		require.NoError(t, err)
+		require.NoError(t, note.Insert())
+		require.NoError(t, CurrentDB().CommitTransaction())
+	}
+	insertNote("books/o'reilly.md", "## Reference: O'Reilly\n\nHe said \"hello\" and was 100% sure (NEAR) title:*wild* -- ^caret", map[string]interface{}{
+		"publisher": "O'Reilly",
+		"quote":     "100% more",
+	})
+	insertNote("books/100%_done.md", "## Reference: Percent\n\nDone", nil)
+	insertNote("books/1000_done.md", "## Reference: Thousand\n\nDone", nil)
+
+	tests := []struct {
+		name  string
+		query string
+		count int
+	}{
+		{name: "Single quote in term", query: `"o'reilly"`, count: 1},
+		{name: "Single quote in attribute", query: `@publisher:"O'Reilly"`, count: 1},
+		{name: "Percent in attribute", query: `@quote:"100% more"`, count: 1},
+		{name: "Percent is not a wildcard in attribute", query: `@quote:"100%"`, count: 0},
+		{name: "Injection in attribute", query: `@publisher:"x' OR '1'='1"`, count: 0},
+		{name: "Injection in kind", query: `kind:"reference') OR ('1'='1"`, count: 0},
+		{name: "Single quote in path", query: `path:"books/o'reilly"`, count: 1},
+		{name: "Percent in path", query: `path:"books/100%"`, count: 1},
+		{name: "Underscore in path", query: `path:"books/100_"`, count: 0},
+		{name: "Percent in tag", query: `#% sure`, count: 0},
+		{name: "FTS operator", query: `NEAR`, count: 1},
+		{name: "FTS column filter", query: `"title:wild"`, count: 1},
+		{name: "FTS special characters", query: `"-- ^caret"`, count: 1},
+		{name: "FTS parentheses", query: `"(NEAR)"`, count: 1},
+		{name: "FTS prefix", query: `"Reil*"`, count: 1},
+		{name: "FTS only wildcard", query: `* kind:reference`, count: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := CurrentRepository().SearchNotes(tt.query)
+			require.NoError(t, err)
+			assert.Len(t, notes, tt.count)
+		})
+	}
+}
+
+func TestSearchNotesByPaths(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	insertNote := func(path string, content string) {
+		file := NewEmptyFile(path)
+		note := NewNote(file, nil, MustParseNote(content, ""))
+		err := CurrentDB().BeginTransaction()
+		require.NoError(t, err)
+		require.NoError(t, note.Insert())
+		require.NoError(t, CurrentDB().CommitTransaction())
+	}
+	insertNote("projects/go.md", "## Note: Go\n\nA project")
+	insertNote("areas/health.md", "## Reference: Health\n\nAn area")
+	insertNote("archives/perl.md", "## Note: Perl\n\nAn archive")
+
+	tests := []struct {
+		name  string
+		query string
+		count int
+	}{
+		{name: "Single path", query: `path:projects/`, count: 1},
+		{name: "Quoted path", query: `path:"areas/"`, count: 1},
+		{name: "Multiple paths", query: `path:projects/ path:areas/`, count: 2},
+		{name: "Multiple paths and kind", query: `path:projects/ path:areas/ kind:reference`, count: 1},
+		{name: "Any kind", query: `kind:any`, count: 3},
+		{name: "Any kind with kind", query: `kind:any kind:note`, count: 3},
+		{name: "Unknown path", query: `path:resources/`, count: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := CurrentRepository().SearchNotes(tt.query)
+			require.NoError(t, err)
+			assert.Len(t, notes, tt.count)
+		})
+	}
+}
+
+func TestSearchNotesByTags(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	insertNote := func(path string, content string, tags ...string) *Note {
+		file := NewEmptyFile(path)
+		note := NewNote(file, nil, MustParseNote(content, ""))
+		note.Tags = tags
+		err := CurrentDB().BeginTransaction()
+		require.NoError(t, err)
+		require.NoError(t, note.Insert())
+		require.NoError(t, CurrentDB().CommitTransaction())
+		return note
+	}
+	insertNote("go.md", "## Note: Go\n\nShort", "go")
+	insertNote("golang.md", "## Note: Golang\n\nLong", "Golang")
+	elan := insertNote("elan.md", "## Note: Élan\n\nAccent", "Élan", "favorite")
+
+	tests := []struct {
+		name  string
+		query string
+		count int
+	}{
+		{name: "Exact membership", query: `#go`, count: 1},
+		{name: "Case-insensitive", query: `#GOLANG`, count: 1},
+		{name: "Accent-insensitive", query: `#elan`, count: 1},
+		{name: "Accent in query", query: `#Élan`, count: 1},
+		{name: "Multiple tags", query: `#elan #favorite`, count: 1},
+		{name: "Partial tag", query: `#gol`, count: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := CurrentRepository().SearchNotes(tt.query)
+			require.NoError(t, err)
+			assert.Len(t, notes, tt.count)
+		})
+	}
+
+	t.Run("Updated tags", func(t *testing.T) {
+		elan.Tags = []string{"favorite"}
+		require.NoError(t, CurrentDB().BeginTransaction())
+		require.NoError(t, elan.Update())
+		require.NoError(t, CurrentDB().CommitTransaction())
+		notes, err := CurrentRepository().SearchNotes(`#elan`)
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+
+		require.NoError(t, CurrentDB().BeginTransaction())
+		require.NoError(t, elan.Delete())
+		require.NoError(t, CurrentDB().CommitTransaction())
+		notes, err = CurrentRepository().SearchNotes(`#favorite`)
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+	})
+}
+
+func TestRunSavedSearch(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	for _, content := range []string{"## Quote: Simplicity\n\nSimple is better", "## Note: Simplicity\n\nSimple is hard"} {
+		note := NewNote(NewEmptyFile("example.md"), nil, MustParseNote(content, ""))
+		require.NoError(t, CurrentDB().BeginTransaction())
+		require.NoError(t, note.Insert())
+		require.NoError(t, CurrentDB().CommitTransaction())
+	}
+	CurrentConfig().ConfigFile.Search = map[string]*ConfigSearch{
+		"quotes": {Q: "kind:quote simple", Name: "Favorite Quotes"},
+	}
+
+	notes, err := CurrentRepository().RunSavedSearch("quotes")
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, KindQuote, notes[0].NoteKind)
+
+	_, err = CurrentRepository().RunSavedSearch("unknown")
+	require.ErrorContains(t, err, `unknown saved search "unknown"`)
+}
+
+func TestFindNotesBySourceDomain(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	MustWriteFile(t, "references.md", `# References
+
+## Quote: Wikipedia
+
+`+"`@source: https://en.wikipedia.org/wiki/Go_(programming_language)`"+`
+
+Go is a statically typed language.
+
+## Quote: Go Blog
+
+`+"`@source: https://go.dev/blog`"+`
+
+Go is fun.
+
+## Note: Internal
+
+`+"`@source: [[go#Flashcard: Golang Logo]]`"+`
+
+See the logo.
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	note, err := CurrentRepository().FindNoteByTitle("Quote: Wikipedia")
+	require.NoError(t, err)
+	require.NotNil(t, note)
+	assert.Equal(t, []*Relation{
+		{
+			SourceOID:  note.OID,
+			SourceKind: "note",
+			TargetOID:  "https://en.wikipedia.org/wiki/Go_(programming_language)",
+			TargetKind: "url",
+			Type:       "external_source",
+		},
+	}, note.Relations())
+
+	// Wikilink sources are unchanged
+	note, err = CurrentRepository().FindNoteByTitle("Note: Internal")
+	require.NoError(t, err)
+	require.NotNil(t, note)
+	relations := note.Relations()
+	require.Len(t, relations, 1)
+	assert.Equal(t, "references", relations[0].Type)
+
+	notes, err := CurrentRepository().FindNotesBySourceDomain("wikipedia.org")
+	require.NoError(t, err)
+	require.Len(t, notes, 2) // go.md already cites Wikipedia
+	var titles []string
+	for _, note := range notes {
+		titles = append(titles, note.Title)
+	}
+	assert.ElementsMatch(t, []string{"Reference: Golang History", "Quote: Wikipedia"}, titles)
+
+	notes, err = CurrentRepository().FindNotesBySourceDomain("go.dev")
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "Quote: Go Blog", notes[0].Title)
+
+	notes, err = CurrentRepository().FindNotesBySourceDomain("example.com")
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+}
+
+func TestResolveEditorLocation(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	note := MustFindNoteByPathAndTitle(t, "go.md", "Flashcard: Golang Logo")
+	expectedPath := CurrentRepository().GetAbsolutePath("go.md")
+
+	for _, query := range []string{
+		note.OID,
+		note.Slug,
+		"go#Flashcard: Golang Logo",
+		"[[go#Flashcard: Golang Logo]]",
+	} {
+		path, line, err := CurrentRepository().ResolveEditorLocation(query)
+		require.NoError(t, err, query)
+		assert.Equal(t, expectedPath, path, query)
+		assert.Equal(t, note.Line, line, query)
+	}
+
+	// Files are opened at the first line
+	path, line, err := CurrentRepository().ResolveEditorLocation("go")
+	require.NoError(t, err)
+	assert.Equal(t, expectedPath, path)
+	assert.Equal(t, 1, line)
+
+	_, _, err = CurrentRepository().ResolveEditorLocation("unknown#Note: Unknown")
+	assert.ErrorContains(t, err, "no note found")
+}
+
+func TestFindSnippets(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	insertNote := func(path string, content string) {
+		file := NewEmptyFile(path)
+		note := NewNote(file, nil, MustParseNote(content, ""))
+		err := CurrentDB().BeginTransaction()
+		require.NoError(t, err)
+		require.NoError(t, note.Insert())
+		require.NoError(t, CurrentDB().CommitTransaction())
+	}
+	insertNote("snippets/git.md", "## Snippet: Undo last commit\n\n```shell\ngit reset HEAD~1\n```")
+	insertNote("snippets/go.md", "## Snippet: Go error\n\n```go\nif err != nil {\n\treturn err\n}\n```")
+	insertNote("notes/git.md", "## Note: Undo last commit\n\nUse git reset")
+
+	snippets, err := CurrentRepository().FindSnippets("")
+	require.NoError(t, err)
+	require.Len(t, snippets, 2)
+	assert.Equal(t, "snippets/git.md", snippets[0].RelativePath)
+	assert.Equal(t, "snippets/go.md", snippets[1].RelativePath)
+
+	snippets, err = CurrentRepository().FindSnippets("commit")
+	require.NoError(t, err)
+	require.Len(t, snippets, 1)
+	assert.Equal(t, "snippets/git.md", snippets[0].RelativePath)
+	assert.Equal(t, []string{"git reset HEAD~1"}, markdown.ExtractCodeBlocks(snippets[0].ContentMarkdown))
+}
+
+func TestRandomQuote(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	insertNote := func(path string, content string) {
+		file := NewEmptyFile(path)
+		note := NewNote(file, nil, MustParseNote(content, ""))
+		err := CurrentDB().BeginTransaction()
+		require.NoError(t, err)
+		require.NoError(t, note.Insert())
+		require.NoError(t, CurrentDB().CommitTransaction())
+	}
+
+	// No quotes
+	quote, err := CurrentRepository().RandomQuote("")
+	require.NoError(t, err)
+	assert.Nil(t, quote)
+
+	insertNote("quotes/disney.md", "## Quote: Walt Disney on Doing\n\n`#favorite`\n\n> The way to get started is to quit talking and begin doing.\n> — Walt Disney")
+	insertNote("quotes/saunders.md", "## Quote: Allen Saunders on Life\n\n> Life is what happens when you're busy making other plans.\n> — Allen Saunders")
+	insertNote("notes/disney.md", "## Note: Walt Disney\n\n`#favorite`\n\nAn animator.")
+
+	for i := 0; i < 10; i++ {
+		quote, err = CurrentRepository().RandomQuote("")
+		require.NoError(t, err)
+		require.NotNil(t, quote)
+		assert.Contains(t, []string{"quotes/disney.md", "quotes/saunders.md"}, quote.RelativePath)
+	}
+
+	quote, err = CurrentRepository().RandomQuote("#favorite")
+	require.NoError(t, err)
+	require.NotNil(t, quote)
+	assert.Equal(t, "quotes/disney.md", quote.RelativePath)
+
+	quote, err = CurrentRepository().RandomQuote("plans")
+	require.NoError(t, err)
+	require.NotNil(t, quote)
+	assert.Equal(t, "quotes/saunders.md", quote.RelativePath)
+
+	quote, err = CurrentRepository().RandomQuote("#unknown")
+	require.NoError(t, err)
+	assert.Nil(t, quote)
+}
+
+func TestQuoteAttribution(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	t.Run("Author and source", func(t *testing.T) {
+		note := NewNote(NewEmptyFile("quotes.md"), nil, MustParseNote("## Quote: J.R.R. Tolkien on Life\n\n> All we have to decide is what to do with the time that is given us.\n> — J.R.R. Tolkien, _The Fellowship of the Ring_", ""))
+		assert.Equal(t, "J.R.R. Tolkien", note.GetAttributeString("author", ""))
+		assert.Equal(t, "_The Fellowship of the Ring_", note.GetAttributeString("source", ""))
+		assert.Equal(t, `<figure>
+	<blockquote>
+		<p>All we have to decide is what to do with the time that is given us.</p>
+	</blockquote>
+	<figcaption>— J.R.R. Tolkien <cite><em>The Fellowship of the Ring</em></cite></figcaption>
+</figure>`, note.ContentHTML)
+	})
+
+	t.Run("Author only", func(t *testing.T) {
+		note := NewNote(NewEmptyFile("quotes.md"), nil, MustParseNote("## Quote: Walt Disney on Doing\n\n> The way to get started is to quit talking and begin doing.\n> -- Walt Disney", ""))
+		assert.Equal(t, "Walt Disney", note.GetAttributeString("author", ""))
+		assert.False(t, note.HasAttribute("source"))
+	})
+
+	t.Run("Explicit attributes", func(t *testing.T) {
+		note := NewNote(NewEmptyFile("quotes.md"), nil, MustParseNote("## Quote: Walt Disney on Doing\n\n`@author: Walter Elias Disney`\n\n> The way to get started is to quit talking and begin doing.\n> — Walt Disney, _Interview_", ""))
+		assert.Equal(t, "Walter Elias Disney", note.GetAttributeString("author", ""))
+		assert.Equal(t, "_Interview_", note.GetAttributeString("source", ""))
+	})
+
+	t.Run("Not a quote", func(t *testing.T) {
+		note := NewNote(NewEmptyFile("notes.md"), nil, MustParseNote("## Note: Walt Disney on Doing\n\n> The way to get started is to quit talking and begin doing.\n> — Walt Disney", ""))
+		assert.False(t, note.HasAttribute("author"))
+	})
+}
+
+func TestFindChecklists(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestNoteFTS")
+
+	file := NewEmptyFile("checklists/travel.md")
+	note := NewNote(file, nil, MustParseNote("## Checklist: Travel\n\n* [x] Passport\n* [ ] Charger\n* [ ] Tickets", ""))
+	assert.Equal(t, 1, note.ItemsDone)
+	assert.Equal(t, 3, note.ItemsTotal)
+	require.NoError(t, CurrentDB().BeginTransaction())
+	require.NoError(t, note.Insert())
+	require.NoError(t, CurrentDB().CommitTransaction())
+
+	checklists, err := CurrentRepository().FindChecklists()
+	require.NoError(t, err)
+	require.Len(t, checklists, 1)
+	assert.Equal(t, KindChecklist, checklists[0].NoteKind)
+	assert.Equal(t, 1, checklists[0].ItemsDone)
+	assert.Equal(t, 3, checklists[0].ItemsTotal)
+
+	stats, err := CurrentRepository().StatsChecklists()
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, 33, stats[0].Percent())
+}
+
+func TestResolveOIDLinks(t *testing.T) {
+	root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	require.NoError(t, CurrentRepository().Add("."))
+
+	note := MustFindNoteByPathAndTitle(t, "go.md", "Flashcard: Golang Logo")
+	assert.Contains(t, note.FormatToMarkdown(), "oid:")
+	assert.Contains(t, note.FormatToHTML(), "<media oid=")
+
+	md := CurrentRepository().ResolveOIDLinks(note.FormatToMarkdown())
+	assert.NotContains(t, md, "oid:")
+	assert.Contains(t, md, filepath.Join(root, "medias/go.svg"))
+
+	html := CurrentRepository().ResolveOIDLinks(note.FormatToHTML())
+	assert.NotContains(t, html, "oid=")
+	assert.Contains(t, html, fmt.Sprintf(`<img src="%s" alt="Logo" />`, filepath.Join(root, "medias/go.svg")))
+
+	// Unknown medias are left untouched
+	assert.Equal(t, "![](oid:4044044044044044044044044044044044044040)", CurrentRepository().ResolveOIDLinks("![](oid:4044044044044044044044044044044044044040)"))
+}
+
+func TestFTSMatchExpression(t *testing.T) {
+	assert.Equal(t, `"go" AND "full-text"`, ftsMatchExpression([]string{"go", "full-text"}))
+	assert.Equal(t, `"say ""hi"""`, ftsMatchExpression([]string{`say "hi"`}))
+	assert.Equal(t, `"gor"*`, ftsMatchExpression([]string{"gor*"}))
+	assert.Equal(t, ``, ftsMatchExpression([]string{"*"}))
+	assert.Equal(t, `"NEAR" AND "a:b"`, ftsMatchExpression([]string{"NEAR", "a:b"}))
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%`, escapeLike("100%"))
+	assert.Equal(t, `a\_b\\c`, escapeLike(`a_b\c`))
+}
+
 func TestNote(t *testing.T) {
 
 	t.Run("YAML", func(t *testing.T) {
@@ -471,6 +1024,8 @@ content_html: |-
     <li><input type="checkbox" /> Test</li>
     </ul>
 content_text: '* [ ] Test'
+items_total: 1
+word_count: 1
 created_at: 2023-01-01T01:12:30Z
 updated_at: 2023-01-01T01:12:30Z
 `), strings.TrimSpace(noteYAML))
@@ -524,6 +1079,242 @@ func TestFormatLongTitle(t *testing.T) {
 	}
 }
 
+func TestConfigLongTitleFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  ConfigLongTitle // input
+		titles    []string        // input
+		longTitle string          // output
+	}{
+		{
+			name:      "Default",
+			settings:  ConfigLongTitle{},
+			titles:    []string{"Go", "Concurrency", "Goroutines"},
+			longTitle: "Go / Concurrency / Goroutines",
+		},
+		{
+			name:      "Custom separator",
+			settings:  ConfigLongTitle{Separator: " > "},
+			titles:    []string{"Go", "Concurrency", "Goroutines"},
+			longTitle: "Go > Concurrency > Goroutines",
+		},
+		{
+			name:      "Max segments",
+			settings:  ConfigLongTitle{MaxSegments: 2},
+			titles:    []string{"Go", "Concurrency", "Goroutines"},
+			longTitle: "Concurrency / Goroutines",
+		},
+		{
+			name:      "Max segments after deduplication",
+			settings:  ConfigLongTitle{MaxSegments: 2},
+			titles:    []string{"Go", "Go History", "Go History"},
+			longTitle: "Go History",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.settings.Format(tt.titles...)
+			assert.Equal(t, tt.longTitle, actual)
+		})
+	}
+}
+
+func TestFuzzyFindNotes(t *testing.T) {
+	history := &Note{LongTitle: "Go / History", Wikilink: "go#Note: History"}
+	goroutines := &Note{LongTitle: "Go / Goroutines", Wikilink: "go#Note: Goroutines"}
+	rust := &Note{LongTitle: "Rust / Ownership", Wikilink: "rust#Note: Ownership"}
+	notes := []*Note{goroutines, rust, history}
+
+	assert.Equal(t, notes, FuzzyFindNotes(notes, ""))
+	assert.Equal(t, []*Note{history}, FuzzyFindNotes(notes, "go hist"))
+	assert.Equal(t, []*Note{goroutines, history}, FuzzyFindNotes(notes, "gor"))
+	assert.Equal(t, []*Note{rust}, FuzzyFindNotes(notes, "rust#own"))
+	assert.Empty(t, FuzzyFindNotes(notes, "python"))
+}
+
+func TestFindNotesByTag(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	MustWriteFile(t, "go.md", `---
+tags: [go]
+---
+
+# Go
+
+## Note: Goroutines
+
+`+"`#concurrency`"+`
+
+## Note: History
+
+`+"`#History`"+`
+`)
+	MustWriteFile(t, "golang.md", `# Golang
+
+## Note: Gophers
+
+`+"`#golang` `#history`"+`
+`)
+	require.NoError(t, CurrentRepository().Add("."))
+
+	titles := func(notes []*Note, err error) []string {
+		require.NoError(t, err)
+		var results []string
+		for _, note := range notes {
+			results = append(results, note.Title)
+		}
+		return results
+	}
+
+	// Inherited tags match and boundaries are respected (go != golang)
+	assert.Equal(t, []string{"Note: Goroutines", "Note: History"}, titles(CurrentRepository().FindNotesByTag("go")))
+	// Case is ignored
+	assert.Equal(t, []string{"Note: History", "Note: Gophers"}, titles(CurrentRepository().FindNotesByTag("HISTORY")))
+	assert.Empty(t, titles(CurrentRepository().FindNotesByTag("hist")))
+
+	assert.Equal(t, []string{"Note: History"}, titles(CurrentRepository().FindNotesByAllTags("go", "history", "go")))
+	assert.Empty(t, titles(CurrentRepository().FindNotesByAllTags("concurrency", "history")))
+	assert.Equal(t, []string{"Note: Goroutines", "Note: Gophers"}, titles(CurrentRepository().FindNotesByAnyTag("concurrency", "golang")))
+	assert.Empty(t, titles(CurrentRepository().FindNotesByAnyTag()))
+}
+
+func TestDetermineNoteSlug(t *testing.T) {
+	tests := []struct {
+		name          string
+		strategy      string // input
+		attributeSlug string // input
+		slug          string // output
+	}{
+		{
+			name:     "Default",
+			strategy: "",
+			slug:     "go-reference-history",
+		},
+		{
+			name:     "Path and title",
+			strategy: SlugStrategyPathTitle,
+			slug:     "go-reference-history",
+		},
+		{
+			name:     "Path",
+			strategy: SlugStrategyPath,
+			slug:     "go-reference",
+		},
+		{
+			name:     "Title",
+			strategy: SlugStrategyTitle,
+			slug:     "reference-history",
+		},
+		{
+			name:          "Attribute",
+			strategy:      SlugStrategyTitle,
+			attributeSlug: "custom",
+			slug:          "custom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetUpRepositoryFromTempDir(t)
+			CurrentConfig().ConfigFile.Core.SlugStrategy = tt.strategy
+			actual := DetermineNoteSlug("go", tt.attributeSlug, KindReference, "History")
+			assert.Equal(t, tt.slug, actual)
+		})
+	}
+}
+
+func TestSlugDisambiguation(t *testing.T) {
+	existing := map[string]bool{"go": true, "go-2": true}
+	exists := func(slug string) bool { return existing[slug] }
+	assert.Equal(t, "go-3", DisambiguateSlug("go", exists))
+	assert.Equal(t, "python", DisambiguateSlug("python", exists))
+
+	SetUpRepositoryFromTempDir(t)
+	CurrentConfig().ConfigFile.Core.SlugStrategy = SlugStrategyTitle
+	CurrentConfig().ConfigFile.Core.SlugDisambiguation = true
+
+	content := `# Golang
+
+## Reference: History
+
+Go was created at Google.
+`
+	MustWriteFile(t, "go.md", content)
+	MustWriteFile(t, "golang.md", content)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	noteGo := MustFindNoteByPathAndTitle(t, "go.md", "Reference: History")
+	noteGolang := MustFindNoteByPathAndTitle(t, "golang.md", "Reference: History")
+	assert.ElementsMatch(t, []string{"reference-history", "reference-history-2"}, []string{noteGo.Slug, noteGolang.Slug})
+
+	// Slugs must be stable between runs
+	err = CurrentRepository().Add(".")
+	require.NoError(t, err)
+	assert.Equal(t, noteGo.Slug, MustFindNoteByPathAndTitle(t, "go.md", "Reference: History").Slug)
+	assert.Equal(t, noteGolang.Slug, MustFindNoteByPathAndTitle(t, "golang.md", "Reference: History").Slug)
+}
+
+func TestNormalizeHash(t *testing.T) {
+	content := `# Golang
+
+## Reference: History
+
+Go was designed at Google in 2007 to improve programming productivity.
+
+* Robert Griesemer
+* Rob Pike
+`
+	reformattedContent := `# Golang
+
+## Reference: History
+
+Go was designed at Google in 2007
+to improve   programming productivity.
+
+- Robert Griesemer
+-   Rob Pike
+`
+
+	updateFile := func(t *testing.T, content string, now time.Time) *Note {
+		FreezeAt(t, now)
+		MustWriteFile(t, "go.md", content)
+		err := CurrentRepository().Add(".")
+		require.NoError(t, err)
+		return MustFindNoteByPathAndTitle(t, "go.md", "Reference: History")
+	}
+
+	day1 := time.Date(2023, time.Month(1), 1, 1, 12, 30, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+
+	t.Run("Disabled", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+
+		note := updateFile(t, content, day1)
+		assert.WithinDuration(t, day1, note.UpdatedAt, time.Second)
+		note = updateFile(t, reformattedContent, day2)
+		assert.WithinDuration(t, day2, note.UpdatedAt, time.Second)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+		CurrentConfig().ConfigFile.Core.NormalizeHash = true
+
+		note := updateFile(t, content, day1)
+		assert.WithinDuration(t, day1, note.UpdatedAt, time.Second)
+
+		// Formatting-only changes are ignored
+		reformattedNote := updateFile(t, reformattedContent, day2)
+		assert.WithinDuration(t, day1, reformattedNote.UpdatedAt, time.Second)
+		assert.Equal(t, note.Hash, reformattedNote.Hash)
+
+		// Other changes are still detected
+		note = updateFile(t, strings.ReplaceAll(content, "2007", "2009"), day3)
+		assert.WithinDuration(t, day3, note.UpdatedAt, time.Second)
+		assert.NotEqual(t, reformattedNote.Hash, note.Hash)
+	})
+}
+
 /* Test Helpers */
 
 // cleanNote ignore some values as EqualValues is very strict.
@@ -537,3 +1328,49 @@ func cleanNote(n *Note) *Note {
 	}
 	return n
 }
+
+func TestFindMatchingNote(t *testing.T) {
+
+	t.Run("Renamed heading", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+		MustWriteFile(t, "go.md", "# Go\n\n## Note: Creator\n\nRob Pike\n\n## Note: Mascot\n\nGopher\n")
+		require.NoError(t, CurrentRepository().Add("."))
+		before := MustFindNoteByPathAndTitle(t, "go.md", "Note: Creator")
+
+		MustWriteFile(t, "go.md", "# Go\n\n## Note: Creators\n\nRob Pike\n\n## Note: Mascot\n\nGopher\n")
+		require.NoError(t, CurrentRepository().Add("."))
+		after := MustFindNoteByPathAndTitle(t, "go.md", "Note: Creators")
+		assert.Equal(t, before.OID, after.OID)
+		count, err := CurrentRepository().CountNotes()
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Swapped titles", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+		MustWriteFile(t, "go.md", "# Go\n\n## Note: A\n\nFirst\n\n## Note: B\n\nSecond\n")
+		require.NoError(t, CurrentRepository().Add("."))
+		first := MustFindNoteByPathAndTitle(t, "go.md", "Note: A")
+		second := MustFindNoteByPathAndTitle(t, "go.md", "Note: B")
+
+		// Notes are matched by content before titles
+		MustWriteFile(t, "go.md", "# Go\n\n## Note: A\n\nSecond\n\n## Note: B\n\nFirst\n")
+		require.NoError(t, CurrentRepository().Add("."))
+		assert.Equal(t, second.OID, MustFindNoteByPathAndTitle(t, "go.md", "Note: A").OID)
+		assert.Equal(t, first.OID, MustFindNoteByPathAndTitle(t, "go.md", "Note: B").OID)
+	})
+
+	t.Run("Copied note", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+		MustWriteFile(t, "go.md", "# Go\n\n## Note: Original\n\nGopher\n")
+		require.NoError(t, CurrentRepository().Add("."))
+		original := MustFindNoteByPathAndTitle(t, "go.md", "Note: Original")
+
+		// The copy must not steal the OID of the unchanged note
+		MustWriteFile(t, "go.md", "# Go\n\n## Note: Copy\n\nGopher\n\n## Note: Original\n\nGopher\n")
+		require.NoError(t, CurrentRepository().Add("."))
+		assert.Equal(t, original.OID, MustFindNoteByPathAndTitle(t, "go.md", "Note: Original").OID)
+		copied := MustFindNoteByPathAndTitle(t, "go.md", "Note: Copy")
+		assert.NotEqual(t, original.OID, copied.OID)
+	})
+}
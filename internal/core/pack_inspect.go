@@ -0,0 +1,110 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/internal/helpers"
+)
+
+// PackFileInfo describes the content of a pack file on disk.
+type PackFileInfo struct {
+	OID string `json:"oid"`
+	// Relative path from the repository root (ex: .nt/objects/9c/9c8a...)
+	RelativePath string            `json:"relativePath"`
+	MTime        time.Time         `json:"mtime"`
+	Size         int64             `json:"size"`
+	Objects      []*PackObjectInfo `json:"objects"`
+	// Blobs referenced by the objects (without duplicates)
+	Blobs []*BlobRef `json:"blobs"`
+}
+
+// PackObjectInfo describes a single object inside a pack file.
+type PackObjectInfo struct {
+	OID         string `json:"oid"`
+	Kind        string `json:"kind"`
+	State       State  `json:"state"`
+	Description string `json:"description"`
+	// Size of the object data once decompressed
+	Size int `json:"size"`
+}
+
+// InspectPackFile reads a pack file to describe its content.
+func (r *Repository) InspectPackFile(oid string) (*PackFileInfo, error) {
+	relativePath := filepath.Join(".nt/objects", OIDToPath(oid))
+	stat, err := os.Stat(filepath.Join(CurrentConfig().RootDirectory, relativePath))
+	if err != nil {
+		return nil, err
+	}
+	packFile, err := CurrentDB().ReadPackFile(oid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PackFileInfo{
+		OID:          packFile.OID,
+		RelativePath: relativePath,
+		MTime:        stat.ModTime(),
+		Size:         stat.Size(),
+	}
+	blobsFound := make(map[string]bool)
+	for _, packObject := range packFile.PackObjects {
+		data, err := packObject.Data.decompress()
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress object %s: %w", packObject.OID, err)
+		}
+		result.Objects = append(result.Objects, &PackObjectInfo{
+			OID:         packObject.OID,
+			Kind:        packObject.Kind,
+			State:       packObject.State,
+			Description: packObject.Description,
+			Size:        len(data),
+		})
+		for _, blobRef := range packObject.ReadObject().Blobs() {
+			if blobsFound[blobRef.OID] {
+				continue
+			}
+			blobsFound[blobRef.OID] = true
+			result.Blobs = append(result.Blobs, blobRef)
+		}
+	}
+	return result, nil
+}
+
+// VerifyPackFile checks a pack file is consistent and returns the problems found.
+// Pack file OIDs are generated randomly and cannot be recomputed. Instead, the OID
+// declared inside the pack file must match its file name, every object must be decodable,
+// and blobs present locally must hash to their OID.
+func (r *Repository) VerifyPackFile(oid string) ([]string, error) {
+	packFile, err := CurrentDB().ReadPackFile(oid)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	if packFile.OID != oid {
+		problems = append(problems, fmt.Sprintf("pack file %s declares OID %s", oid, packFile.OID))
+	}
+	for _, packObject := range packFile.PackObjects {
+		if _, err := packObject.Data.decompress(); err != nil {
+			problems = append(problems, fmt.Sprintf("object %s cannot be decompressed: %v", packObject.OID, err))
+			continue
+		}
+		for _, blobRef := range packObject.ReadObject().Blobs() {
+			if !CurrentDB().BlobExists(blobRef.OID) {
+				// Blobs can be missing locally after a partial pull
+				continue
+			}
+			data, err := CurrentDB().ReadBlob(blobRef.OID)
+			if err != nil {
+				return nil, err
+			}
+			if hash := helpers.Hash(data); hash != blobRef.OID {
+				problems = append(problems, fmt.Sprintf("blob %s has hash %s", blobRef.OID, hash))
+			}
+		}
+	}
+	return problems, nil
+}
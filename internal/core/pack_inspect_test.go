@@ -0,0 +1,65 @@
+package core
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectPackFile(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+	err = CurrentDB().Commit("initial commit")
+	require.NoError(t, err)
+
+	require.Len(t, CurrentDB().index.PackFiles, 1)
+	var packFileOID string
+	for oid := range CurrentDB().index.PackFiles {
+		packFileOID = oid
+	}
+
+	logo, err := CurrentRepository().FindMediaByRelativePath("medias/go.svg")
+	require.NoError(t, err)
+	require.NotNil(t, logo)
+	blobOID := logo.BlobRefs[0].OID
+
+	info, err := CurrentRepository().InspectPackFile(packFileOID)
+	require.NoError(t, err)
+	assert.Equal(t, packFileOID, info.OID)
+	assert.Equal(t, ".nt/objects/"+OIDToPath(packFileOID), info.RelativePath)
+	assert.Greater(t, info.Size, int64(0))
+	require.NotEmpty(t, info.Objects)
+	var kinds []string
+	for _, object := range info.Objects {
+		assert.Greater(t, object.Size, 0)
+		kinds = append(kinds, object.Kind)
+	}
+	assert.Contains(t, kinds, "file")
+	assert.Contains(t, kinds, "note")
+	assert.Contains(t, kinds, "media")
+	var blobOIDs []string
+	for _, blob := range info.Blobs {
+		blobOIDs = append(blobOIDs, blob.OID)
+	}
+	assert.Contains(t, blobOIDs, blobOID)
+
+	problems, err := CurrentRepository().VerifyPackFile(packFileOID)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// Corrupt a blob
+	err = os.WriteFile(CurrentDB().blobPath(blobOID), []byte("corrupted"), 0644)
+	require.NoError(t, err)
+	problems, err = CurrentRepository().VerifyPackFile(packFileOID)
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], blobOID)
+
+	// Unknown pack file
+	_, err = CurrentRepository().InspectPackFile("4f9e8e8dd1d1ccd2fb5a2a0e0fb1a1e0d9d2c3f1")
+	assert.Error(t, err)
+}
@@ -2,22 +2,57 @@ package core
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/scanner"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+// Attribute paths support nested attributes (ex: author.name) and array membership (ex: tags[]).
+var regexAttributePath = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*(\.[a-zA-Z_][a-zA-Z0-9_-]*)*(\[\])?$`)
+
+// Supported sort orders for queries
+const (
+	SortRank    = "rank"
+	SortCreated = "created"
+	SortUpdated = "updated"
+	SortTitle   = "title"
+)
+
+// Kind matching all notes (same as omitting kind:)
+const QueryKindAny = "any"
+
+const (
+	// DefaultQueryLimit is the number of results when no limit is specified
+	DefaultQueryLimit = 10
+	// MaxQueryLimit is the maximum number of results of a single query
+	MaxQueryLimit = 1000
 )
 
 type Query struct {
 	Kinds      []string
 	Tags       []string
 	Attributes map[string]interface{}
-	Path       string
-	Terms      []string
+	// Path prefixes (a note must be present under one of them)
+	Paths  []string
+	Terms  []string
+	Sort   string
+	Limit  int
+	Offset int
+	// Slug of a note to search related notes (see FindRelatedNotes)
+	Related string
 }
 
 // NewQuery instantiates a new query.
 func NewQuery() *Query {
 	return &Query{
 		Attributes: make(map[string]interface{}),
+		Sort:       SortRank,
+		Limit:      DefaultQueryLimit,
 	}
 }
 
@@ -29,9 +64,13 @@ func ParseQuery(q string) (*Query, error) {
 	s.Init(strings.NewReader(q))
 	s.Filename = ""
 
+	anyKind := false
 	for {
 		token := s.Scan()
 		if token == scanner.EOF {
+			if anyKind {
+				result.Kinds = nil
+			}
 			return result, nil
 		}
 		switch s.TokenText() {
@@ -47,6 +86,10 @@ func ParseQuery(q string) (*Query, error) {
 			if kindToken == scanner.EOF {
 				return nil, errors.New("unexpected EOF when a kind value was expected")
 			}
+			if s.TokenText() == QueryKindAny {
+				anyKind = true
+				continue
+			}
 			result.Kinds = append(result.Kinds, s.TokenText())
 
 		case "path":
@@ -60,7 +103,85 @@ func ParseQuery(q string) (*Query, error) {
 			if pathToken == scanner.EOF {
 				return nil, errors.New("unexpected EOF when a path was expected")
 			}
-			result.Path = strings.TrimRight(strings.TrimLeft(s.TokenText(), `"`), `"`)
+			path := s.TokenText()
+			if !strings.HasPrefix(path, `"`) {
+				// Unquoted paths end at the next space (ex: path:projects/)
+				for {
+					v := s.Peek()
+					if v == scanner.EOF || unicode.IsSpace(v) {
+						break
+					}
+					path += string(s.Next())
+				}
+			}
+			result.Paths = append(result.Paths, strings.TrimRight(strings.TrimLeft(path, `"`), `"`))
+
+		case "related":
+			// Related notes
+			colonToken := s.Scan()
+			if colonToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when : was expected")
+			}
+
+			slugToken := s.Scan()
+			if slugToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when a slug was expected")
+			}
+			slug := s.TokenText()
+			for {
+				// Slugs contain - (ex: go-reference-history)
+				v := s.Peek()
+				if v == scanner.EOF || v != '-' {
+					break
+				}
+				s.Scan() // advance -
+				partToken := s.Scan()
+				if partToken == scanner.EOF {
+					return nil, errors.New("unexpected EOF in the middle of a slug")
+				}
+				slug += "-" + s.TokenText()
+			}
+			result.Related = slug
+
+		case "sort":
+			// Sort
+			colonToken := s.Scan()
+			if colonToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when : was expected")
+			}
+
+			sortToken := s.Scan()
+			if sortToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when a sort value was expected")
+			}
+			sort := s.TokenText()
+			if !slices.Contains([]string{SortRank, SortCreated, SortUpdated, SortTitle}, sort) {
+				return nil, fmt.Errorf("unsupported sort %q", sort)
+			}
+			result.Sort = sort
+
+		case "limit", "offset":
+			// Pagination
+			name := s.TokenText()
+			colonToken := s.Scan()
+			if colonToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when : was expected")
+			}
+
+			valueToken := s.Scan()
+			if valueToken == scanner.EOF {
+				return nil, fmt.Errorf("unexpected EOF when a %s value was expected", name)
+			}
+			value, err := strconv.Atoi(s.TokenText())
+			if err != nil || value < 0 || (name == "limit" && value == 0) {
+				return nil, fmt.Errorf("invalid %s %q", name, s.TokenText())
+			}
+			if name == "limit" {
+				// Clamp to not load the whole database
+				result.Limit = min(value, MaxQueryLimit)
+			} else {
+				result.Offset = value
+			}
 
 		case "#":
 			// Tag
@@ -85,17 +206,39 @@ func ParseQuery(q string) (*Query, error) {
 
 		case "@":
 			// Attribute
-			attributeNameToken := s.Scan()
-			if attributeNameToken == scanner.EOF {
-				return nil, errors.New("unexpected EOF when a tag name was expected")
+			attributeName, err := scanAttributePath(&s)
+			if err != nil {
+				return nil, err
+			}
+
+			colonToken := s.Scan()
+			if colonToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when : was expected")
+			}
+
+			attributeValueToken := s.Scan()
+			if attributeValueToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when an attribute value was expected")
 			}
-			attributeName := s.TokenText()
+			result.Attributes[attributeName] = strings.TrimRight(strings.TrimLeft(s.TokenText(), `"`), `"`)
 
+		case "attr":
+			// Attribute (alternative syntax attr:name=value)
 			colonToken := s.Scan()
 			if colonToken == scanner.EOF {
 				return nil, errors.New("unexpected EOF when : was expected")
 			}
 
+			attributeName, err := scanAttributePath(&s)
+			if err != nil {
+				return nil, err
+			}
+
+			equalToken := s.Scan()
+			if equalToken == scanner.EOF {
+				return nil, errors.New("unexpected EOF when = was expected")
+			}
+
 			attributeValueToken := s.Scan()
 			if attributeValueToken == scanner.EOF {
 				return nil, errors.New("unexpected EOF when an attribute value was expected")
@@ -109,3 +252,51 @@ func ParseQuery(q string) (*Query, error) {
 		}
 	}
 }
+
+// scanAttributePath reads an attribute path like "author.name" or "tags[]".
+func scanAttributePath(s *scanner.Scanner) (string, error) {
+	attributeNameToken := s.Scan()
+	if attributeNameToken == scanner.EOF {
+		return "", errors.New("unexpected EOF when an attribute name was expected")
+	}
+	path := s.TokenText()
+	for {
+		v := s.Peek()
+		if v == '.' || v == '-' {
+			s.Scan() // advance . or -
+			partToken := s.Scan()
+			if partToken == scanner.EOF {
+				return "", errors.New("unexpected EOF in the middle of an attribute name")
+			}
+			path += string(v) + s.TokenText()
+			continue
+		}
+		if v == '[' {
+			s.Scan() // advance [
+			if s.Scan() != ']' {
+				return "", errors.New("] was expected after [ in attribute name")
+			}
+			path += "[]"
+		}
+		break
+	}
+	if !regexAttributePath.MatchString(path) {
+		return "", fmt.Errorf("invalid attribute path %q", path)
+	}
+	return path, nil
+}
+
+// attributeJSONPath converts an attribute path to a JSON path (ex: author.name => $."author"."name").
+// The path must have been validated first.
+func attributeJSONPath(path string) (jsonPath string, array bool) {
+	array = strings.HasSuffix(path, "[]")
+	path = strings.TrimSuffix(path, "[]")
+	var result strings.Builder
+	result.WriteString("$")
+	for _, part := range strings.Split(path, ".") {
+		result.WriteString(`."`)
+		result.WriteString(part)
+		result.WriteString(`"`)
+	}
+	return result.String(), array
+}
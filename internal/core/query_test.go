@@ -42,6 +42,16 @@ if a > 10 {
 	assert.EqualValues(t, expected, tokens)
 }
 
+func TestAttributeJSONPath(t *testing.T) {
+	jsonPath, array := attributeJSONPath("author.name")
+	assert.Equal(t, `$."author"."name"`, jsonPath)
+	assert.False(t, array)
+
+	jsonPath, array = attributeJSONPath("tags[]")
+	assert.Equal(t, `$."tags"`, jsonPath)
+	assert.True(t, array)
+}
+
 func TestGoTextScannerWithQuery(t *testing.T) {
 	// Same as above but with a specific query
 	const src = `#tag subject @title:"Note Title"`
@@ -68,7 +78,7 @@ func TestParseQuery(t *testing.T) {
 		q := `#favorite keyword1 kind:note kind:flashcard @title:"Note Title" path:"projects/toto" "keyword 2" #life-changing @name:Epictectus`
 		query, err := ParseQuery(q)
 		require.NoError(t, err)
-		assert.Equal(t, "projects/toto", query.Path)
+		assert.Equal(t, []string{"projects/toto"}, query.Paths)
 		assert.EqualValues(t, []string{"note", "flashcard"}, query.Kinds)
 		assert.EqualValues(t, []string{"favorite", "life-changing"}, query.Tags)
 		assert.EqualValues(t, map[string]interface{}{
@@ -78,9 +88,68 @@ func TestParseQuery(t *testing.T) {
 		assert.EqualValues(t, []string{"keyword1", "keyword 2"}, query.Terms)
 	})
 
+	t.Run("Paths and kinds", func(t *testing.T) {
+		query, err := ParseQuery(`path:projects/ path:"areas/health" kind:any kind:note go`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"projects/", "areas/health"}, query.Paths)
+		assert.Empty(t, query.Kinds)
+		assert.EqualValues(t, []string{"go"}, query.Terms)
+	})
+
+	t.Run("Nested attributes", func(t *testing.T) {
+		q := `@author.name:Pike attr:tags[]=go attr:source-url="https://go.dev" @book.first-edition.year:2015`
+		query, err := ParseQuery(q)
+		require.NoError(t, err)
+		assert.EqualValues(t, map[string]interface{}{
+			"author.name":             "Pike",
+			"tags[]":                  "go",
+			"source-url":              "https://go.dev",
+			"book.first-edition.year": "2015",
+		}, query.Attributes)
+		assert.Empty(t, query.Terms)
+	})
+
+	t.Run("Related", func(t *testing.T) {
+		query, err := ParseQuery("related:go-reference-history go")
+		require.NoError(t, err)
+		assert.Equal(t, "go-reference-history", query.Related)
+		assert.EqualValues(t, []string{"go"}, query.Terms)
+	})
+
+	t.Run("Sort and pagination", func(t *testing.T) {
+		query, err := ParseQuery("go")
+		require.NoError(t, err)
+		assert.Equal(t, SortRank, query.Sort)
+		assert.Equal(t, DefaultQueryLimit, query.Limit)
+		assert.Equal(t, 0, query.Offset)
+
+		query, err = ParseQuery("go sort:updated limit:50 offset:100")
+		require.NoError(t, err)
+		assert.Equal(t, SortUpdated, query.Sort)
+		assert.Equal(t, 50, query.Limit)
+		assert.Equal(t, 100, query.Offset)
+		assert.EqualValues(t, []string{"go"}, query.Terms)
+
+		// Limit is clamped
+		query, err = ParseQuery("limit:100000")
+		require.NoError(t, err)
+		assert.Equal(t, MaxQueryLimit, query.Limit)
+
+		_, err = ParseQuery("sort:size")
+		require.ErrorContains(t, err, "unsupported sort")
+		_, err = ParseQuery("limit:0")
+		require.ErrorContains(t, err, "invalid limit")
+		_, err = ParseQuery("offset:-1")
+		require.ErrorContains(t, err, "invalid offset")
+	})
+
 	t.Run("Invalid", func(t *testing.T) {
 		_, err := ParseQuery("#")
 		require.ErrorContains(t, err, "unexpected EOF")
+		_, err = ParseQuery("@tags[:go")
+		require.ErrorContains(t, err, "] was expected")
+		_, err = ParseQuery("attr:author.=Pike")
+		require.ErrorContains(t, err, "invalid attribute path")
 	})
 
 }
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// Marker present while a commit is being written to disk
+const CommitInProgressFilename = "COMMIT_IN_PROGRESS"
+
+// RecoveryAction describes how an interrupted commit was recovered.
+type RecoveryAction string
+
+const (
+	// No interrupted commit was found
+	RecoveryNone RecoveryAction = "none"
+	// The index was saved. Remaining steps were replayed.
+	RecoveryReplayed RecoveryAction = "replayed"
+	// The index was not saved. Pack files were removed and changes are still staged.
+	RecoveryRolledBack RecoveryAction = "rolled back"
+)
+
+// commitInProgressPath returns the path of the marker file.
+func commitInProgressPath() string {
+	return filepath.Join(CurrentConfig().RootDirectory, ".nt", CommitInProgressFilename)
+}
+
+// startCommit writes the marker file describing the commit to write.
+func startCommit(commit *Commit) error {
+	return filesystem.WriteFileAtomic(commitInProgressPath(), func(w io.Writer) error {
+		return yaml.NewEncoder(w).Encode(commit)
+	})
+}
+
+// endCommit removes the marker file once all files of the commit were written.
+func endCommit() error {
+	return os.Remove(commitInProgressPath())
+}
+
+// readCommitInProgress returns the commit described in the marker file, or nil if no commit is in progress.
+func readCommitInProgress() (*Commit, error) {
+	data, err := os.ReadFile(commitInProgressPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	commit := new(Commit)
+	if err := yaml.Unmarshal(data, commit); err != nil {
+		return nil, fmt.Errorf("invalid %s file: %v", CommitInProgressFilename, err)
+	}
+	return commit, nil
+}
+
+// CommitInProgress returns true if a previous commit was interrupted.
+func (db *DB) CommitInProgress() bool {
+	_, err := os.Stat(commitInProgressPath())
+	return err == nil
+}
+
+// Recover completes or cancels a commit interrupted before all files were written.
+//
+// The index is the reference. When the index includes the commit, remaining
+// steps (commit graph, refs) are replayed. Otherwise, the new pack files are
+// removed and the changes remain in the staging area to be committed again.
+func (db *DB) Recover() (RecoveryAction, error) {
+	commit, err := readCommitInProgress()
+	if err != nil {
+		return RecoveryNone, err
+	}
+	if commit == nil {
+		return RecoveryNone, nil
+	}
+
+	// Only the saved index can tell if the commit completed
+	index := ReadIndex()
+
+	if indexIncludesCommit(index, commit) {
+		if !db.commitGraphIncludesCommit(commit.OID) {
+			if err := db.commitGraph.AppendCommit(commit); err != nil {
+				return RecoveryNone, err
+			}
+			if err := db.commitGraph.Save(); err != nil {
+				return RecoveryNone, err
+			}
+		}
+		if err := db.updateRef("main", commit.OID); err != nil {
+			return RecoveryNone, err
+		}
+		CurrentLogger().Infof("Replayed interrupted commit %s", commit.OID)
+		return RecoveryReplayed, endCommit()
+	}
+
+	for _, packFileRef := range commit.PackFiles {
+		path := filepath.Join(CurrentConfig().RootDirectory, ".nt/objects", OIDToPath(packFileRef.OID))
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return RecoveryNone, err
+		}
+	}
+	// Changes must be staged again
+	db.index = index
+	CurrentLogger().Infof("Rolled back interrupted commit %s", commit.OID)
+	return RecoveryRolledBack, endCommit()
+}
+
+// indexIncludesCommit returns true if the pack files of the commit were registered in the index.
+func indexIncludesCommit(index *Index, commit *Commit) bool {
+	for _, packFileRef := range commit.PackFiles {
+		if index.PackFiles[packFileRef.OID] != commit.OID {
+			return false
+		}
+	}
+	return true
+}
+
+// commitGraphIncludesCommit returns true if the commit was appended to the commit graph.
+func (db *DB) commitGraphIncludesCommit(commitOID string) bool {
+	for _, commit := range db.commitGraph.Commits {
+		if commit.OID == commitOID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+
+	t.Run("Nothing to recover", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		err := CurrentRepository().Add("go.md")
+		require.NoError(t, err)
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+		assert.False(t, CurrentDB().CommitInProgress())
+
+		action, err := CurrentDB().Recover()
+		require.NoError(t, err)
+		assert.Equal(t, RecoveryNone, action)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		err := CurrentRepository().Add("go.md")
+		require.NoError(t, err)
+
+		// Simulate a crash after the index was saved
+		db := CurrentDB()
+		commit, packFiles := db.index.CreateCommitFromStagingArea()
+		require.NoError(t, startCommit(commit))
+		for _, packFile := range packFiles {
+			require.NoError(t, packFile.Save())
+			db.index.putPackFile(commit.OID, packFile)
+		}
+		require.NoError(t, db.index.Save())
+		assert.True(t, db.CommitInProgress())
+
+		// A new commit is refused until the repository is recovered
+		err = db.Commit("new commit")
+		assert.ErrorContains(t, err, "nt recover")
+
+		action, err := db.Recover()
+		require.NoError(t, err)
+		assert.Equal(t, RecoveryReplayed, action)
+		assert.False(t, db.CommitInProgress())
+		assert.Equal(t, commit.OID, db.commitGraph.Ref())
+		ref, _ := db.Ref("main")
+		assert.Equal(t, commit.OID, ref)
+
+		// Changes must have been persisted
+		Reset()
+		assert.Equal(t, commit.OID, CurrentDB().commitGraph.Ref())
+		assert.Equal(t, 0, CurrentDB().index.StagingArea.Count())
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		err := CurrentRepository().Add("go.md")
+		require.NoError(t, err)
+
+		// Simulate a crash before the index was saved
+		db := CurrentDB()
+		commit, packFiles := db.index.CreateCommitFromStagingArea()
+		require.NoError(t, startCommit(commit))
+		var packFilePaths []string
+		for _, packFile := range packFiles {
+			require.NoError(t, packFile.Save())
+			packFilePaths = append(packFilePaths, filepath.Join(root, ".nt/objects", OIDToPath(packFile.OID)))
+		}
+
+		action, err := db.Recover()
+		require.NoError(t, err)
+		assert.Equal(t, RecoveryRolledBack, action)
+		assert.False(t, db.CommitInProgress())
+		for _, path := range packFilePaths {
+			assert.NoFileExists(t, path)
+		}
+		assert.Empty(t, db.commitGraph.Commits)
+
+		// Changes are still staged and can be committed again
+		Reset()
+		assert.NotZero(t, CurrentDB().index.StagingArea.Count())
+		err = CurrentDB().Commit("initial commit")
+		require.NoError(t, err)
+	})
+
+	t.Run("Startup", func(t *testing.T) {
+		root := SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+		err := CurrentRepository().Add("go.md")
+		require.NoError(t, err)
+
+		// Simulate a crash after the index was saved
+		db := CurrentDB()
+		commit, packFiles := db.index.CreateCommitFromStagingArea()
+		require.NoError(t, startCommit(commit))
+		for _, packFile := range packFiles {
+			require.NoError(t, packFile.Save())
+			db.index.putPackFile(commit.OID, packFile)
+		}
+		require.NoError(t, db.index.Save())
+
+		// The interrupted commit is recovered when the database is loaded
+		Reset()
+		assert.False(t, CurrentDB().CommitInProgress())
+		assert.NoFileExists(t, filepath.Join(root, ".nt", CommitInProgressFilename))
+		assert.Equal(t, commit.OID, CurrentDB().commitGraph.Ref())
+		data, err := os.ReadFile(filepath.Join(root, ".nt/refs/main"))
+		require.NoError(t, err)
+		assert.Equal(t, commit.OID, string(data))
+	})
+}
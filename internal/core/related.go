@@ -0,0 +1,185 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/text"
+	"golang.org/x/exp/slices"
+)
+
+// Scores used to rank related notes
+const (
+	// Per note linked directly (in either direction)
+	relatedScoreLink = 3
+	// Per shared tag
+	relatedScoreTag = 2
+	// Per shared attribute value (ex: same author)
+	relatedScoreAttribute = 1
+)
+
+// Attributes ignored when comparing notes
+var relatedIgnoredAttributes = []string{"title", "tags"}
+
+// FindRelatedNotes returns the notes sharing the most with a note, starting with the most related one.
+// Candidates score 3 points when linked to the note (in either direction), 2 points per shared tag,
+// and 1 point per shared attribute value. Notes with the same score are sorted by path and line.
+func (r *Repository) FindRelatedNotes(oid string, limit int) ([]*Note, error) {
+	note, err := r.LoadNoteByOID(oid)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, fmt.Errorf("no note found with OID %s", oid)
+	}
+
+	candidateOIDs := make(map[string]bool)
+	linkedOIDs := make(map[string]bool)
+
+	// Notes within one link hop
+	oids, err := queryOIDs(`
+		SELECT target_oid FROM relation WHERE source_oid = ? AND target_kind = 'note'
+		UNION
+		SELECT source_oid FROM relation WHERE target_oid = ? AND source_kind = 'note';`, oid, oid)
+	if err != nil {
+		return nil, err
+	}
+	for _, linkedOID := range oids {
+		linkedOIDs[linkedOID] = true
+		candidateOIDs[linkedOID] = true
+	}
+
+	// Notes sharing a tag
+	for _, tag := range note.GetTags() {
+		oids, err := queryOIDs(`SELECT note_oid FROM note_tag WHERE tag = ?`, text.Fold(tag))
+		if err != nil {
+			return nil, err
+		}
+		for _, candidateOID := range oids {
+			candidateOIDs[candidateOID] = true
+		}
+	}
+
+	// Notes sharing an attribute value
+	for name, value := range note.GetAttributes() {
+		if slices.Contains(relatedIgnoredAttributes, name) || !regexAttributePath.MatchString(name) {
+			continue
+		}
+		jsonPath, _ := attributeJSONPath(name)
+		for _, value := range relatedAttributeValues(value) {
+			// json_each supports both scalar and array values
+			oids, err := queryOIDs(`
+				SELECT oid FROM note
+				WHERE EXISTS (SELECT 1 FROM json_each(note.attributes, ?) WHERE json_each.value = ?)`,
+				jsonPath, value)
+			if err != nil {
+				return nil, err
+			}
+			for _, candidateOID := range oids {
+				candidateOIDs[candidateOID] = true
+			}
+		}
+	}
+
+	delete(candidateOIDs, oid)
+	if len(candidateOIDs) == 0 {
+		return nil, nil
+	}
+
+	var oidsSQL []string
+	var oidsArgs []any
+	for candidateOID := range candidateOIDs {
+		oidsSQL = append(oidsSQL, "?")
+		oidsArgs = append(oidsArgs, candidateOID)
+	}
+	candidates, err := QueryNotes(CurrentDB().Client(), "WHERE oid IN ("+strings.Join(oidsSQL, ",")+") ORDER BY relative_path, line", oidsArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]int)
+	for _, candidate := range candidates {
+		scores[candidate.OID] = relatedScore(note, candidate, linkedOIDs[candidate.OID])
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i].OID] > scores[candidates[j].OID]
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// relatedScore returns how much two notes have in common.
+func relatedScore(note, candidate *Note, linked bool) int {
+	score := 0
+	if linked {
+		score += relatedScoreLink
+	}
+	for _, tag := range note.GetTags() {
+		for _, candidateTag := range candidate.GetTags() {
+			if text.Fold(tag) == text.Fold(candidateTag) {
+				score += relatedScoreTag
+				break
+			}
+		}
+	}
+	for name, value := range note.GetAttributes() {
+		if slices.Contains(relatedIgnoredAttributes, name) {
+			continue
+		}
+		candidateValue, ok := candidate.GetAttributes()[name]
+		if !ok {
+			continue
+		}
+		candidateValues := relatedAttributeValues(candidateValue)
+		for _, value := range relatedAttributeValues(value) {
+			for _, candidateValue := range candidateValues {
+				if reflect.DeepEqual(value, candidateValue) {
+					score += relatedScoreAttribute
+					break
+				}
+			}
+		}
+	}
+	return score
+}
+
+// relatedAttributeValues returns the scalar values of an attribute.
+func relatedAttributeValues(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		var results []interface{}
+		for _, item := range v {
+			results = append(results, relatedAttributeValues(item)...)
+		}
+		return results
+	case map[string]interface{}:
+		// Nested objects are ignored
+		return nil
+	default:
+		return []interface{}{v}
+	}
+}
+
+// queryOIDs returns the values of the first column.
+func queryOIDs(query string, args ...any) ([]string, error) {
+	rows, err := CurrentDB().Client().Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var oids []string
+	for rows.Next() {
+		var oid string
+		if err := rows.Scan(&oid); err != nil {
+			return nil, err
+		}
+		oids = append(oids, oid)
+	}
+	return oids, rows.Err()
+}
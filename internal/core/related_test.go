@@ -0,0 +1,83 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRelatedNotes(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	MustWriteFile(t, "go.md", `# Go
+
+## Reference: Go
+
+`+"`#go` `#history` `@author: Pike`"+`
+
+Go was designed at Google in 2007.
+
+## Note: Tags
+
+`+"`#go` `#history`"+`
+
+Go 1.0 was released in 2012.
+
+## Note: Author
+
+`+"`@author: Pike`"+`
+
+Rob Pike worked on Plan 9.
+
+## Note: Unrelated
+
+Python was created by Guido van Rossum.
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	// Links are resolved only to existing notes
+	MustWriteFile(t, "links.md", `# Links
+
+## Note: Link
+
+`+"`@source: [[go#Reference: Go]]`"+`
+
+See the history of Go.
+`)
+	err = CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	note := MustFindNoteByPathAndTitle(t, "go.md", "Reference: Go")
+
+	titles := func(notes []*Note) []string {
+		var results []string
+		for _, note := range notes {
+			results = append(results, note.Title)
+		}
+		return results
+	}
+
+	notes, err := CurrentRepository().FindRelatedNotes(note.OID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Note: Tags",   // 2 shared tags
+		"Note: Link",   // 1 link
+		"Note: Author", // 1 shared attribute
+	}, titles(notes))
+
+	notes, err = CurrentRepository().FindRelatedNotes(note.OID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Note: Tags"}, titles(notes))
+
+	// Search operator
+	notes, err = CurrentRepository().SearchNotes("related:" + note.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Note: Tags", "Note: Link", "Note: Author"}, titles(notes))
+	notes, err = CurrentRepository().SearchNotes("related:" + note.Slug + " kind:note Plan")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Note: Author"}, titles(notes))
+	_, err = CurrentRepository().SearchNotes("related:unknown-slug")
+	assert.Error(t, err)
+}
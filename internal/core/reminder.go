@@ -190,6 +190,10 @@ func (r *Reminder) update(note *Note, descriptionRaw, tag string) error {
 		r.Note = note
 		r.stale = true
 	}
+	if r.RelativePath != note.RelativePath {
+		r.RelativePath = note.RelativePath
+		r.stale = true
+	}
 	if r.DescriptionRaw != descriptionRaw {
 		r.updateContent(descriptionRaw)
 		r.stale = true
@@ -205,6 +209,21 @@ func (r *Reminder) update(note *Note, descriptionRaw, tag string) error {
 	return nil
 }
 
+// GetNote returns the owning note, loading it from database if necessary.
+func (r *Reminder) GetNote() *Note {
+	if r.NoteOID == "" {
+		return nil
+	}
+	if r.Note == nil {
+		note, err := CurrentRepository().LoadNoteByOID(r.NoteOID)
+		if err != nil {
+			log.Fatalf("Unable to find note %q: %v", r.NoteOID, err)
+		}
+		r.Note = note
+	}
+	return r.Note
+}
+
 /* State Management */
 
 func (r *Reminder) New() bool {
@@ -224,7 +243,7 @@ func (r *Reminder) Next() error {
 	expression := strings.TrimPrefix(r.Tag, "#reminder-")
 
 	lastPerformedAt := r.NextPerformedAt
-	nextPerformedAt, err := EvaluateTimeExpression(expression)
+	nextPerformedAt, err := EvaluateTimeExpression(expression, CurrentConfig().Location())
 	if err != nil {
 		return err
 	}
@@ -233,12 +252,24 @@ func (r *Reminder) Next() error {
 	return nil
 }
 
+// Snooze postpones the next occurrence by the given duration.
+// A reminder already due is postponed from now.
+func (r *Reminder) Snooze(d time.Duration) {
+	start := r.NextPerformedAt
+	if now := clock.Now(); start.Before(now) {
+		start = now
+	}
+	r.NextPerformedAt = start.Add(d)
+	r.stale = true
+}
+
 /* Parsing */
 
-// EvaluateTimeExpression determine the next matching reminder date
-func EvaluateTimeExpression(expr string) (time.Time, error) {
+// EvaluateTimeExpression determine the next matching reminder date.
+// Dates are midnight in the given location.
+func EvaluateTimeExpression(expr string, loc *time.Location) (time.Time, error) {
 	originalExpr := expr
-	today := clock.Now()
+	today := clock.Now().In(loc)
 
 	// Static dates are easier to address first
 	var reStaticDate = regexp.MustCompile(`(\d{4})(?:-(\d{2})(?:-(\d{2})))`)
@@ -262,7 +293,7 @@ func EvaluateTimeExpression(expr string) (time.Time, error) {
 		} else {
 			month, _ = strconv.Atoi(monthStr)
 		}
-		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), nil
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc), nil
 	}
 
 	// We have an expression where the year, month, day can be ommitted and where different syntaxes are supported (through variables).
@@ -382,7 +413,7 @@ func EvaluateTimeExpression(expr string) (time.Time, error) {
 	}
 
 	// Generate all possible combinations
-	possibleDates := generateDates(yearExpr, monthExpr, dayExpr)
+	possibleDates := generateDates(yearExpr, monthExpr, dayExpr, loc)
 
 	// Filter to keep only future dates
 	var possibleFutureDates []time.Time
@@ -405,7 +436,7 @@ func EvaluateTimeExpression(expr string) (time.Time, error) {
 	return possibleFutureDates[0], nil
 }
 
-func generateDates(yearExpr, monthExpr, dayExpr string) []time.Time {
+func generateDates(yearExpr, monthExpr, dayExpr string, loc *time.Location) []time.Time {
 	// Implementation: We generate all potential candidate dates as it's not easy to determine the target value.
 	//
 	// Ex: `reminder-${year}-07-02`
@@ -422,10 +453,10 @@ func generateDates(yearExpr, monthExpr, dayExpr string) []time.Time {
 		year, _ := strconv.Atoi(yearExpr)
 		month, _ := strconv.Atoi(monthExpr)
 		day, _ := strconv.Atoi(dayExpr)
-		return []time.Time{time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)}
+		return []time.Time{time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)}
 	}
 
-	today := clock.Now()
+	today := clock.Now().In(loc)
 	var dates []time.Time
 	if !text.IsNumber(yearExpr) {
 		switch yearExpr {
@@ -433,23 +464,23 @@ func generateDates(yearExpr, monthExpr, dayExpr string) []time.Time {
 			fallthrough
 		case "year":
 			// this year or next year
-			dates = append(dates, generateDates(fmt.Sprint(today.Year()), monthExpr, dayExpr)...)
-			dates = append(dates, generateDates(fmt.Sprint(today.Year()+1), monthExpr, dayExpr)...)
+			dates = append(dates, generateDates(fmt.Sprint(today.Year()), monthExpr, dayExpr, loc)...)
+			dates = append(dates, generateDates(fmt.Sprint(today.Year()+1), monthExpr, dayExpr, loc)...)
 			return dates
 		case "odd-year":
 			if today.Year()%2 == 0 {
-				dates = append(dates, generateDates(fmt.Sprint(today.Year()), monthExpr, dayExpr)...)
-				dates = append(dates, generateDates(fmt.Sprint(today.Year()+2), monthExpr, dayExpr)...)
+				dates = append(dates, generateDates(fmt.Sprint(today.Year()), monthExpr, dayExpr, loc)...)
+				dates = append(dates, generateDates(fmt.Sprint(today.Year()+2), monthExpr, dayExpr, loc)...)
 			} else {
-				dates = append(dates, generateDates(fmt.Sprint(today.Year()+1), monthExpr, dayExpr)...)
+				dates = append(dates, generateDates(fmt.Sprint(today.Year()+1), monthExpr, dayExpr, loc)...)
 			}
 			return dates
 		case "even-year":
 			if today.Year()%2 == 1 {
-				dates = append(dates, generateDates(fmt.Sprint(today.Year()), monthExpr, dayExpr)...)
-				dates = append(dates, generateDates(fmt.Sprint(today.Year()+2), monthExpr, dayExpr)...)
+				dates = append(dates, generateDates(fmt.Sprint(today.Year()), monthExpr, dayExpr, loc)...)
+				dates = append(dates, generateDates(fmt.Sprint(today.Year()+2), monthExpr, dayExpr, loc)...)
 			} else {
-				dates = append(dates, generateDates(fmt.Sprint(today.Year()+1), monthExpr, dayExpr)...)
+				dates = append(dates, generateDates(fmt.Sprint(today.Year()+1), monthExpr, dayExpr, loc)...)
 			}
 			return dates
 		default:
@@ -466,57 +497,57 @@ func generateDates(yearExpr, monthExpr, dayExpr string) []time.Time {
 		case "month":
 			if today.Year() == year {
 				// this month + next month
-				dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()), dayExpr)...)
+				dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()), dayExpr, loc)...)
 				if today.Month() == time.December {
-					dates = append(dates, generateDates(yearExpr, "01", dayExpr)...)
+					dates = append(dates, generateDates(yearExpr, "01", dayExpr, loc)...)
 				} else {
-					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+1), dayExpr)...)
+					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+1), dayExpr, loc)...)
 				}
 			} else {
 				// First month of a future year
-				dates = append(dates, generateDates(yearExpr, "01", dayExpr)...)
+				dates = append(dates, generateDates(yearExpr, "01", dayExpr, loc)...)
 			}
 			return dates
 		case "odd-month":
 			if today.Year() == year {
 				if today.Month()%2 == 0 {
 					// this month + next odd month
-					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()), dayExpr)...)
+					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()), dayExpr, loc)...)
 					if today.Month() == time.December {
-						dates = append(dates, generateDates(yearExpr, "02", dayExpr)...)
+						dates = append(dates, generateDates(yearExpr, "02", dayExpr, loc)...)
 					} else {
-						dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+2), dayExpr)...)
+						dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+2), dayExpr, loc)...)
 					}
 				} else {
 					// next month (NB: +1 is safe as we know the current month is even)
-					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+1), dayExpr)...)
+					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+1), dayExpr, loc)...)
 				}
 			} else {
 				// First odd month of a future year
-				dates = append(dates, generateDates(yearExpr, "02", dayExpr)...)
+				dates = append(dates, generateDates(yearExpr, "02", dayExpr, loc)...)
 			}
 			return dates
 		case "even-month":
 			if today.Year() == year {
 				if today.Month()%2 == 1 {
 					// this month + next even month
-					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()), dayExpr)...)
+					dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()), dayExpr, loc)...)
 					if today.Month() == time.November {
-						dates = append(dates, generateDates(yearExpr, "01", dayExpr)...)
+						dates = append(dates, generateDates(yearExpr, "01", dayExpr, loc)...)
 					} else {
-						dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+2), dayExpr)...)
+						dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+2), dayExpr, loc)...)
 					}
 				} else {
 					// next month
 					if today.Month() == time.December {
-						dates = append(dates, generateDates(yearExpr, "01", dayExpr)...)
+						dates = append(dates, generateDates(yearExpr, "01", dayExpr, loc)...)
 					} else {
-						dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+1), dayExpr)...)
+						dates = append(dates, generateDates(yearExpr, fmt.Sprintf("%02d", today.Month()+1), dayExpr, loc)...)
 					}
 				}
 			} else {
 				// First even month of a future year
-				dates = append(dates, generateDates(yearExpr, "01", dayExpr)...)
+				dates = append(dates, generateDates(yearExpr, "01", dayExpr, loc)...)
 			}
 			return dates
 		default:
@@ -526,7 +557,7 @@ func generateDates(yearExpr, monthExpr, dayExpr string) []time.Time {
 
 	month, _ := strconv.Atoi(monthExpr)
 	currentMonth := time.Month(month)
-	start := time.Date(year, currentMonth, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(year, currentMonth, 1, 0, 0, 0, 0, loc)
 
 	// We know that dayExpr is not a number if we reach this block
 	switch dayExpr {
@@ -534,10 +565,10 @@ func generateDates(yearExpr, monthExpr, dayExpr string) []time.Time {
 		fallthrough
 	case "day":
 		if today.Year() == year && today.Month() == time.Month(month) {
-			dates = append(dates, generateDates(yearExpr, monthExpr, fmt.Sprintf("%02d", today.Day()+1))...)
-			dates = append(dates, generateDates(yearExpr, monthExpr, "01")...) // end of month
+			dates = append(dates, generateDates(yearExpr, monthExpr, fmt.Sprintf("%02d", today.Day()+1), loc)...)
+			dates = append(dates, generateDates(yearExpr, monthExpr, "01", loc)...) // end of month
 		} else {
-			dates = append(dates, generateDates(yearExpr, monthExpr, "01")...)
+			dates = append(dates, generateDates(yearExpr, monthExpr, "01", loc)...)
 		}
 		return dates
 	case "monday":
@@ -751,6 +782,63 @@ func (r *Repository) LoadReminderByOID(oid string) (*Reminder, error) {
 	return QueryReminder(CurrentDB().Client(), `WHERE oid = ?`, oid)
 }
 
+// SnoozeReminder postpones the next occurrence of a reminder by the given duration.
+// Following occurrences of recurring reminders are unchanged.
+func (r *Repository) SnoozeReminder(oid string, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("invalid snooze duration %s", d)
+	}
+	reminder, err := r.LoadReminderByOID(oid)
+	if err != nil {
+		return err
+	}
+	if reminder == nil {
+		return fmt.Errorf("no reminder found for %q", oid)
+	}
+
+	db := CurrentDB()
+	if err := db.BeginTransaction(); err != nil {
+		return err
+	}
+	defer db.RollbackTransaction()
+
+	// Stage before saving as saving resets the state
+	reminder.Snooze(d)
+	if err := db.StageObject(reminder); err != nil {
+		return fmt.Errorf("unable to stage modified object %s: %v", reminder, err)
+	}
+	if err := reminder.Save(); err != nil {
+		return err
+	}
+
+	if err := db.CommitTransaction(); err != nil {
+		return err
+	}
+	return db.index.Save()
+}
+
+// DueReminders returns reminders whose next occurrence is before the given time, sorted by due date.
+// The owning notes are loaded.
+func (r *Repository) DueReminders(at time.Time) ([]*Reminder, error) {
+	reminders, err := r.FindReminders()
+	if err != nil {
+		return nil, err
+	}
+	// Dates are compared in Go as dates may be stored using different time zones
+	var dueReminders []*Reminder
+	for _, reminder := range reminders {
+		if reminder.NextPerformedAt.After(at) {
+			continue
+		}
+		reminder.GetNote()
+		dueReminders = append(dueReminders, reminder)
+	}
+	sort.SliceStable(dueReminders, func(i, j int) bool {
+		return dueReminders[i].NextPerformedAt.Before(dueReminders[j].NextPerformedAt)
+	})
+	return dueReminders, nil
+}
+
 func (r *Repository) FindRemindersByUpcomingDate(deadline time.Time) ([]*Reminder, error) {
 	return QueryReminders(CurrentDB().Client(), `WHERE next_performed_at > ?`, timeToSQL(deadline))
 }
@@ -71,13 +71,83 @@ func TestEvaluateTimeExpression(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual, err := EvaluateTimeExpression(tt.expr)
+			actual, err := EvaluateTimeExpression(tt.expr, time.UTC)
 			require.NoError(t, err)
 			assert.EqualValues(t, tt.expected, actual)
 		})
 	}
 }
 
+func TestEvaluateTimeExpressionInLocation(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name     string // name
+		now      time.Time
+		loc      *time.Location
+		expr     string // input
+		expected time.Time
+	}{
+		{
+			name:     "Static date",
+			now:      time.Date(2023, time.Month(3), 1, 0, 0, 0, 0, time.UTC),
+			loc:      paris,
+			expr:     "2023-03-26",
+			expected: time.Date(2023, time.Month(3), 26, 0, 0, 0, 0, paris),
+		},
+		{
+			// Clocks go forward on 2023-03-26 in Paris
+			name:     "Daily before DST start",
+			now:      time.Date(2023, time.Month(3), 25, 22, 30, 0, 0, time.UTC), // 23:30 in Paris
+			loc:      paris,
+			expr:     "every-${day}",
+			expected: time.Date(2023, time.Month(3), 26, 0, 0, 0, 0, paris),
+		},
+		{
+			name:     "Daily after DST start",
+			now:      time.Date(2023, time.Month(3), 26, 22, 30, 0, 0, time.UTC), // 00:30 on March 27 in Paris
+			loc:      paris,
+			expr:     "every-${day}",
+			expected: time.Date(2023, time.Month(3), 28, 0, 0, 0, 0, paris),
+		},
+		{
+			// Clocks go back on 2023-10-29 in Paris
+			name:     "Weekly across DST end",
+			now:      time.Date(2023, time.Month(10), 28, 12, 0, 0, 0, time.UTC),
+			loc:      paris,
+			expr:     "every-${sunday}",
+			expected: time.Date(2023, time.Month(10), 29, 0, 0, 0, 0, paris),
+		},
+		{
+			// Still January 31 in New York while February 1 in UTC
+			name:     "Month rollover",
+			now:      time.Date(2023, time.Month(2), 1, 3, 0, 0, 0, time.UTC),
+			loc:      newYork,
+			expr:     "every-${day}",
+			expected: time.Date(2023, time.Month(2), 1, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:     "Month rollover (UTC)",
+			now:      time.Date(2023, time.Month(2), 1, 3, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			expr:     "every-${day}",
+			expected: time.Date(2023, time.Month(2), 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			FreezeAt(t, tt.now)
+			actual, err := EvaluateTimeExpression(tt.expr, tt.loc)
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(actual), "expected %s, got %s", tt.expected, actual)
+			assert.Equal(t, tt.loc, actual.Location())
+		})
+	}
+}
+
 func TestReminder(t *testing.T) {
 
 	t.Run("YAML", func(t *testing.T) {
@@ -133,3 +203,87 @@ updated_at: 2023-01-01T01:12:30Z
 	})
 
 }
+
+func TestSnoozeReminder(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	FreezeAt(t, time.Date(2023, time.Month(1), 1, 1, 12, 30, 0, time.UTC))
+
+	MustWriteFile(t, "garden.md", `# Garden
+
+## Todo: Garden
+
+* [ ] Water the plants `+"`#reminder-every-${day}`"+`
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+	err = CurrentDB().Commit("initial commit")
+	require.NoError(t, err)
+
+	reminders, err := CurrentRepository().FindRemindersMatching(MustFindNoteByPathAndTitle(t, "garden.md", "Todo: Garden").OID, "Water the plants")
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	reminder := reminders[0]
+	assert.EqualValues(t, time.Date(2023, time.Month(1), 2, 0, 0, 0, 0, time.UTC), reminder.NextPerformedAt)
+
+	// Snooze the next occurrence
+	err = CurrentRepository().SnoozeReminder(reminder.OID, 48*time.Hour)
+	require.NoError(t, err)
+	snoozedReminder, err := CurrentRepository().LoadReminderByOID(reminder.OID)
+	require.NoError(t, err)
+	assert.EqualValues(t, time.Date(2023, time.Month(1), 4, 0, 0, 0, 0, time.UTC), snoozedReminder.NextPerformedAt)
+	stagedObject, ok := CurrentDB().index.StagingArea.ReadStagingObject(reminder.OID)
+	require.True(t, ok)
+	assert.Equal(t, Modified, stagedObject.State)
+
+	// Following occurrences are unchanged
+	FreezeAt(t, time.Date(2023, time.Month(1), 4, 8, 0, 0, 0, time.UTC))
+	require.NoError(t, snoozedReminder.Next())
+	assert.EqualValues(t, time.Date(2023, time.Month(1), 5, 0, 0, 0, 0, time.UTC), snoozedReminder.NextPerformedAt)
+
+	// A due reminder is snoozed from now
+	snoozedReminder.NextPerformedAt = time.Date(2023, time.Month(1), 1, 0, 0, 0, 0, time.UTC)
+	snoozedReminder.Snooze(2 * time.Hour)
+	assert.EqualValues(t, time.Date(2023, time.Month(1), 4, 10, 0, 0, 0, time.UTC), snoozedReminder.NextPerformedAt)
+
+	// Invalid inputs
+	err = CurrentRepository().SnoozeReminder("unknown", time.Hour)
+	assert.ErrorContains(t, err, "no reminder found")
+	err = CurrentRepository().SnoozeReminder(reminder.OID, -time.Hour)
+	assert.ErrorContains(t, err, "invalid snooze duration")
+}
+
+func TestDueReminders(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	FreezeAt(t, time.Date(2023, time.Month(1), 1, 1, 12, 30, 0, time.UTC))
+
+	MustWriteFile(t, "home.md", `# Home
+
+## Todo: Renovation
+
+* [ ] Renew the insurance `+"`#reminder-2023-02-01`"+`
+* [ ] Fix the door `+"`#reminder-2023-01-15`"+`
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	reminders, err := CurrentRepository().DueReminders(time.Date(2023, time.Month(1), 10, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+
+	reminders, err = CurrentRepository().DueReminders(time.Date(2023, time.Month(2), 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, reminders, 2)
+	assert.Equal(t, "Fix the door", reminders[0].DescriptionText)
+	assert.Equal(t, "Renew the insurance", reminders[1].DescriptionText)
+	require.NotNil(t, reminders[0].Note)
+	assert.Equal(t, "home.md", reminders[0].Note.RelativePath)
+	assert.Equal(t, "Todo: Renovation", reminders[0].Note.Title)
+
+	// Reminders stored in a different time zone are compared by instant
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	reminders, err = CurrentRepository().DueReminders(time.Date(2023, time.Month(2), 1, 0, 30, 0, 0, paris))
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, "Fix the door", reminders[0].DescriptionText)
+}
@@ -6,8 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -15,7 +19,8 @@ import (
 )
 
 var (
-	ErrObjectNotExist = errors.New("object does not exist")
+	ErrObjectNotExist    = errors.New("object does not exist")
+	ErrObjectNotModified = errors.New("object not modified")
 )
 
 // Remote provides an abstraction in front of remote implementations.
@@ -29,11 +34,25 @@ var (
 // the same field when querying using the same key.
 type Remote interface {
 	GetObject(key string) ([]byte, error)
+	// GetObjectStream is similar to GetObject but does not load the whole object in memory (ex: large blobs).
+	// The caller must close the reader.
+	GetObjectStream(key string) (io.ReadCloser, error)
 	PutObject(key string, content []byte) error
 	DeleteObject(key string) error
+	// ObjectExists checks the presence of an object without downloading it.
+	ObjectExists(key string) (bool, error)
+	// ListObjects returns the keys of all objects (ex: "info/commit-graph").
+	ListObjects() ([]string, error)
 	// Note: File permissions are not important concerning object. MTime, etc. must be stored inside the object definitions if useful.
 }
 
+// ConditionalRemote is implemented by remotes supporting conditional reads (ex: If-None-Match on S3).
+type ConditionalRemote interface {
+	// GetObjectIfChanged returns the object with its current version (ex: an ETag)
+	// or ErrObjectNotModified when the object still has the given version.
+	GetObjectIfChanged(key string, version string) ([]byte, string, error)
+}
+
 /* FS */
 
 type FSRemote struct {
@@ -64,6 +83,17 @@ func (r *FSRemote) GetObject(key string) ([]byte, error) {
 	return data, err
 }
 
+func (r *FSRemote) GetObjectStream(key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(r.path, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
 func (r *FSRemote) PutObject(key string, data []byte) error {
 	dirPath := filepath.Join(r.path, filepath.Dir(key))
 	err := os.MkdirAll(dirPath, 0755)
@@ -83,6 +113,33 @@ func (r *FSRemote) DeleteObject(key string) error {
 	return os.Remove(path)
 }
 
+func (r *FSRemote) ObjectExists(key string) (bool, error) {
+	_, err := os.Stat(filepath.Join(r.path, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *FSRemote) ListObjects() ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(r.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		key, err := filepath.Rel(r.path, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(key))
+		return nil
+	})
+	return keys, err
+}
+
 /* S3 */
 
 type S3Remote struct {
@@ -132,6 +189,53 @@ func (r *S3Remote) GetObject(key string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+func (r *S3Remote) GetObjectIfChanged(key string, version string) ([]byte, string, error) {
+	options := minio.GetObjectOptions{}
+	if version != "" {
+		if err := options.SetMatchETagExcept(version); err != nil {
+			return nil, "", err
+		}
+	}
+	object, err := r.minioClient.GetObject(context.Background(), r.bucketName, key, options)
+	if err != nil {
+		return nil, "", err
+	}
+	defer object.Close()
+	// Errors are only reported when reading the object
+	stat, err := object.Stat()
+	if err != nil {
+		errorResponse := minio.ToErrorResponse(err)
+		if errorResponse.StatusCode == http.StatusNotModified {
+			return nil, "", ErrObjectNotModified
+		}
+		if errorResponse.Code == "NoSuchKey" {
+			return nil, "", ErrObjectNotExist
+		}
+		return nil, "", err
+	}
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, stat.ETag, nil
+}
+
+func (r *S3Remote) GetObjectStream(key string) (io.ReadCloser, error) {
+	object, err := r.minioClient.GetObject(context.Background(), r.bucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// Errors are only reported when reading the object
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrObjectNotExist
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
 func (r *S3Remote) PutObject(key string, data []byte) error {
 	_, err := r.minioClient.PutObject(context.Background(), r.bucketName, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
 	return err
@@ -145,6 +249,28 @@ func (r *S3Remote) DeleteObject(key string) error {
 	return r.minioClient.RemoveObject(context.Background(), r.bucketName, key, minio.RemoveObjectOptions{})
 }
 
+func (r *S3Remote) ObjectExists(key string) (bool, error) {
+	_, err := r.minioClient.StatObject(context.Background(), r.bucketName, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *S3Remote) ListObjects() ([]string, error) {
+	var keys []string
+	for object := range r.minioClient.ListObjects(context.Background(), r.bucketName, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		keys = append(keys, object.Key)
+	}
+	return keys, nil
+}
+
 /* Storj */
 
 type StorjRemote struct {
@@ -208,6 +334,17 @@ func (r *StorjRemote) GetObject(key string) ([]byte, error) {
 	return data, nil
 }
 
+func (r *StorjRemote) GetObjectStream(key string) (io.ReadCloser, error) {
+	download, err := r.project.DownloadObject(context.Background(), r.bucketName, key, nil)
+	if errors.Is(err, uplink.ErrObjectNotFound) {
+		return nil, ErrObjectNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not open object: %v", err)
+	}
+	return download, nil
+}
+
 func (r *StorjRemote) PutObject(key string, data []byte) error {
 	ctx := context.Background()
 
@@ -244,3 +381,156 @@ func (r *StorjRemote) DeleteObject(key string) error {
 	}
 	return nil
 }
+
+func (r *StorjRemote) ObjectExists(key string) (bool, error) {
+	_, err := r.project.StatObject(context.Background(), r.bucketName, key)
+	if errors.Is(err, uplink.ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *StorjRemote) ListObjects() ([]string, error) {
+	var keys []string
+	objects := r.project.ListObjects(context.Background(), r.bucketName, &uplink.ListObjectsOptions{Recursive: true})
+	for objects.Next() {
+		keys = append(keys, objects.Item().Key)
+	}
+	if err := objects.Err(); err != nil {
+		return nil, fmt.Errorf("could not list objects: %v", err)
+	}
+	return keys, nil
+}
+
+/* Rclone */
+
+// RcloneRemote delegates to the rclone binary to support any storage
+// supported by rclone (ex: Dropbox, Google Drive, OneDrive).
+type RcloneRemote struct {
+	// Settings
+	path string // Ex: "mydrive:notes"
+}
+
+func NewRcloneRemote(path string) (*RcloneRemote, error) {
+	if path == "" {
+		return nil, errors.New("missing rclone remote path")
+	}
+	if _, err := exec.LookPath("rclone"); err != nil {
+		return nil, fmt.Errorf("rclone binary not found: %v", err)
+	}
+	return &RcloneRemote{
+		path: strings.TrimSuffix(path, "/"),
+	}, nil
+}
+
+// location returns the rclone path of a key.
+func (r *RcloneRemote) location(key string) string {
+	if strings.HasSuffix(r.path, ":") {
+		return r.path + key
+	}
+	return r.path + "/" + key
+}
+
+// run executes a rclone command and returns its standard output.
+func (r *RcloneRemote) run(stdin []byte, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("rclone", args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if isRcloneNotFound(err, stderr.String()) {
+			return nil, ErrObjectNotExist
+		}
+		return nil, fmt.Errorf("rclone %s failed: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// isRcloneNotFound determines if a rclone command failed due to a missing file.
+// See https://rclone.org/docs/#exit-code
+func isRcloneNotFound(err error, stderr string) bool {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// 3 = Directory not found, 4 = File not found
+		if exitErr.ExitCode() == 3 || exitErr.ExitCode() == 4 {
+			return true
+		}
+	}
+	stderr = strings.ToLower(stderr)
+	for _, message := range []string{"object not found", "file not found", "directory not found"} {
+		if strings.Contains(stderr, message) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RcloneRemote) GetObject(key string) ([]byte, error) {
+	data, err := r.run(nil, "cat", r.location(key))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		// rclone cat succeeds on missing files when the parent directory exists
+		exists, err := r.ObjectExists(key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrObjectNotExist
+		}
+	}
+	return data, nil
+}
+
+func (r *RcloneRemote) GetObjectStream(key string) (io.ReadCloser, error) {
+	data, err := r.GetObject(key)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (r *RcloneRemote) PutObject(key string, data []byte) error {
+	_, err := r.run(data, "rcat", r.location(key))
+	return err
+}
+
+func (r *RcloneRemote) DeleteObject(key string) error {
+	_, err := r.run(nil, "deletefile", r.location(key))
+	return err
+}
+
+func (r *RcloneRemote) ObjectExists(key string) (bool, error) {
+	output, err := r.run(nil, "lsf", "--files-only", r.location(key))
+	if errors.Is(err, ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+func (r *RcloneRemote) ListObjects() ([]string, error) {
+	output, err := r.run(nil, "lsf", "-R", "--files-only", r.path)
+	if errors.Is(err, ErrObjectNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line != "" {
+			keys = append(keys, line)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// Name of the file caching the last-seen remote indexes
+const RemoteIndexCacheFilename = "remote-index-cache"
+
+// RemoteIndexCache is the content of the file .nt/remote-index-cache.
+type RemoteIndexCache struct {
+	// Last-seen index for every remote name
+	Remotes map[string]*RemoteIndexCacheEntry `yaml:"remotes"`
+}
+
+// RemoteIndexCacheEntry is the last-seen index of a remote.
+type RemoteIndexCacheEntry struct {
+	// Version of the remote object (ex: an ETag)
+	Version string `yaml:"version"`
+	// Raw content of the remote index
+	Index string `yaml:"index"`
+}
+
+// remoteIndexCachePath returns the path of the cache file.
+func remoteIndexCachePath() string {
+	return filepath.Join(CurrentConfig().RootDirectory, ".nt", RemoteIndexCacheFilename)
+}
+
+// readRemoteIndexCache reads the cache file (empty if missing).
+func readRemoteIndexCache() (*RemoteIndexCache, error) {
+	cache := &RemoteIndexCache{
+		Remotes: make(map[string]*RemoteIndexCacheEntry),
+	}
+	data, err := os.ReadFile(remoteIndexCachePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("invalid %s file: %v", RemoteIndexCacheFilename, err)
+	}
+	if cache.Remotes == nil {
+		cache.Remotes = make(map[string]*RemoteIndexCacheEntry)
+	}
+	return cache, nil
+}
+
+// Save writes the cache file.
+func (c *RemoteIndexCache) Save() error {
+	return filesystem.WriteFileAtomic(remoteIndexCachePath(), func(w io.Writer) error {
+		return yaml.NewEncoder(w).Encode(c)
+	})
+}
+
+// readRemoteIndex returns the raw index of the remote with the given name.
+// When the remote index cache is enabled and the remote supports conditional reads,
+// the index is downloaded only if changed since the last operation.
+func readRemoteIndex(name string, origin Remote) ([]byte, error) {
+	conditionalOrigin, ok := origin.(ConditionalRemote)
+	if !CurrentConfig().RemoteIndexCache || !ok {
+		return origin.GetObject("index")
+	}
+
+	cache, err := readRemoteIndexCache()
+	if err != nil {
+		return nil, err
+	}
+	version := ""
+	entry, ok := cache.Remotes[name]
+	if ok {
+		version = entry.Version
+	}
+
+	data, newVersion, err := conditionalOrigin.GetObjectIfChanged("index", version)
+	if errors.Is(err, ErrObjectNotModified) && ok {
+		CurrentLogger().Debugf("Reusing cached index of remote %s", name)
+		return []byte(entry.Index), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Remotes[name] = &RemoteIndexCacheEntry{
+		Version: newVersion,
+		Index:   string(data),
+	}
+	if err := cache.Save(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/julien-sobczak/the-notewriter/internal/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conditionalFSRemote is a FS remote supporting conditional reads using the hash of objects as version.
+type conditionalFSRemote struct {
+	*FSRemote
+	downloads int
+}
+
+func (r *conditionalFSRemote) GetObjectIfChanged(key string, version string) ([]byte, string, error) {
+	data, err := r.GetObject(key)
+	if err != nil {
+		return nil, "", err
+	}
+	newVersion := helpers.Hash(data)
+	if newVersion == version {
+		return nil, "", ErrObjectNotModified
+	}
+	r.downloads++
+	return data, newVersion, nil
+}
+
+func TestReadRemoteIndex(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+
+	fsRemote, err := NewFSRemote(t.TempDir())
+	require.NoError(t, err)
+	origin := &conditionalFSRemote{FSRemote: fsRemote}
+	require.NoError(t, origin.PutObject("index", []byte("v1")))
+
+	t.Run("Disabled", func(t *testing.T) {
+		data, err := readRemoteIndex(DefaultRemoteName, origin)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v1"), data)
+		assert.Equal(t, 0, origin.downloads)
+		assert.NoFileExists(t, filepath.Join(root, ".nt", RemoteIndexCacheFilename))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		CurrentConfig().RemoteIndexCache = true
+		defer func() {
+			CurrentConfig().RemoteIndexCache = false
+		}()
+
+		// First read downloads the index
+		data, err := readRemoteIndex(DefaultRemoteName, origin)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v1"), data)
+		assert.Equal(t, 1, origin.downloads)
+		assert.FileExists(t, filepath.Join(root, ".nt", RemoteIndexCacheFilename))
+
+		// Unchanged index is read from the cache
+		data, err = readRemoteIndex(DefaultRemoteName, origin)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v1"), data)
+		assert.Equal(t, 1, origin.downloads)
+
+		// Updated index is downloaded again
+		require.NoError(t, origin.PutObject("index", []byte("v2")))
+		data, err = readRemoteIndex(DefaultRemoteName, origin)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v2"), data)
+		assert.Equal(t, 2, origin.downloads)
+
+		// Cache is per remote
+		data, err = readRemoteIndex("backup", origin)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("v2"), data)
+		assert.Equal(t, 3, origin.downloads)
+	})
+}
@@ -2,6 +2,9 @@ package core
 
 import (
 	"context"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,15 +58,128 @@ commits:
 	- a04d20dec96acfc2f9785802d7e3708721005d5d
 `), data)
 
+	// Stream the file
+	reader, err := r.GetObjectStream("info/commit-graph")
+	require.NoError(t, err)
+	streamedData, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, data, streamedData)
+	_, err = r.GetObjectStream("commit-graph")
+	assert.ErrorIs(t, err, ErrObjectNotExist)
+
+	// Check the file
+	exists, err := r.ObjectExists("info/commit-graph")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	keys, err := r.ListObjects()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"info/commit-graph"}, keys)
+
 	// Delete the file
 	err = r.DeleteObject("info/commit-graph")
 	require.NoError(t, err)
+	exists, err = r.ObjectExists("info/commit-graph")
+	require.NoError(t, err)
+	assert.False(t, exists)
 
 	// Delete a missing file
 	err = r.DeleteObject("info/commit-graph")
 	require.Error(t, err)
 }
 
+func TestRcloneRemote(t *testing.T) {
+	// Simulate rclone using a local directory to test without network access
+	storage := t.TempDir()
+	bin := t.TempDir()
+	script := `#!/bin/sh
+command=$1
+shift
+while [ "${1#-}" != "$1" ]; do shift; done
+path="$FAKE_RCLONE_DIR/${1#fake:}"
+case "$command" in
+cat)
+	[ -d "$(dirname "$path")" ] || { echo "directory not found" >&2; exit 3; }
+	if [ -f "$path" ]; then cat "$path"; fi
+	;;
+rcat)
+	mkdir -p "$(dirname "$path")" && cat > "$path"
+	;;
+deletefile)
+	[ -f "$path" ] || { echo "object not found" >&2; exit 4; }
+	rm "$path"
+	;;
+lsf)
+	if [ -d "$path" ]; then
+		(cd "$path" && find . -type f | sed 's|^\./||' | sort)
+	elif [ -f "$path" ]; then
+		basename "$path"
+	elif [ ! -d "$(dirname "$path")" ]; then
+		echo "directory not found" >&2
+		exit 3
+	fi
+	;;
+esac
+`
+	err := os.WriteFile(filepath.Join(bin, "rclone"), []byte(script), 0755)
+	require.NoError(t, err)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_RCLONE_DIR", storage)
+
+	r, err := NewRcloneRemote("fake:")
+	require.NoError(t, err)
+
+	// Empty remote
+	keys, err := r.ListObjects()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// Add a file
+	err = r.PutObject("info/commit-graph", []byte("commits: []"))
+	require.NoError(t, err)
+
+	// Read missing files
+	_, err = r.GetObject("commit-graph")
+	assert.ErrorIs(t, err, ErrObjectNotExist)
+	_, err = r.GetObject("missing/commit-graph")
+	assert.ErrorIs(t, err, ErrObjectNotExist)
+	_, err = r.GetObjectStream("commit-graph")
+	assert.ErrorIs(t, err, ErrObjectNotExist)
+
+	// Read the correct file
+	data, err := r.GetObject("info/commit-graph")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("commits: []"), data)
+	reader, err := r.GetObjectStream("info/commit-graph")
+	require.NoError(t, err)
+	streamedData, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, data, streamedData)
+
+	// Check the file
+	exists, err := r.ObjectExists("info/commit-graph")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = r.ObjectExists("missing/commit-graph")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	keys, err = r.ListObjects()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"info/commit-graph"}, keys)
+
+	// Delete the file
+	err = r.DeleteObject("info/commit-graph")
+	require.NoError(t, err)
+	exists, err = r.ObjectExists("info/commit-graph")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Delete a missing file
+	err = r.DeleteObject("info/commit-graph")
+	assert.ErrorIs(t, err, ErrObjectNotExist)
+}
+
 func TestStorjRemote(t *testing.T) {
 	t.Skip() // The test does not execute the closure...
 
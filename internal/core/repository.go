@@ -1,24 +1,28 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/julien-sobczak/the-notewriter/pkg/clock"
 	"github.com/julien-sobczak/the-notewriter/pkg/filesystem"
 	"github.com/julien-sobczak/the-notewriter/pkg/resync"
 	"github.com/julien-sobczak/the-notewriter/pkg/text"
-	godiffpatch "github.com/sourcegraph/go-diff-patch"
 	"golang.org/x/exp/slices"
 )
 
 const ReferenceKindBook = "book"
 const ReferenceKindAuthor = "author"
+const ReferenceKindMovie = "movie"
 
 var (
 	// Lazy-load configuration and ensure a single read
@@ -112,12 +116,52 @@ func (r *Repository) walk(paths []string, fn func(path string, stat fs.FileInfo)
 	var fileInfos = make(map[string]*fs.FileInfo)
 	var filePaths = make(map[string]string)
 
-	for _, path := range paths {
-		CurrentLogger().Infof("Reading %s...\n", path)
-		filepath.WalkDir(path, func(path string, info fs.DirEntry, err error) error {
+	// Ignore files present in subdirectories are loaded when walking
+	ignoreFile := IgnoreFile{Entries: config.IgnoreFile.Entries}
+	loadNestedIgnoreFile := func(dir string, relDir string) {
+		if relDir == "." {
+			// Already loaded in the configuration
+			return
+		}
+		content, err := os.ReadFile(filepath.Join(dir, ".ntignore"))
+		if err != nil {
+			return
+		}
+		if err := ignoreFile.AddNested(relDir, string(content)); err != nil {
+			CurrentLogger().Infof("Ignoring invalid file %s: %v\n", filepath.Join(relDir, ".ntignore"), err)
+		}
+	}
+
+	// Real paths of walked directories to not follow symlinks creating cycles
+	followSymlinks := config.ConfigFile.Core.FollowSymlinks
+	visitedDirs := make(map[string]bool)
+
+	// walkDir walks a directory (or a symlink to a directory) reporting paths under the given dir.
+	var walkDir func(dir string) error
+	walkDir = func(dir string) error {
+		realDir := dir
+		if followSymlinks {
+			var err error
+			realDir, err = filepath.EvalSymlinks(dir)
 			if err != nil {
 				return err
 			}
+			visitedDirs[realDir] = true
+		}
+
+		return filepath.WalkDir(realDir, func(realPath string, info fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			path := realPath
+			if realDir != dir {
+				relDir, err := filepath.Rel(realDir, realPath)
+				if err != nil {
+					return err
+				}
+				path = filepath.Join(dir, relDir)
+			}
 
 			dirname := filepath.Base(path)
 			if dirname == ".nt" {
@@ -133,10 +177,39 @@ func (r *Repository) walk(paths []string, fn func(path string, stat fs.FileInfo)
 				return nil
 			}
 
-			if config.IgnoreFile.MustExcludeFile(relpath, info.IsDir()) {
+			isDir := info.IsDir()
+			symlinkTarget := ""
+			if followSymlinks && info.Type()&fs.ModeSymlink != 0 {
+				if target, err := filepath.EvalSymlinks(realPath); err == nil {
+					if targetInfo, err := os.Stat(target); err == nil && targetInfo.IsDir() {
+						isDir = true
+						symlinkTarget = target
+					}
+				}
+			}
+
+			if ignoreFile.MustExcludeFile(relpath, isDir) {
+				// Files under an ignored directory can be included again only using a negated entry
+				if info.IsDir() && !ignoreFile.Negated() {
+					// Don't waste time reading irrelevant subtrees
+					return fs.SkipDir
+				}
 				return nil
 			}
 
+			if isDir {
+				loadNestedIgnoreFile(path, relpath)
+			}
+
+			if symlinkTarget != "" {
+				// Cycle guard: a directory is walked once and never from one of its subdirectories
+				if visitedDirs[symlinkTarget] || strings.HasPrefix(realPath, symlinkTarget+string(filepath.Separator)) {
+					CurrentLogger().Infof("Ignoring symlink %s to avoid a cycle\n", relpath)
+					return nil
+				}
+				return walkDir(path)
+			}
+
 			// We look for only specific extension
 			if !info.IsDir() && !config.ConfigFile.SupportExtension(relpath) {
 				// Nothing to do
@@ -149,6 +222,14 @@ func (r *Repository) walk(paths []string, fn func(path string, stat fs.FileInfo)
 				// Ignore the file
 				return nil
 			}
+			if followSymlinks && fileInfo.Mode()&fs.ModeSymlink != 0 {
+				// Process symlinked files like their target
+				fileInfo, err = os.Stat(path)
+				if err != nil {
+					// Ignore broken symlinks
+					return nil
+				}
+			}
 			if !fileInfo.Mode().IsRegular() {
 				// Exclude any file with a mode bit set (device, socket, named pipe, ...)
 				// See https://pkg.go.dev/io/fs#FileMode
@@ -164,6 +245,17 @@ func (r *Repository) walk(paths []string, fn func(path string, stat fs.FileInfo)
 		})
 	}
 
+	for _, path := range outermostPaths(paths) {
+		CurrentLogger().Infof("Reading %s...\n", path)
+		// Rules of parent directories also apply
+		if relpath, err := r.GetFileRelativePath(path); err == nil && !strings.HasPrefix(relpath, "..") {
+			for relDir := filepath.Dir(relpath); relDir != "." && relDir != string(filepath.Separator); relDir = filepath.Dir(relDir) {
+				loadNestedIgnoreFile(r.GetAbsolutePath(relDir), relDir)
+			}
+		}
+		walkDir(path)
+	}
+
 	// Process the file in a given order:
 
 	// Constraint 1: index.md must be processed before other notes under this directory
@@ -244,6 +336,32 @@ func (r *Repository) walk(paths []string, fn func(path string, stat fs.FileInfo)
 	return nil
 }
 
+// outermostPaths removes the paths present under another path to walk every subtree once.
+func outermostPaths(paths []string) []string {
+	var results []string
+	for i, path := range paths {
+		nested := false
+		for j, otherPath := range paths {
+			if i == j {
+				continue
+			}
+			if path == otherPath && j < i {
+				// Duplicate
+				nested = true
+				break
+			}
+			if strings.HasPrefix(path, strings.TrimSuffix(otherPath, string(filepath.Separator))+string(filepath.Separator)) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			results = append(results, path)
+		}
+	}
+	return results
+}
+
 // normalizePaths converts to absolute paths.
 func (r *Repository) normalizePaths(paths ...string) []string {
 	if len(paths) == 0 {
@@ -262,15 +380,139 @@ func (r *Repository) normalizePaths(paths ...string) []string {
 	return results
 }
 
+// FileError reports an error that occurred when processing a single file.
+type FileError struct {
+	RelativePath string
+	Err          error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.RelativePath, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// FileErrors aggregates errors collected when processing files with the keep-going mode.
+type FileErrors []*FileError
+
+func (e FileErrors) Error() string {
+	var res strings.Builder
+	res.WriteString(fmt.Sprintf("%d files cannot be processed:", len(e)))
+	for _, err := range e {
+		res.WriteString("\n  ")
+		res.WriteString(err.Error())
+	}
+	return res.String()
+}
+
+// Contains returns true if an error was collected for the given file.
+func (e FileErrors) Contains(relativePath string) bool {
+	for _, err := range e {
+		if err.RelativePath == relativePath {
+			return true
+		}
+	}
+	return false
+}
+
+// collectFileError records the error when the keep-going mode is enabled or returns it otherwise.
+func (r *Repository) collectFileError(errs *FileErrors, path string, err error) error {
+	if !CurrentConfig().KeepGoing {
+		return err
+	}
+	relativePath, relErr := r.GetFileRelativePath(path)
+	if relErr != nil {
+		return relErr
+	}
+	if !errs.Contains(relativePath) {
+		CurrentLogger().Warnf("Ignoring file %s: %v", relativePath, err)
+		*errs = append(*errs, &FileError{RelativePath: relativePath, Err: err})
+	}
+	return nil
+}
+
+// AddSummary lists the files and medias staged by the command `nt add`.
+type AddSummary struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+	// Files ignored due to errors (keep-going mode only)
+	FileErrors FileErrors
+}
+
+// Count returns the number of files and medias staged.
+func (s *AddSummary) Count() int {
+	return len(s.Added) + len(s.Modified) + len(s.Deleted)
+}
+
 // Add implements the command `nt add`.`
 func (r *Repository) Add(paths ...string) error {
-	// Start with command linter (do not stage invalid file)
-	linterResult, err := r.Lint(nil, paths...)
-	if err != nil {
-		return err
+	_, err := r.AddWithSummary(paths...)
+	return err
+}
+
+// MediaProgress reports the generation of blobs when adding files.
+type MediaProgress struct {
+	// Media whose blobs were generated
+	RelativePath string
+	// Number of medias processed so far
+	Completed int
+	// Number of medias to process
+	Total int
+}
+
+// AddWithSummary implements the command `nt add` and returns the staged files and medias.
+// Nothing is persisted when the dry-run mode is enabled.
+func (r *Repository) AddWithSummary(paths ...string) (*AddSummary, error) {
+	return r.AddWithContext(context.Background(), nil, paths...)
+}
+
+// AddWithContext works like AddWithSummary but stops when the context is canceled.
+// Progress of blob generation is sent on the optional channel.
+// Nothing is staged after a cancellation and the blobs generated in the meantime are removed.
+func (r *Repository) AddWithContext(ctx context.Context, progress chan<- MediaProgress, paths ...string) (*AddSummary, error) {
+	return r.add(ctx, progress, nil, paths...)
+}
+
+// NoteSelector decides if an added, modified, or deleted note must be staged.
+type NoteSelector func(note *Note) (bool, error)
+
+// AddPatch implements the command `nt add --patch`.
+// Only the changed notes accepted by the selector are staged (with their links, reminders, and flashcards).
+// Files are still staged and skipped notes will be staged by the next `nt add`.
+func (r *Repository) AddPatch(ctx context.Context, selector NoteSelector, paths ...string) (*AddSummary, error) {
+	return r.add(ctx, nil, selector, paths...)
+}
+
+// noteOIDOf returns the OID of the note owning the given object (empty if none).
+func noteOIDOf(object StatefulObject) string {
+	switch o := object.(type) {
+	case *Link:
+		return o.NoteOID
+	case *Reminder:
+		return o.NoteOID
+	case *Flashcard:
+		return o.NoteOID
 	}
-	if len(linterResult.Errors) > 0 {
-		return fmt.Errorf("%d linter errors detected:\n%s", len(linterResult.Errors), linterResult)
+	return ""
+}
+
+func (r *Repository) add(ctx context.Context, progress chan<- MediaProgress, selector NoteSelector, paths ...string) (*AddSummary, error) {
+	// Files that cannot be parsed are skipped in keep-going mode
+	var fileErrors FileErrors
+
+	// Start with command linter (do not stage invalid file) unless explicitly disabled
+	if !CurrentConfig().NoVerify {
+		linterResult, err := r.Lint(nil, paths...)
+		if err != nil {
+			return nil, err
+		}
+		if len(linterResult.Errors) > 0 {
+			return nil, fmt.Errorf("%d linter errors detected:\n%s", len(linterResult.Errors), linterResult)
+		}
+		fileErrors = linterResult.FileErrors
 	}
 
 	// Any object not updated after this date will be considered as deletions
@@ -278,6 +520,10 @@ func (r *Repository) Add(paths ...string) error {
 	db := CurrentDB()
 	paths = r.normalizePaths(paths...)
 
+	// Remember the staging area to determine the changes (and to revert them in dry-run mode)
+	dryRun := CurrentConfig().DryRun
+	stagingAreaBefore := db.index.StagingArea.Clone()
+
 	// Keep notes of processed objects to avoid duplication of effort
 	// when some objects like medias are referenced by different notes.
 	traversedObjects := make(map[string]bool)
@@ -286,15 +532,41 @@ func (r *Repository) Add(paths ...string) error {
 	// Keep notes of unprocessed medias to generate blob using goroutines to speed up the execution
 	var unprocessedMedias []*Media
 
+	// Keep notes of notes rejected by the selector to ignore their changes
+	skippedNotes := make(map[string]bool)
+	skip := func(object StatefulObject) (bool, error) {
+		if selector == nil || object.State() == None {
+			return false, nil
+		}
+		if skippedNotes[noteOIDOf(object)] {
+			return true, nil
+		}
+		note, ok := object.(*Note)
+		if !ok {
+			return false, nil
+		}
+		selected, err := selector(note)
+		if err != nil {
+			return false, err
+		}
+		if !selected {
+			skippedNotes[note.OID] = true
+		}
+		return !selected, nil
+	}
+
 	// Run all queries inside the same transaction
-	err = db.BeginTransaction()
+	err := db.BeginTransaction()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer db.RollbackTransaction()
 
 	// Traverse all given path to add files
 	err = r.walk(paths, func(path string, stat fs.FileInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		CurrentLogger().Debugf("Processing %s...\n", path)
 
 		var parent *File = nil
@@ -310,10 +582,18 @@ func (r *Repository) Add(paths ...string) error {
 			}
 		}
 
-		file, err := NewOrExistingFile(parent, path)
+		relativePath, err := r.GetFileRelativePath(path)
 		if err != nil {
 			return err
 		}
+		if fileErrors.Contains(relativePath) {
+			return nil
+		}
+
+		file, err := NewOrExistingFile(parent, path)
+		if err != nil {
+			return r.collectFileError(&fileErrors, path, err)
+		}
 
 		if file.HasTag("ignore") {
 			// Do not add to index files marked as ignorable
@@ -336,6 +616,14 @@ func (r *Repository) Add(paths ...string) error {
 				continue
 			}
 
+			skipped, err := skip(object)
+			if err != nil {
+				return err
+			}
+			if skipped {
+				continue
+			}
+
 			// Notes are processed in two passes
 			if object.Kind() == "note" {
 				if note, ok := object.(*Note); ok {
@@ -364,37 +652,32 @@ func (r *Repository) Add(paths ...string) error {
 		return nil
 	})
 	if err != nil {
-		return err
+		if ctx.Err() != nil {
+			// Discard all changes (the transaction is rolled back)
+			db.index.StagingArea = stagingAreaBefore
+		}
+		return nil, err
 	}
 
 	// Generate blobs
-	mediaJobs := make(chan *Media, len(unprocessedMedias))
-	mediaResults := make(chan *Media, len(unprocessedMedias))
-	countWorkers := CurrentConfig().ConfigFile.Medias.Parallel
-	if countWorkers == 0 {
-		countWorkers = 1
-	}
-	for w := 1; w <= countWorkers; w++ {
-		go func(workerNum int, jobs <-chan *Media, results chan<- *Media) {
-			for media := range jobs {
-				CurrentLogger().Infof("[worker %d] Generating blobs for %s...\n", workerNum, media.RelativePath)
-				media.UpdateBlobs()
-				results <- media
-			}
-		}(w, mediaJobs, mediaResults)
-	}
-	for _, media := range unprocessedMedias {
-		mediaJobs <- media
+	completedMedias, err := generateBlobs(ctx, unprocessedMedias, progress)
+	if err != nil {
+		// Discard all changes
+		db.index.StagingArea = stagingAreaBefore
+		if err := db.RollbackTransaction(); err != nil {
+			return nil, err
+		}
+		if err := r.deleteUnreferencedBlobs(completedMedias); err != nil {
+			return nil, err
+		}
+		return nil, err
 	}
-	close(mediaJobs)
-	// Then, wait for blob generation to end
-	for i := 0; i < len(unprocessedMedias); i++ {
-		mediaCompleted := <-mediaResults
+	for _, mediaCompleted := range completedMedias {
 		if err := mediaCompleted.InsertBlobs(); err != nil {
-			return err
+			return nil, err
 		}
 		if err := db.StageObject(mediaCompleted); err != nil {
-			return fmt.Errorf("unable to stage modified object %s: %v", mediaCompleted, err)
+			return nil, fmt.Errorf("unable to stage modified object %s: %v", mediaCompleted, err)
 		}
 	}
 
@@ -403,35 +686,69 @@ func (r *Repository) Add(paths ...string) error {
 	for _, path := range paths {
 		relpath, err := r.GetFileRelativePath(path)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		pathDeletions, err := r.findObjectsLastCheckedBefore(buildTime, relpath)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		for _, pathDeletion := range pathDeletions {
+			// Objects present in ignored files were not checked but still exist
+			if fileErrors.Contains(objectRelativePath(pathDeletion)) {
+				continue
+			}
+			deletions = append(deletions, pathDeletion)
 		}
-		deletions = append(deletions, pathDeletions...)
 	}
 
 	// Check for dead medias only when adding the root directory.
 	// For example, when adding a file, it can contains references to medias stored in a directory outside the given path.
-	if slices.Contains(paths, CurrentConfig().RootDirectory) { // ex: nt add .
+	// Medias referenced only by ignored files were not checked and must be kept.
+	if slices.Contains(paths, CurrentConfig().RootDirectory) && len(fileErrors) == 0 { // ex: nt add .
 		// As we walked the whole hierarchy, all medias must have be checked.
 		mediaDeletions, err := CurrentRepository().FindMediasLastCheckedBefore(buildTime)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, mediaDeletion := range mediaDeletions {
 			deletions = append(deletions, mediaDeletion)
 		}
 	}
 
+	// Select deleted notes first as their links, reminders, and flashcards are listed before them
+	skippedFiles := make(map[string]bool)
+	for _, deletion := range deletions {
+		note, ok := deletion.(*Note)
+		if !ok || skippedNotes[note.OID] {
+			continue
+		}
+		note.ForceState(Deleted)
+		skipped, err := skip(note)
+		if err != nil {
+			return nil, err
+		}
+		if skipped {
+			skippedFiles[note.FileOID] = true
+		}
+	}
+	// Unchanged files mark all their notes as checked.
+	// Reset the modification time so that the next add parses the file again and detects the skipped deletions.
+	for fileOID := range skippedFiles {
+		if _, err := db.Client().Exec(`UPDATE file SET mtime = ? WHERE oid = ?`, timeToSQL(time.Time{}), fileOID); err != nil {
+			return nil, err
+		}
+	}
 	for _, deletion := range deletions {
+		// Notes skipped when modified were not checked but still exist
+		if skippedNotes[deletion.UniqueOID()] || skippedNotes[noteOIDOf(deletion)] || skippedFiles[deletion.UniqueOID()] {
+			continue
+		}
 		deletion.ForceState(Deleted)
 		if err := deletion.Save(); err != nil {
-			return err
+			return nil, err
 		}
 		if err := db.StageObject(deletion); err != nil {
-			return fmt.Errorf("unable to stage deleted object %s: %v", deletion, err)
+			return nil, fmt.Errorf("unable to stage deleted object %s: %v", deletion, err)
 		}
 	}
 
@@ -446,6 +763,9 @@ func (r *Repository) Add(paths ...string) error {
 			return err
 		}
 		for _, relation := range dependencies {
+			if skippedNotes[relation.SourceOID] {
+				continue
+			}
 			dependentObject, err := db.ReadLastStagedOrCommittedObjectFromDB(relation.SourceOID)
 			if err != nil {
 				return err
@@ -475,60 +795,239 @@ func (r *Repository) Add(paths ...string) error {
 		// Refresh content after having processed all notes (useful when a note include a note processed later)
 		changed, err := note.Refresh()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		// Save relations only now that we know existing dependencies really exist
 		if err := r.UpdateRelations(note); err != nil {
-			return err
+			return nil, err
 		}
 		if !changed {
 			continue
 		}
 		if err := note.Save(); err != nil {
-			return err
+			return nil, err
 		}
 		dependencies, err := r.FindRelationsTo(note.UniqueOID())
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, relation := range dependencies {
+			if skippedNotes[relation.SourceOID] {
+				continue
+			}
 			dependentObject, err := db.ReadLastStagedOrCommittedObjectFromDB(relation.SourceOID)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			CurrentLogger().Infof("Reprocessing dependent object %s...", dependentObject)
 			changed, err := dependentObject.Refresh()
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if changed {
 				if err := db.StageObject(dependentObject); err != nil {
-					return fmt.Errorf("unable to stage modified dependent object %s: %v", dependentObject, err)
+					return nil, fmt.Errorf("unable to stage modified dependent object %s: %v", dependentObject, err)
 				}
 				traversedRefreshedObjects[relation.SourceOID] = true
 				if err := dependentObject.Save(); err != nil {
-					return err
+					return nil, err
 				}
 				if err := r.UpdateRelations(dependentObject); err != nil {
-					return err
+					return nil, err
 				}
 				if err := refreshDependencies(relation.SourceOID); err != nil {
-					return err
+					return nil, err
 				}
 			}
 		}
 	}
 
+	summary := db.index.StagingArea.summarizeChanges(stagingAreaBefore)
+	summary.FileErrors = fileErrors
+
+	if dryRun {
+		// Discard all changes (the transaction is rolled back)
+		db.index.StagingArea = stagingAreaBefore
+		return summary, nil
+	}
+
 	// Don't forget to commit
 	if err := db.CommitTransaction(); err != nil {
-		return err
+		return nil, err
 	}
 	// And to persist the index
 	if err := db.index.Save(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// generateBlobs generates the blobs of medias using a pool of workers sized by ConfigMedias.Parallel.
+// Remaining medias are skipped when the context is canceled and the processed medias are returned with the context error.
+func generateBlobs(ctx context.Context, medias []*Media, progress chan<- MediaProgress) ([]*Media, error) {
+	jobs := make(chan *Media)
+	results := make(chan *Media)
+	countWorkers := CurrentConfig().ConfigFile.Medias.Parallel
+	if countWorkers < 1 {
+		countWorkers = 1
+	}
+	var wg sync.WaitGroup
+	for w := 1; w <= countWorkers; w++ {
+		wg.Add(1)
+		go func(workerNum int) {
+			defer wg.Done()
+			for media := range jobs {
+				CurrentLogger().Infof("[worker %d] Generating blobs for %s...\n", workerNum, media.RelativePath)
+				media.UpdateBlobs()
+				results <- media
+			}
+		}(w)
+	}
+	go func() {
+		defer close(jobs)
+		for _, media := range medias {
+			select {
+			case jobs <- media:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Wait for running workers to end even after a cancellation
+	var completedMedias []*Media
+	for media := range results {
+		completedMedias = append(completedMedias, media)
+		if progress != nil {
+			progress <- MediaProgress{
+				RelativePath: media.RelativePath,
+				Completed:    len(completedMedias),
+				Total:        len(medias),
+			}
+		}
+	}
+	return completedMedias, ctx.Err()
+}
+
+// deleteUnreferencedBlobs removes the blobs of medias that are not referenced in database.
+func (r *Repository) deleteUnreferencedBlobs(medias []*Media) error {
+	for _, media := range medias {
+		for _, blob := range media.BlobRefs {
+			// Blobs are shared between medias with the same content
+			existingBlob, err := r.FindBlobFromOID(blob.OID)
+			if err != nil {
+				return err
+			}
+			if existingBlob != nil {
+				continue
+			}
+			if err := os.Remove(CurrentDB().blobPath(blob.OID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Restore implements the command `nt restore`.
+// Only staged changes (--staged) can be restored. Other staged files are left intact.
+func (r *Repository) Restore(paths []string, staged bool) error {
+	if !staged {
+		return fmt.Errorf("restoring the working tree is not supported (use --staged)")
+	}
+	var relativePaths []string
+	for _, path := range r.normalizePaths(paths...) {
+		relativePath, err := r.GetFileRelativePath(path)
+		if err != nil {
+			return err
+		}
+		relativePaths = append(relativePaths, relativePath)
+	}
+	return CurrentDB().Restore(relativePaths)
+}
+
+// CommitWithMessage records the staged changes in a new commit described by the given message.
+// The message is optional.
+func (r *Repository) CommitWithMessage(msg string) error {
+	return CurrentDB().Commit(msg)
+}
+
+// Remove implements the command `nt rm`.
+// Objects present in the given paths are staged as deleted. Files are also deleted
+// from the working tree unless keepFile is true (they will be added again by the next nt add).
+func (r *Repository) Remove(paths []string, keepFile bool) error {
+	db := CurrentDB()
+
+	// Run all queries inside the same transaction
+	err := db.BeginTransaction()
+	if err != nil {
 		return err
 	}
+	defer db.RollbackTransaction()
+
+	// All objects were checked before now
+	buildTime := clock.Now().Add(time.Second)
 
+	var deletedFiles []string
+	for _, path := range r.normalizePaths(paths...) {
+		relativePath, err := r.GetFileRelativePath(path)
+		if err != nil {
+			return err
+		}
+		if relativePath == "." {
+			relativePath = ""
+		}
+		objects, err := r.findObjectsLastCheckedBefore(buildTime, relativePath)
+		if err != nil {
+			return err
+		}
+		// Objects are searched by prefix (ex: "notes/go" also matches "notes/golang.md")
+		var deletions []StatefulObject
+		for _, object := range objects {
+			if matchRelativePaths(objectRelativePath(object), []string{relativePath}) {
+				deletions = append(deletions, object)
+			}
+		}
+		if len(deletions) == 0 {
+			return fmt.Errorf("pathspec %q did not match any files", relativePath)
+		}
+		for _, deletion := range deletions {
+			deletion.ForceState(Deleted)
+			if err := deletion.Save(); err != nil {
+				return err
+			}
+			if err := db.StageObject(deletion); err != nil {
+				return fmt.Errorf("unable to stage deleted object %s: %v", deletion, err)
+			}
+			if file, ok := deletion.(*File); ok {
+				deletedFiles = append(deletedFiles, file.RelativePath)
+			}
+		}
+	}
+
+	if err := db.CommitTransaction(); err != nil {
+		return err
+	}
+	if err := db.index.Save(); err != nil {
+		return err
+	}
+
+	if keepFile {
+		return nil
+	}
+	for _, relativePath := range deletedFiles {
+		CurrentLogger().Infof("Removing %s...", relativePath)
+		err := os.Remove(r.GetAbsolutePath(relativePath))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -683,16 +1182,35 @@ func (r *Repository) Lint(ruleNames []string, paths ...string) (*LintResult, err
 	 * and is still present in the database objects even so the media has been deleted and
 	 * not added since.
 	 */
+	return r.lint(ruleNames, r.normalizePaths(paths...), func(stat fs.FileInfo) bool {
+		return true
+	})
+}
+
+// LintChanged checks linter rules only on files modified after the given time.
+// Rules requiring repository-wide inventories (ex: dead wikilinks) still consider all files
+// but only violations for the modified files are reported.
+func (r *Repository) LintChanged(since time.Time, ruleNames []string) (*LintResult, error) {
+	return r.lint(ruleNames, r.normalizePaths(), func(stat fs.FileInfo) bool {
+		return stat.ModTime().After(since)
+	})
+}
+
+// lint checks linter rules on files under the given absolute paths satisfying the filter.
+func (r *Repository) lint(ruleNames []string, paths []string, filter func(stat fs.FileInfo) bool) (*LintResult, error) {
 	var result LintResult
 
-	paths = r.normalizePaths(paths...)
 	err := r.walk(paths, func(path string, stat fs.FileInfo) error {
+		if !filter(stat) {
+			return nil
+		}
+
 		CurrentLogger().Debugf("Processing %s...\n", path)
 
 		// Work without the database
 		file, err := ParseFile(path)
 		if err != nil {
-			return err
+			return r.collectFileError(&result.FileErrors, path, err)
 		}
 
 		// Ignore ignorable files
@@ -703,7 +1221,7 @@ func (r *Repository) Lint(ruleNames []string, paths ...string) (*LintResult, err
 		// Check file
 		violations, err := file.Lint(ruleNames)
 		if err != nil {
-			return err
+			return r.collectFileError(&result.FileErrors, path, err)
 		}
 		if len(violations) > 0 {
 			result.Append(violations...)
@@ -720,6 +1238,73 @@ func (r *Repository) Lint(ruleNames []string, paths ...string) (*LintResult, err
 	return &result, nil
 }
 
+// LintReader lints a file content instead of the file on disk (ex: unsaved editor buffer).
+// The relative path determines the rules to apply and is used in violations.
+func (r *Repository) LintReader(relativePath string, in io.Reader, ruleNames []string) (*LintResult, error) {
+	var result LintResult
+
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	file, err := ParseFileFromBytes(filepath.ToSlash(filepath.Clean(relativePath)), content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore ignorable files
+	if file.HasTag("ignore") {
+		return &result, nil
+	}
+
+	violations, err := file.Lint(ruleNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		result.Append(violations...)
+		result.AffectedFiles += 1
+	}
+	result.AnalyzedFiles += 1
+
+	return &result, nil
+}
+
+// OutlineEntry is a heading in the outline of a file.
+type OutlineEntry struct {
+	Level int
+	Text  string
+	Line  int // 1-based index based on Markdown file
+	// True when the heading is a note, false for organizational sections
+	Note bool
+	Kind NoteKind
+}
+
+// Outline returns the heading hierarchy of a file.
+func (r *Repository) Outline(relativePath string) ([]OutlineEntry, error) {
+	md, err := ParseMarkdownFile(r.GetAbsolutePath(relativePath))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []OutlineEntry
+	err = md.WalkSections(func(parent *MarkdownSection, current *MarkdownSection, children []*MarkdownSection) error {
+		ok, kind, _ := isSupportedNote(current.HeadingText)
+		entries = append(entries, OutlineEntry{
+			Level: current.HeadingLevel,
+			Text:  current.HeadingText,
+			Line:  current.FileLineStart,
+			Note:  ok,
+			Kind:  kind,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 // CountObjectsByType returns the total number of objects for every type.
 func (r *Repository) CountObjectsByType() (map[string]int, error) {
 	// Count object per type
@@ -758,13 +1343,48 @@ func (r *Repository) CountObjectsByType() (map[string]int, error) {
 	}, nil
 }
 
+// DiffRemote shows changes a push would make on the origin for notes under the given paths.
+func (r *Repository) DiffRemote(paths []string) (string, error) {
+	diffs, err := r.DiffRemoteObjects(paths)
+	if err != nil {
+		return "", err
+	}
+	return FormatPatches(diffs), nil
+}
+
+// DiffRemoteObjects works like DiffRemote but returns the changes of every note.
+func (r *Repository) DiffRemoteObjects(paths []string) ([]*ObjectDiff, error) {
+	var relativePaths []string
+	for _, path := range r.normalizePaths(paths...) {
+		relativePath, err := r.GetFileRelativePath(path)
+		if err != nil {
+			return nil, err
+		}
+		relativePaths = append(relativePaths, relativePath)
+	}
+	return CurrentDB().DiffRemoteObjects(relativePaths)
+}
+
 // Diff show changes between commits and working tree.
 func (r *Repository) Diff(staged bool) (string, error) {
+	diffs, err := r.DiffObjects(staged)
+	if err != nil {
+		return "", err
+	}
+	return FormatPatches(diffs), nil
+}
+
+// DiffObjects works like Diff but returns the changes of every note.
+func (r *Repository) DiffObjects(staged bool) ([]*ObjectDiff, error) {
 	// Enable dry-run mode to not generate blobs
+	dryRun := CurrentConfig().DryRun
 	CurrentConfig().DryRun = true
+	defer func() {
+		CurrentConfig().DryRun = dryRun
+	}()
 
 	if staged {
-		return CurrentDB().Diff()
+		return CurrentDB().DiffObjects()
 	}
 
 	// Any object not updated after this date will be considered as deletions
@@ -780,7 +1400,7 @@ func (r *Repository) Diff(staged bool) (string, error) {
 	// and rollback the transaction to have no side-effects.
 	err := db.BeginTransaction()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer db.RollbackTransaction()
 
@@ -829,54 +1449,53 @@ func (r *Repository) Diff(staged bool) (string, error) {
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Find deleted notes for every path
 	relpath, err := r.GetFileRelativePath(path)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	deletedNotes, err := r.FindNotesLastCheckedBefore(buildTime, relpath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var diff strings.Builder
+	var diffs []*ObjectDiff
 	// Diff updated notes
 	for _, noteAfter := range updatedNotes {
 		objectBefore, err := db.ReadLastStagedOrCommittedObject(noteAfter.OID)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		noteContentBefore := ""
+		var noteBefore *Note
 		if objectBefore != nil {
-			noteBefore := objectBefore.(*Note)
-			noteContentBefore = noteBefore.ContentRaw
+			noteBefore = objectBefore.(*Note)
 		}
-		noteContentAfter := noteAfter.ContentRaw
-		patch := godiffpatch.GeneratePatch(noteAfter.RelativePath, noteContentBefore, noteContentAfter)
-		diff.WriteString(patch)
+		diffs = append(diffs, &ObjectDiff{
+			Before: noteBefore,
+			After:  noteAfter,
+		})
 	}
 	// Diff deleted notes
 	for _, noteAfter := range deletedNotes {
 		objectBefore, err := db.ReadLastStagedOrCommittedObject(noteAfter.OID)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		noteBefore := objectBefore.(*Note)
-		noteContentBefore := noteBefore.ContentRaw
-		noteContentAfter := ""
-		patch := godiffpatch.GeneratePatch(noteAfter.RelativePath, noteContentBefore, noteContentAfter)
-		diff.WriteString(patch)
+		diffs = append(diffs, &ObjectDiff{
+			Before: objectBefore.(*Note),
+		})
 	}
+	sortObjectDiffs(diffs)
 
 	// Don't forget to rollback
 	if err := db.RollbackTransaction(); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return diff.String(), nil
+	return diffs, nil
 }
 
 /* Statistics */
@@ -910,6 +1529,7 @@ func NewStatsInDBEmpty() *StatsInDB {
 			KindTodo:       0,
 			KindArtwork:    0,
 			KindSnippet:    0,
+			KindChecklist:  0,
 		},
 		Tags:       map[string]int{},
 		Attributes: map[string]int{},
@@ -963,6 +1583,359 @@ func (r *Repository) StatsInDB() (*StatsInDB, error) {
 	}, nil
 }
 
+// KindBreakdown counts notes per kind.
+type KindBreakdown map[NoteKind]int
+
+// StatsByTag returns the number of notes per kind for every tag.
+func (r *Repository) StatsByTag() (map[string]KindBreakdown, error) {
+	result := make(map[string]KindBreakdown)
+
+	// Same approach as CountTags() while preserving the note kind
+	rows, err := CurrentDB().Client().Query(`
+		SELECT note_tag.tag, note.kind, count(*)
+		FROM note_tag
+		JOIN note ON note.oid = note_tag.note_oid
+		GROUP BY note_tag.tag, note.kind;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tag string
+		var kind string
+		var count int
+
+		err = rows.Scan(
+			&tag,
+			&kind,
+			&count,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := result[tag]; !ok {
+			result[tag] = make(KindBreakdown)
+		}
+		result[tag][NoteKind(kind)] = count
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type ChecklistProgress struct {
+	// Long title of the checklist note
+	Title string `json:"title"`
+	// Location of the checklist note
+	RelativePath string `json:"relativePath"`
+	// Number of checked items
+	Done int `json:"done"`
+	// Number of items
+	Total int `json:"total"`
+}
+
+// Percent returns the completion between 0 and 100.
+func (c ChecklistProgress) Percent() int {
+	if c.Total == 0 {
+		return 0
+	}
+	return c.Done * 100 / c.Total
+}
+
+// StatsChecklists returns the completion of every checklist.
+func (r *Repository) StatsChecklists() ([]ChecklistProgress, error) {
+	notes, err := r.FindChecklists()
+	if err != nil {
+		return nil, err
+	}
+	var result []ChecklistProgress
+	for _, note := range notes {
+		result = append(result, ChecklistProgress{
+			Title:        note.LongTitle,
+			RelativePath: note.RelativePath,
+			Done:         note.ItemsDone,
+			Total:        note.ItemsTotal,
+		})
+	}
+	return result, nil
+}
+
+// TotalWords returns the number of words in the notes under the given paths (ex: "projects/").
+// All notes are considered when no path is given.
+func (r *Repository) TotalWords(paths ...string) (int, error) {
+	var conditions []string
+	var args []any
+	for _, path := range paths {
+		if filepath.IsAbs(path) {
+			relativePath, err := r.GetFileRelativePath(path)
+			if err != nil {
+				return 0, err
+			}
+			path = relativePath
+		}
+		path = strings.TrimSuffix(filepath.ToSlash(path), "/")
+		if path == "." || path == "" {
+			// Include all notes
+			conditions = nil
+			args = nil
+			break
+		}
+		// Match a file or a directory
+		conditions = append(conditions, "relative_path = ? OR relative_path LIKE ?")
+		args = append(args, path, path+"/%")
+	}
+
+	query := `SELECT coalesce(sum(word_count), 0) FROM note`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " OR ")
+	}
+	var count int
+	if err := CurrentDB().Client().QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// HealthStats counts problems in the repository.
+type HealthStats struct {
+	// Number of medias referenced by notes but missing on disk
+	DanglingMedias int `json:"danglingMedias"`
+	// Number of wikilinks pointing to a missing file or section
+	DeadWikilinks int `json:"deadWikilinks"`
+	// Number of notes not referenced by any other note
+	OrphanNotes int `json:"orphanNotes"`
+}
+
+// HealthStats reports dangling medias, dead wikilinks, and orphan notes.
+// Wikilinks are checked using the same inventory as the linter.
+func (r *Repository) HealthStats() (*HealthStats, error) {
+	var result HealthStats
+
+	if err := CurrentDB().Client().QueryRow(`SELECT count(*) FROM media WHERE dangling = 1`).Scan(&result.DanglingMedias); err != nil {
+		return nil, err
+	}
+
+	if err := loadSectionsInventory(); err != nil {
+		return nil, err
+	}
+
+	// Wikilinks of notes referenced from other notes (ex: "go#Note: History")
+	linkedWikilinks := make(map[string]bool)
+	err := r.walk([]string{CurrentConfig().RootDirectory}, func(path string, stat fs.FileInfo) error {
+		file, err := ParseFile(path)
+		if err != nil {
+			return err
+		}
+
+		violations, err := NoDeadWikilink(file, nil)
+		if err != nil {
+			return err
+		}
+		result.DeadWikilinks += len(violations)
+
+		for _, wikilink := range ParseWikilinks(file.Body) {
+			if wikilink.Section() == "" {
+				continue
+			}
+			searchedPath := text.TrimExtension(wikilink.Path())
+			if wikilink.Anchored() {
+				searchedPath = text.TrimExtension(file.RelativePath)
+			}
+			for path := range sectionsInventory {
+				if strings.HasSuffix(path, "/"+searchedPath) { // Match full filename
+					linkedWikilinks[strings.TrimPrefix(path, "/")+"#"+wikilink.Section()] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Notes can also be referenced using attributes (ex: references)
+	relationTargets := make(map[string]bool)
+	rows, err := CurrentDB().Client().Query(`SELECT DISTINCT target_oid FROM relation WHERE target_kind = 'note'`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var oid string
+		if err := rows.Scan(&oid); err != nil {
+			return nil, err
+		}
+		relationTargets[oid] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = QueryNotesFunc(CurrentDB().Client(), func(note *Note) error {
+		if !linkedWikilinks[note.Wikilink] && !relationTargets[note.OID] {
+			result.OrphanNotes++
+		}
+		return nil
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Supported bucket sizes for timeline statistics
+const (
+	TimelineDay   = "day"
+	TimelineWeek  = "week"
+	TimelineMonth = "month"
+)
+
+type TimelineBucket struct {
+	// Start of the bucket (inclusive)
+	Start time.Time `json:"start"`
+	// Number of notes created during the bucket
+	Notes int `json:"notes"`
+	// Number of flashcards created during the bucket
+	Flashcards int `json:"flashcards"`
+	// Number of words in the notes created during the bucket
+	Words int `json:"words"`
+}
+
+// StatsTimeline returns the number of notes and flashcards created per day, week, or month.
+// Buckets without creations are included to ease the rendering of charts.
+func (r *Repository) StatsTimeline(bucket string) ([]TimelineBucket, error) {
+	if !slices.Contains([]string{TimelineDay, TimelineWeek, TimelineMonth}, bucket) {
+		return nil, fmt.Errorf("unsupported timeline bucket %q", bucket)
+	}
+
+	notesCreatedAt, notesWords, err := r.queryNoteCreations()
+	if err != nil {
+		return nil, err
+	}
+	flashcardsCreatedAt, err := r.queryCreationTimes(`SELECT created_at FROM flashcard`)
+	if err != nil {
+		return nil, err
+	}
+
+	countNotes := make(map[time.Time]int)
+	countFlashcards := make(map[time.Time]int)
+	countWords := make(map[time.Time]int)
+	var first, last time.Time
+	register := func(createdAt time.Time, counts map[time.Time]int) time.Time {
+		start := timelineBucketStart(createdAt, bucket)
+		counts[start]++
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if last.IsZero() || start.After(last) {
+			last = start
+		}
+		return start
+	}
+	for i, createdAt := range notesCreatedAt {
+		start := register(createdAt, countNotes)
+		countWords[start] += notesWords[i]
+	}
+	for _, createdAt := range flashcardsCreatedAt {
+		register(createdAt, countFlashcards)
+	}
+
+	var result []TimelineBucket
+	if first.IsZero() {
+		return result, nil
+	}
+	for start := first; !start.After(last); start = timelineNextBucket(start, bucket) {
+		result = append(result, TimelineBucket{
+			Start:      start,
+			Notes:      countNotes[start],
+			Flashcards: countFlashcards[start],
+			Words:      countWords[start],
+		})
+	}
+	return result, nil
+}
+
+// queryNoteCreations returns the creation time and the word count of every note.
+func (r *Repository) queryNoteCreations() ([]time.Time, []int, error) {
+	var createdAts []time.Time
+	var wordCounts []int
+
+	rows, err := CurrentDB().Client().Query(`SELECT created_at, word_count FROM note`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var createdAt string
+		var wordCount int
+		if err := rows.Scan(&createdAt, &wordCount); err != nil {
+			return nil, nil, err
+		}
+		createdAts = append(createdAts, timeFromSQL(createdAt))
+		wordCounts = append(wordCounts, wordCount)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return createdAts, wordCounts, nil
+}
+
+func (r *Repository) queryCreationTimes(query string) ([]time.Time, error) {
+	var result []time.Time
+
+	rows, err := CurrentDB().Client().Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var createdAt string
+		if err := rows.Scan(&createdAt); err != nil {
+			return nil, err
+		}
+		result = append(result, timeFromSQL(createdAt))
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// timelineBucketStart returns the start of the bucket containing the given time.
+func timelineBucketStart(t time.Time, bucket string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch bucket {
+	case TimelineWeek:
+		// Weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case TimelineMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return day
+}
+
+// timelineNextBucket returns the start of the bucket following the given one.
+func timelineNextBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case TimelineWeek:
+		return start.AddDate(0, 0, 7)
+	case TimelineMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
 type Stats struct {
 	OnDisk *StatsOnDisk
 	InDB   *StatsInDB
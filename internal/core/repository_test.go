@@ -1,9 +1,15 @@
 package core
 
 import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/julien-sobczak/the-notewriter/pkg/text"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
@@ -135,6 +141,82 @@ func TestRepositoryGetRelativePath(t *testing.T) {
 	}
 }
 
+func TestOutermostPaths(t *testing.T) {
+	assert.Equal(t, []string{"/notes"}, outermostPaths([]string{"/notes", "/notes/go.md", "/notes/sub"}))
+	assert.Equal(t, []string{"/notes/sub", "/notes-archive"}, outermostPaths([]string{"/notes/sub", "/notes-archive", "/notes/sub"}))
+	assert.Equal(t, []string{"/"}, outermostPaths([]string{"/notes", "/"}))
+}
+
+func TestWalk(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "notes/sub"), os.ModePerm))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "build"), os.ModePerm))
+	MustWriteFile(t, "notes/a.md", "# A")
+	MustWriteFile(t, "notes/sub/b.md", "# B")
+	MustWriteFile(t, "build/c.md", "# C")
+	MustWriteFile(t, "build/keep.md", "# Keep")
+
+	walk := func(paths ...string) []string {
+		var results []string
+		err := CurrentRepository().walk(paths, func(path string, stat fs.FileInfo) error {
+			relpath, err := CurrentRepository().GetFileRelativePath(path)
+			require.NoError(t, err)
+			results = append(results, relpath)
+			return nil
+		})
+		require.NoError(t, err)
+		return results
+	}
+
+	// Nested paths are walked once
+	assert.Equal(t, []string{"notes/a.md", "notes/sub/b.md"}, walk(filepath.Join(root, "notes"), filepath.Join(root, "notes/sub")))
+
+	// Ignored directories are skipped
+	CurrentConfig().IgnoreFile = IgnoreFile{Entries: GlobPaths{"build/"}}
+	assert.Equal(t, []string{"notes/a.md", "notes/sub/b.md"}, walk(root))
+
+	// Unless files are included again
+	CurrentConfig().IgnoreFile = IgnoreFile{Entries: GlobPaths{"build/", "!build/keep.md"}}
+	assert.Equal(t, []string{"build/keep.md", "notes/a.md", "notes/sub/b.md"}, walk(root))
+
+	// Ignore files in subdirectories are applied relative to their directory
+	MustWriteFile(t, "notes/sub/.ntignore", "/b.md\n")
+	assert.Equal(t, []string{"build/keep.md", "notes/a.md"}, walk(root))
+	assert.Empty(t, walk(filepath.Join(root, "notes/sub/b.md")))
+}
+
+func TestWalkSymlinks(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+	outside := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "notes"), os.ModePerm))
+	MustWriteFile(t, "notes/a.md", "# A")
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "b.md"), []byte("# B"), 0644))
+	// Self-referential symlink
+	require.NoError(t, os.Symlink(filepath.Join(root, "notes"), filepath.Join(root, "notes/loop")))
+	// Symlinked directory and file
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "shared")))
+	require.NoError(t, os.Symlink(filepath.Join(root, "notes/a.md"), filepath.Join(root, "c.md")))
+
+	walk := func() []string {
+		var results []string
+		err := CurrentRepository().walk([]string{root}, func(path string, stat fs.FileInfo) error {
+			relpath, err := CurrentRepository().GetFileRelativePath(path)
+			require.NoError(t, err)
+			results = append(results, relpath)
+			assert.True(t, stat.Mode().IsRegular())
+			return nil
+		})
+		require.NoError(t, err)
+		return results
+	}
+
+	// Symlinks are ignored by default
+	assert.Equal(t, []string{"notes/a.md"}, walk())
+
+	CurrentConfig().ConfigFile.Core.FollowSymlinks = true
+	assert.Equal(t, []string{"c.md", "notes/a.md", "shared/b.md"}, walk())
+}
+
 func TestStatsInDB(t *testing.T) {
 
 	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
@@ -171,3 +253,392 @@ func TestStatsInDB(t *testing.T) {
 		"title":  3,
 	}, stats.Attributes)
 }
+
+func TestStatsByTag(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	stats, err := CurrentRepository().StatsByTag()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]KindBreakdown{
+		"go": {
+			KindReference: 1,
+			KindFlashcard: 1,
+			KindTodo:      1,
+		},
+		"history": {
+			KindReference: 1,
+		},
+	}, stats)
+}
+
+func TestStatsTimeline(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	// Wednesday
+	FreezeAt(t, time.Date(2023, time.Month(1), 4, 12, 30, 0, 0, time.UTC))
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	timeline, err := CurrentRepository().StatsTimeline(TimelineWeek)
+	require.NoError(t, err)
+	require.Len(t, timeline, 1)
+	assert.Equal(t, time.Date(2023, time.Month(1), 2, 0, 0, 0, 0, time.UTC), timeline[0].Start) // Monday
+	assert.Equal(t, 3, timeline[0].Notes)
+	assert.Equal(t, 1, timeline[0].Flashcards)
+	totalWords, err := CurrentRepository().TotalWords()
+	require.NoError(t, err)
+	assert.Greater(t, totalWords, 0)
+	assert.Equal(t, totalWords, timeline[0].Words)
+
+	timeline, err = CurrentRepository().StatsTimeline(TimelineMonth)
+	require.NoError(t, err)
+	require.Len(t, timeline, 1)
+	assert.Equal(t, time.Date(2023, time.Month(1), 1, 0, 0, 0, 0, time.UTC), timeline[0].Start)
+
+	_, err = CurrentRepository().StatsTimeline("year")
+	require.ErrorContains(t, err, "unsupported timeline bucket")
+}
+
+func TestTotalWords(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "projects"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "projects-old"), 0755))
+
+	MustWriteFile(t, "go.md", `# Go
+
+## Note: Basics
+
+Go is **simple** and [fast](https://go.dev).
+
+`+"```go"+`
+fmt.Println("Hello")
+`+"```"+`
+`)
+	MustWriteFile(t, "projects/a.md", `# A
+
+## Note: A
+
+One two three.
+`)
+	MustWriteFile(t, "projects-old/b.md", `# B
+
+## Note: B
+
+One two.
+`)
+	require.NoError(t, CurrentRepository().Add("."))
+
+	note := MustFindNoteByPathAndTitle(t, "go.md", "Note: Basics")
+	assert.Equal(t, text.CountWords(note.ContentText), note.WordCount)
+	assert.GreaterOrEqual(t, note.WordCount, 5) // Markdown syntax is not counted
+
+	total, err := CurrentRepository().TotalWords()
+	require.NoError(t, err)
+	assert.Equal(t, note.WordCount+3+2, total)
+	total, err = CurrentRepository().TotalWords("projects")
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	total, err = CurrentRepository().TotalWords("projects/", "go.md")
+	require.NoError(t, err)
+	assert.Equal(t, note.WordCount+3, total)
+	total, err = CurrentRepository().TotalWords("missing")
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	// Word counts are recomputed by nt reindex (ex: notes saved before word counts)
+	_, err = CurrentDB().Client().Exec(`UPDATE note SET word_count = 0`)
+	require.NoError(t, err)
+	require.NoError(t, CurrentRepository().Reindex())
+	total, err = CurrentRepository().TotalWords()
+	require.NoError(t, err)
+	assert.Equal(t, note.WordCount+3+2, total)
+}
+
+func TestHealthStats(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+
+	MustWriteFile(t, "go.md", `# Go
+
+## Note: History
+
+See [[#Note: Release]] and [[python#Note: Missing]].
+
+## Note: Release
+
+![](medias/missing.png)
+
+## Note: Orphan
+
+Not referenced.
+`)
+	MustWriteFile(t, "python.md", `# Python
+
+## Note: Python
+
+Unlike [[go#Note: History]], see [[perl]].
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	stats, err := CurrentRepository().HealthStats()
+	require.NoError(t, err)
+	assert.Equal(t, &HealthStats{
+		DanglingMedias: 1,
+		DeadWikilinks:  2, // Missing section + missing file
+		OrphanNotes:    2, // Note: Orphan + Note: Python
+	}, stats)
+}
+
+func TestTimelineBuckets(t *testing.T) {
+	sunday := time.Date(2023, time.Month(1), 8, 23, 59, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, time.Month(1), 8, 0, 0, 0, 0, time.UTC), timelineBucketStart(sunday, TimelineDay))
+	assert.Equal(t, time.Date(2023, time.Month(1), 2, 0, 0, 0, 0, time.UTC), timelineBucketStart(sunday, TimelineWeek))
+	assert.Equal(t, time.Date(2023, time.Month(1), 1, 0, 0, 0, 0, time.UTC), timelineBucketStart(sunday, TimelineMonth))
+
+	start := time.Date(2023, time.Month(1), 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, time.Month(2), 1, 0, 0, 0, 0, time.UTC), timelineNextBucket(start, TimelineDay))
+	assert.Equal(t, time.Date(2023, time.Month(2), 7, 0, 0, 0, 0, time.UTC), timelineNextBucket(start, TimelineWeek))
+}
+
+func TestAddPatch(t *testing.T) {
+	SetUpRepositoryFromTempDir(t)
+	MustWriteFile(t, "go.md", "# Go\n\n## Note: Creator\n\nRob Pike\n\n## Note: Mascot\n\nGopher\n\n## Note: Removed\n\nObsolete\n")
+	require.NoError(t, CurrentRepository().Add("."))
+	require.NoError(t, CurrentDB().Commit("initial commit"))
+	creator := MustFindNoteByPathAndTitle(t, "go.md", "Note: Creator")
+	mascot := MustFindNoteByPathAndTitle(t, "go.md", "Note: Mascot")
+	removed := MustFindNoteByPathAndTitle(t, "go.md", "Note: Removed")
+
+	// Modify two notes, add a new note, and delete a note
+	MustWriteFile(t, "go.md", "# Go\n\n## Note: Creator\n\nRob Pike and Ken Thompson\n\n## Note: Mascot\n\nThe Gopher\n\n## Note: Release\n\n2009\n")
+	var prompts []string
+	_, err := CurrentRepository().AddPatch(context.Background(), func(note *Note) (bool, error) {
+		prompts = append(prompts, fmt.Sprintf("%s %s", note.State(), note.Title))
+		return note.Title == "Note: Creator" || note.Title == "Note: Release", nil
+	}, ".")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"modified Note: Creator",
+		"modified Note: Mascot",
+		"added Note: Release",
+		"deleted Note: Removed",
+	}, prompts)
+
+	stagingArea := CurrentDB().index.StagingArea
+	release := MustFindNoteByPathAndTitle(t, "go.md", "Note: Release")
+	_, ok := stagingArea.ReadStagingObject(creator.OID)
+	assert.True(t, ok)
+	_, ok = stagingArea.ReadStagingObject(release.OID)
+	assert.True(t, ok)
+	_, ok = stagingArea.ReadStagingObject(mascot.OID)
+	assert.False(t, ok)
+	_, ok = stagingArea.ReadStagingObject(removed.OID)
+	assert.False(t, ok)
+
+	// Skipped notes are left untouched
+	assert.Equal(t, mascot.Hash, MustFindNoteByPathAndTitle(t, "go.md", "Note: Mascot").Hash)
+	MustFindNoteByPathAndTitle(t, "go.md", "Note: Removed")
+
+	// Skipped notes are staged by the next add
+	require.NoError(t, CurrentRepository().Add("."))
+	stagingArea = CurrentDB().index.StagingArea
+	_, ok = stagingArea.ReadStagingObject(mascot.OID)
+	assert.True(t, ok)
+	stagingObject, ok := stagingArea.ReadStagingObject(removed.OID)
+	require.True(t, ok)
+	assert.Equal(t, Deleted, stagingObject.State)
+}
+
+func TestAddRenamedFile(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+	MustWriteFile(t, "go.md", "# Go\n\n## Flashcard: Creator\n\nWho created Go?\n\n---\n\nRob Pike\n\n## Note: Reminder\n\n* Upgrade `#reminder-2050-01-01`\n")
+	require.NoError(t, CurrentRepository().Add("."))
+	require.NoError(t, CurrentDB().Commit("initial commit"))
+	fileBefore, err := CurrentRepository().FindFileByRelativePath("go.md")
+	require.NoError(t, err)
+	require.NotNil(t, fileBefore)
+	noteBefore := MustFindNoteByPathAndTitle(t, "go.md", "Flashcard: Creator")
+
+	// Rename the file without editing it
+	require.NoError(t, os.Rename(filepath.Join(root, "go.md"), filepath.Join(root, "golang.md")))
+	require.NoError(t, CurrentRepository().Add("."))
+
+	fileAfter, err := CurrentRepository().FindFileByRelativePath("golang.md")
+	require.NoError(t, err)
+	require.NotNil(t, fileAfter)
+	assert.Equal(t, fileBefore.OID, fileAfter.OID)
+	assert.Equal(t, "golang", fileAfter.Wikilink)
+	fileMissing, err := CurrentRepository().FindFileByRelativePath("go.md")
+	require.NoError(t, err)
+	assert.Nil(t, fileMissing)
+
+	noteAfter := MustFindNoteByPathAndTitle(t, "golang.md", "Flashcard: Creator")
+	assert.Equal(t, noteBefore.OID, noteAfter.OID)
+	assert.Equal(t, "golang#Flashcard: Creator", noteAfter.Wikilink)
+	flashcard, err := CurrentRepository().FindFlashcardByShortTitle("Creator")
+	require.NoError(t, err)
+	require.NotNil(t, flashcard)
+	assert.Equal(t, "golang.md", flashcard.RelativePath)
+	reminders, err := CurrentRepository().FindReminders()
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, "golang.md", reminders[0].RelativePath)
+
+	// The rename is staged as a modification
+	stagingArea := CurrentDB().index.StagingArea
+	assert.Zero(t, stagingArea.CountByState(Added))
+	assert.Zero(t, stagingArea.CountByState(Deleted))
+	stagingObject, ok := stagingArea.ReadStagingObject(fileBefore.OID)
+	require.True(t, ok)
+	assert.Equal(t, Modified, stagingObject.State)
+
+	// The index tracks the new path after the commit
+	require.NoError(t, CurrentDB().Commit("rename"))
+	status, err := CurrentRepository().Status()
+	require.NoError(t, err)
+	assert.NotContains(t, status, "go.md")
+}
+
+func TestAddWithContext(t *testing.T) {
+
+	setUp := func(t *testing.T) string {
+		root := SetUpRepositoryFromTempDir(t)
+		CurrentConfig().ConfigFile.Medias.Parallel = 2
+		require.NoError(t, os.Mkdir(filepath.Join(root, "medias"), os.ModePerm))
+		for _, name := range []string{"a.png", "b.png", "c.png", "d.png"} {
+			require.NoError(t, os.WriteFile(filepath.Join(root, "medias", name), []byte(name), 0644))
+		}
+		MustWriteFile(t, "pictures.md", `# Pictures
+
+## Note: Pictures
+
+![A](medias/a.png)
+![B](medias/b.png)
+![C](medias/c.png)
+![D](medias/d.png)
+`)
+		return root
+	}
+
+	t.Run("Progress", func(t *testing.T) {
+		setUp(t)
+
+		progress := make(chan MediaProgress, 10)
+		_, err := CurrentRepository().AddWithContext(context.Background(), progress, ".")
+		require.NoError(t, err)
+		close(progress)
+
+		var events []MediaProgress
+		for event := range progress {
+			events = append(events, event)
+		}
+		require.Len(t, events, 4)
+		for i, event := range events {
+			assert.Equal(t, i+1, event.Completed)
+			assert.Equal(t, 4, event.Total)
+			assert.Contains(t, []string{"medias/a.png", "medias/b.png", "medias/c.png", "medias/d.png"}, event.RelativePath)
+		}
+		assert.NotZero(t, CurrentDB().index.StagingArea.Count())
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		root := setUp(t)
+
+		// Cancel after the first media
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		progress := make(chan MediaProgress)
+		go func() {
+			<-progress
+			cancel()
+			for range progress {
+				// Drain remaining events
+			}
+		}()
+		_, err := CurrentRepository().AddWithContext(ctx, progress, ".")
+		close(progress)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// Nothing must have been staged
+		assert.Zero(t, CurrentDB().index.StagingArea.Count())
+		count, err := CurrentRepository().CountMedias()
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		// Generated blobs must have been removed
+		var blobs []string
+		err = filepath.WalkDir(filepath.Join(root, ".nt/objects"), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				blobs = append(blobs, path)
+			}
+			return nil
+		})
+		if !os.IsNotExist(err) {
+			require.NoError(t, err)
+		}
+		assert.Empty(t, blobs)
+
+		// Files can be added again
+		_, err = CurrentRepository().AddWithContext(context.Background(), nil, ".")
+		require.NoError(t, err)
+		count, err = CurrentRepository().CountMedias()
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+	})
+
+	t.Run("Cancel before start", func(t *testing.T) {
+		setUp(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := CurrentRepository().AddWithContext(ctx, nil, ".")
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, CurrentDB().index.StagingArea.Count())
+	})
+}
+
+func TestOutline(t *testing.T) {
+	SetUpRepositoryFromFileContent(t, "go.md", `---
+tags: [go]
+---
+
+# Go
+
+## History
+
+### Note: Creation
+
+Go was created at Google.
+
+## Flashcard: Creators
+
+Who created Go?
+
+---
+
+Robert Griesemer, Rob Pike, and Ken Thompson.
+
+`+"```"+`
+# Not a heading
+`+"```"+`
+`)
+
+	entries, err := CurrentRepository().Outline("go.md")
+	require.NoError(t, err)
+	assert.Equal(t, []OutlineEntry{
+		{Level: 1, Text: "Go", Line: 5, Note: false, Kind: KindFree},
+		{Level: 2, Text: "History", Line: 7, Note: false, Kind: KindFree},
+		{Level: 3, Text: "Note: Creation", Line: 9, Note: true, Kind: KindNote},
+		{Level: 2, Text: "Flashcard: Creators", Line: 13, Note: true, Kind: KindFlashcard},
+	}, entries)
+
+	_, err = CurrentRepository().Outline("missing.md")
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+CREATE TABLE note_tag (
+  -- The note OID
+  note_oid TEXT NOT NULL,
+  -- The tag in its canonical form (lowercase without diacritics)
+  tag TEXT NOT NULL,
+
+  PRIMARY KEY (note_oid, tag)
+);
+
+CREATE INDEX index_note_tag_tag ON note_tag(tag);
+
+-- Backfill existing notes.
+-- Diacritics are only removed when notes are saved again.
+INSERT OR IGNORE INTO note_tag (note_oid, tag)
+WITH RECURSIVE split(note_oid, tag, str) AS (
+  SELECT oid, '', tags||',' FROM note
+  UNION ALL SELECT
+  note_oid,
+  substr(str, 0, instr(str, ',')),
+  substr(str, instr(str, ',')+1)
+  FROM split WHERE str!=''
+)
+SELECT note_oid, lower(tag) FROM split WHERE tag!='';
@@ -0,0 +1,2 @@
+ALTER TABLE note DROP COLUMN items_total;
+ALTER TABLE note DROP COLUMN items_done;
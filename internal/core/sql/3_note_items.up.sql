@@ -0,0 +1,2 @@
+ALTER TABLE note ADD COLUMN items_done INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE note ADD COLUMN items_total INTEGER NOT NULL DEFAULT 0;
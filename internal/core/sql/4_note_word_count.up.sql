@@ -0,0 +1 @@
+ALTER TABLE note ADD COLUMN word_count INTEGER NOT NULL DEFAULT 0;
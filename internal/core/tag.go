@@ -0,0 +1,177 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/text"
+	"gopkg.in/yaml.v3"
+)
+
+// Ex: favorite, life-changing, programming/go
+var regexValidTag = regexp.MustCompile("^[\\p{L}\\p{N}_][^\\s`,\\[\\]{}]*$")
+
+// ValidateTag checks a tag can be used inline (ex: `#favorite`) and in the Front Matter.
+func ValidateTag(tag string) error {
+	if !regexValidTag.MatchString(tag) {
+		return fmt.Errorf("invalid tag %q", tag)
+	}
+	return nil
+}
+
+// RenameTag replaces a tag by another one in all files under the given paths.
+// Tags are renamed in the Front Matter, inline (ex: `#favorite`), and when set
+// using the attribute syntax (ex: `@tags: favorite`). Code blocks are left untouched.
+// The number of modified files is returned. Files are not written when dryRun is true.
+func (r *Repository) RenameTag(oldTag, newTag string, dryRun bool, paths ...string) (int, error) {
+	if strings.TrimSpace(oldTag) == "" {
+		return 0, errors.New("missing tag to rename")
+	}
+	if err := ValidateTag(newTag); err != nil {
+		return 0, err
+	}
+	if oldTag == newTag {
+		return 0, nil
+	}
+
+	count := 0
+	paths = r.normalizePaths(paths...)
+	err := r.walk(paths, func(path string, stat fs.FileInfo) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		newContent, err := renameTagInContent(string(content), oldTag, newTag)
+		if err != nil {
+			relativePath, _ := r.GetFileRelativePath(path)
+			return fmt.Errorf("unable to rename tag in %s: %w", relativePath, err)
+		}
+		if newContent == string(content) {
+			return nil
+		}
+		count++
+
+		if dryRun {
+			return nil
+		}
+		CurrentLogger().Debugf("Renaming tag %q in %s...", oldTag, path)
+		return os.WriteFile(path, []byte(newContent), stat.Mode())
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// renameTagInContent replaces a tag in the raw content of a file.
+func renameTagInContent(content string, oldTag, newTag string) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	// Search for the Front Matter (same logic as ParseFile)
+	frontMatterStart := -1
+	frontMatterEnd := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "---") {
+			if frontMatterStart == -1 {
+				frontMatterStart = i
+				continue
+			}
+			frontMatterEnd = i
+			break
+		}
+		if frontMatterStart == -1 && !text.IsBlank(line) {
+			// No Front Matter
+			break
+		}
+	}
+
+	bodyStart := 0
+	if frontMatterStart != -1 && frontMatterEnd != -1 {
+		bodyStart = frontMatterEnd + 1
+		if err := renameTagInFrontMatter(lines, frontMatterStart, frontMatterEnd, oldTag, newTag); err != nil {
+			return "", err
+		}
+	}
+
+	inlineTag := regexp.MustCompile("`#" + regexp.QuoteMeta(oldTag) + "`")
+	attributeTag := regexp.MustCompile("(`@tags\\s*:\\s*)" + regexp.QuoteMeta(oldTag) + "`")
+	insideCodeBlock := false
+	for i := bodyStart; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			insideCodeBlock = !insideCodeBlock
+			continue
+		}
+		if insideCodeBlock {
+			continue
+		}
+		line = inlineTag.ReplaceAllLiteralString(line, "`#"+newTag+"`")
+		line = attributeTag.ReplaceAllString(line, "${1}"+strings.ReplaceAll(newTag, "$", "$$")+"`")
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// renameTagInFrontMatter replaces a tag in the attribute "tags" of the Front Matter
+// delimited by the two given lines. Only the tag values are rewritten to preserve the formatting.
+func renameTagInFrontMatter(lines []string, start, end int, oldTag, newTag string) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[start+1:end], "\n")), &node); err != nil {
+		return err
+	}
+	if node.Kind != yaml.DocumentNode || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	mapping := node.Content[0]
+
+	var valueNodes []*yaml.Node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != "tags" {
+			continue
+		}
+		valueNode := mapping.Content[i+1]
+		switch valueNode.Kind {
+		case yaml.ScalarNode:
+			valueNodes = append(valueNodes, valueNode)
+		case yaml.SequenceNode:
+			valueNodes = append(valueNodes, valueNode.Content...)
+		}
+	}
+
+	for _, valueNode := range valueNodes {
+		if valueNode.Kind != yaml.ScalarNode || valueNode.Value != oldTag {
+			continue
+		}
+		// Node lines are relative to the first line after the opening ---
+		i := start + valueNode.Line
+		line := []rune(lines[i])
+		column := valueNode.Column - 1
+		if column < 0 || column > len(line) {
+			continue
+		}
+		prefix := string(line[:column])
+		suffix := string(line[column:])
+		if suffix == "" {
+			continue
+		}
+		switch valueNode.Style {
+		case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+			quote := suffix[:1]
+			if !strings.HasPrefix(suffix, quote+oldTag+quote) {
+				continue
+			}
+			lines[i] = prefix + quote + newTag + quote + suffix[len(oldTag)+2:]
+		default:
+			if !strings.HasPrefix(suffix, oldTag) {
+				continue
+			}
+			lines[i] = prefix + newTag + suffix[len(oldTag):]
+		}
+	}
+	return nil
+}
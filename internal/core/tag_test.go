@@ -0,0 +1,149 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTag(t *testing.T) {
+	assert.NoError(t, ValidateTag("favorite"))
+	assert.NoError(t, ValidateTag("life-changing"))
+	assert.NoError(t, ValidateTag("programming/go"))
+	assert.NoError(t, ValidateTag("café"))
+	assert.Error(t, ValidateTag(""))
+	assert.Error(t, ValidateTag("#favorite"))
+	assert.Error(t, ValidateTag("two words"))
+	assert.Error(t, ValidateTag("a,b"))
+	assert.Error(t, ValidateTag("`favorite`"))
+}
+
+func TestRenameTag(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "archives"), 0755))
+
+	MustWriteFile(t, "go.md", `---
+tags: [go, favorite, "favorites"]
+---
+
+# Go
+
+## Note: Inline
+
+”#favorite” ”#go”
+
+A favorite language.
+
+## Note: Attribute
+
+”@tags: favorite”
+
+Go is `+"`#favorite`"+`.
+
+## Note: Code
+
+”#go”
+
+`+"```md"+`
+”#favorite”
+`+"```"+`
+`)
+	MustWriteFile(t, "python.md", `---
+tags:
+- python
+- 'favorite'
+---
+
+# Python
+`)
+	MustWriteFile(t, "java.md", `# Java
+
+## Note: Java
+
+”#java”
+`)
+	MustWriteFile(t, "archives/perl.md", `---
+tags: favorite
+---
+
+# Perl
+`)
+
+	readFile := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		require.NoError(t, err)
+		return string(content)
+	}
+	original := readFile("go.md")
+
+	// Invalid tag
+	_, err := CurrentRepository().RenameTag("favorite", "my favorite", false)
+	assert.Error(t, err)
+
+	// Dry run
+	count, err := CurrentRepository().RenameTag("favorite", "top", true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, original, readFile("go.md"))
+
+	// Scoped
+	count, err = CurrentRepository().RenameTag("favorite", "top", false, "archives/")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, UnescapeTestContent(`---
+tags: top
+---
+
+# Perl
+`), readFile("archives/perl.md"))
+	assert.Equal(t, original, readFile("go.md"))
+
+	// All files
+	count, err = CurrentRepository().RenameTag("favorite", "top", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, UnescapeTestContent(`---
+tags: [go, top, "favorites"]
+---
+
+# Go
+
+## Note: Inline
+
+”#top” ”#go”
+
+A favorite language.
+
+## Note: Attribute
+
+”@tags: top”
+
+Go is `+"`#top`"+`.
+
+## Note: Code
+
+”#go”
+
+`+"```md"+`
+”#favorite”
+`+"```"+`
+`), readFile("go.md"))
+	assert.Equal(t, UnescapeTestContent(`---
+tags:
+- python
+- 'top'
+---
+
+# Python
+`), readFile("python.md"))
+
+	// The new tags are found by the parser
+	err = CurrentRepository().Add(".")
+	require.NoError(t, err)
+	note := MustFindNoteByPathAndTitle(t, "go.md", "Note: Inline")
+	assert.Contains(t, note.GetTags(), "top")
+	assert.NotContains(t, note.GetTags(), "favorite")
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/julien-sobczak/the-notewriter/internal/reference"
+)
+
+// DefaultNoteTemplate is used when no template is configured for a kind in .nt/config.
+const DefaultNoteTemplate = `{{with index . "tags"}}---
+tags: [{{.}}]
+---
+
+{{end}}# {{index . "title"}}
+
+## {{index . "kindTitle"}}: {{index . "title"}}
+
+{{with index . "body"}}{{.}}{{else}}{{index . "kindPlaceholder"}}{{end}}
+`
+
+// Prefixes used in note headings
+var noteKindTitles = map[NoteKind]string{
+	KindReference:  "Reference",
+	KindNote:       "Note",
+	KindFlashcard:  "Flashcard",
+	KindCheatsheet: "Cheatsheet",
+	KindQuote:      "Quote",
+	KindJournal:    "Journal",
+	KindTodo:       "TODO",
+	KindArtwork:    "Artwork",
+	KindSnippet:    "Snippet",
+	KindChecklist:  "Checklist",
+}
+
+// Default content when no body is passed to avoid blank notes
+var noteKindPlaceholders = map[NoteKind]string{
+	KindFlashcard: "Question?\n\n---\n\nAnswer.",
+	KindTodo:      "* [ ] Something to do",
+}
+
+// NewNoteFromTemplate renders the template configured for the given kind to create a new note file content.
+// The variable "title" is required. Variables "tags" and "body" are supported by the default template.
+func (r *Repository) NewNoteFromTemplate(kind NoteKind, vars map[string]string) (string, error) {
+	kindTitle, ok := noteKindTitles[kind]
+	if !ok {
+		return "", fmt.Errorf("unsupported kind %q", kind)
+	}
+	if vars["title"] == "" {
+		return "", errors.New("missing title")
+	}
+
+	templateText := DefaultNoteTemplate
+	if value, ok := CurrentConfig().ConfigFile.Templates[string(kind)]; ok {
+		templateText = value
+	}
+
+	tmpl, err := reference.ParseTemplate(templateText)
+	if err != nil {
+		return "", fmt.Errorf("invalid template for kind %q: %w", kind, err)
+	}
+
+	data := map[string]string{
+		"kind":            string(kind),
+		"kindTitle":       kindTitle,
+		"kindPlaceholder": noteKindPlaceholders[kind],
+	}
+	for key, value := range vars {
+		data[key] = value
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNoteFromTemplate(t *testing.T) {
+
+	t.Run("Default template", func(t *testing.T) {
+		root := SetUpRepositoryFromTempDir(t)
+
+		content, err := CurrentRepository().NewNoteFromTemplate(KindTodo, map[string]string{
+			"title": "Backlog",
+			"tags":  "project",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `---
+tags: [project]
+---
+
+# Backlog
+
+## TODO: Backlog
+
+* [ ] Something to do
+`, content)
+
+		// The generated file must satisfy the linter
+		path := filepath.Join(root, "backlog.md")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		parsedFile, err := ParseFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"project"}, parsedFile.GetTags())
+		violations, err := NoFreeNote(parsedFile, nil)
+		require.NoError(t, err)
+		assert.Empty(t, violations)
+		violations, err = NoteTitleMatch(parsedFile, []string{`^TODO: [A-Z]`})
+		require.NoError(t, err)
+		assert.Empty(t, violations)
+		notes := ParseNotes(parsedFile.Body, parsedFile.Slug)
+		require.Len(t, notes, 1)
+		assert.Equal(t, KindTodo, notes[0].Kind)
+		assert.Equal(t, "Backlog", notes[0].ShortTitle)
+	})
+
+	t.Run("Custom template", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+		CurrentConfig().ConfigFile.Templates = map[string]string{
+			"quote": `# {{index . "title" | title}}
+
+## {{index . "kindTitle"}}: {{index . "title" | title}}
+
+> {{index . "body"}}
+`,
+		}
+
+		content, err := CurrentRepository().NewNoteFromTemplate(KindQuote, map[string]string{
+			"title": "on doing",
+			"body":  "Do it now.",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `# On Doing
+
+## Quote: On Doing
+
+> Do it now.
+`, content)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		SetUpRepositoryFromTempDir(t)
+
+		_, err := CurrentRepository().NewNoteFromTemplate(KindFree, map[string]string{"title": "Test"})
+		assert.ErrorContains(t, err, "unsupported kind")
+
+		_, err = CurrentRepository().NewNoteFromTemplate(KindNote, nil)
+		assert.ErrorContains(t, err, "missing title")
+	})
+}
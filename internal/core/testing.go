@@ -23,6 +23,7 @@ func Reset() {
 	dbOnce.Reset()
 	loggerOnce.Reset()
 	sectionsInventoryOnce.Reset()
+	mediasInventoryOnce.Reset()
 	slugInventoryOnce.Reset()
 }
 
@@ -79,6 +80,7 @@ func configureDir(t *testing.T, dirname string) {
 		if err := os.WriteFile(filepath.Join(ntDir, "config"), []byte(`
 [core]
 extensions=["md", "markdown"]
+timezone="UTC"
 
 [medias]
 command="random"
@@ -86,6 +88,8 @@ command="random"
 			t.Fatal(err)
 		}
 	}
+	// Forget singletons initialized by tests not using a temporary directory
+	Reset()
 	// Force the application to consider the temporary directory as the home
 	os.Setenv("NT_HOME", dirname)
 	t.Cleanup(func() {
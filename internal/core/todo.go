@@ -0,0 +1,117 @@
+package core
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/markdown"
+)
+
+// TodoItem is an open task found in a todo note.
+type TodoItem struct {
+	// Text of the task without tags and attributes
+	Text string `json:"text"`
+	// The todo note containing the task
+	NoteOID      string `json:"noteOID"`
+	RelativePath string `json:"relativePath"`
+	// Line number (1-based index) of the task in the file
+	Line int `json:"line"`
+	// Reminder tag present on the task (ex: #reminder-2023-06-01)
+	Reminder string `json:"reminder,omitempty"`
+	// Next occurrence of the reminder (zero if none)
+	DueAt time.Time `json:"dueAt"`
+}
+
+// FindOpenTodos returns the unchecked items of todo notes under the given paths.
+// In todo notes without task list, every list item is a task, or the note itself when
+// no list is present.
+func (r *Repository) FindOpenTodos(paths ...string) ([]TodoItem, error) {
+	relativePaths, err := r.relativePaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, args := notesWhereClause(relativePaths, []NoteKind{KindTodo})
+	notes, err := QueryNotes(CurrentDB().Client(), whereClause+" ORDER BY relative_path, line", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	reItem := regexp.MustCompile(`^\s*[-*+]\s+(?:\[([ xX])\]\s+)?(.*)$`)
+	reReminder := regexp.MustCompile("`(#reminder-\\S+)`")
+
+	var items []TodoItem
+	fileLines := make(map[string][]string)
+	for _, note := range notes {
+		lines, ok := fileLines[note.RelativePath]
+		if !ok {
+			// Read the file to locate tasks, ignoring missing files
+			content, _ := os.ReadFile(r.GetAbsolutePath(note.RelativePath))
+			lines = strings.Split(string(content), "\n")
+			fileLines[note.RelativePath] = lines
+		}
+
+		newItem := func(description, rawLine string, line int) TodoItem {
+			item := TodoItem{
+				Text:         RemoveTagsAndAttributes(description),
+				NoteOID:      note.OID,
+				RelativePath: note.RelativePath,
+				Line:         line,
+			}
+			if match := reReminder.FindStringSubmatch(rawLine); match != nil {
+				item.Reminder = match[1]
+				if dueAt, err := EvaluateTimeExpression(strings.TrimPrefix(match[1], "#reminder-"), CurrentConfig().Location()); err == nil {
+					item.DueAt = dueAt
+				}
+			}
+			return item
+		}
+
+		found := false
+		lastLine := note.Line
+		for _, rawLine := range strings.Split(markdown.CleanCodeBlocks(note.ContentRaw), "\n") {
+			match := reItem.FindStringSubmatch(rawLine)
+			if match == nil {
+				continue
+			}
+			if match[1] != "" && match[1] != " " {
+				// Done
+				continue
+			}
+			if match[1] == "" && note.ItemsTotal > 0 {
+				// Plain list items are tasks only in notes without task list
+				continue
+			}
+			found = true
+			line := note.Line
+			for i := lastLine; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) == strings.TrimSpace(rawLine) {
+					line = i + 1
+					lastLine = i + 1
+					break
+				}
+			}
+			items = append(items, newItem(match[2], rawLine, line))
+		}
+		if !found && note.ItemsTotal == 0 {
+			items = append(items, newItem(note.ShortTitle, note.ContentRaw, note.Line))
+		}
+	}
+
+	return items, nil
+}
+
+// SortTodosByDue sorts items by their next reminder date. Items without reminder come last.
+func SortTodosByDue(items []TodoItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].DueAt.IsZero() {
+			return false
+		}
+		if items[j].DueAt.IsZero() {
+			return true
+		}
+		return items[i].DueAt.Before(items[j].DueAt)
+	})
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOpenTodos(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	FreezeAt(t, time.Date(2023, time.Month(1), 1, 1, 12, 30, 0, time.UTC))
+
+	MustWriteFile(t, "home.md", `# Home
+
+## Todo: Renovation
+
+* [x] Paint the walls
+* [ ] Fix the door
+* [ ] Renew the insurance `+"`#reminder-2023-02-01`"+`
+
+## Todo: Call the plumber
+
+The kitchen sink is leaking.
+`)
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+
+	items, err := CurrentRepository().FindOpenTodos()
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+
+	assert.Equal(t, "go.md", items[0].RelativePath)
+	assert.Equal(t, 30, items[0].Line)
+	assert.Equal(t, "#reminder-2023-06-26", items[0].Reminder)
+	assert.Equal(t, time.Date(2023, time.Month(6), 26, 0, 0, 0, 0, time.UTC), items[0].DueAt)
+
+	assert.Equal(t, "home.md", items[1].RelativePath)
+	assert.Equal(t, "Fix the door", items[1].Text)
+	assert.Equal(t, 6, items[1].Line)
+	assert.Zero(t, items[1].DueAt)
+
+	assert.Equal(t, "Renew the insurance", items[2].Text)
+	assert.Equal(t, 7, items[2].Line)
+	assert.Equal(t, time.Date(2023, time.Month(2), 1, 0, 0, 0, 0, time.UTC), items[2].DueAt)
+
+	// A todo note without list is a task (notes without content are ignored like any other notes)
+	assert.Equal(t, "Call the plumber", items[3].Text)
+	assert.Equal(t, 9, items[3].Line)
+
+	SortTodosByDue(items)
+	assert.Equal(t, "Renew the insurance", items[0].Text)
+	assert.Equal(t, "go.md", items[1].RelativePath)
+	assert.Equal(t, "Fix the door", items[2].Text)
+	assert.Equal(t, "Call the plumber", items[3].Text)
+
+	// Restrict to paths
+	items, err = CurrentRepository().FindOpenTodos("go.md")
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+}
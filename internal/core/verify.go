@@ -0,0 +1,137 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+// RemoteVerifyReport lists the differences between the objects referenced by the origin index
+// and the objects present in the origin.
+type RemoteVerifyReport struct {
+	PackFilesChecked int `json:"packFilesChecked"`
+	BlobsChecked     int `json:"blobsChecked"`
+	// OIDs of pack files and blobs referenced by the origin index but not present
+	MissingPackFiles []string `json:"missingPackFiles"`
+	MissingBlobs     []string `json:"missingBlobs"`
+	// Keys of objects present but not referenced by the origin index
+	ExtraObjects []string `json:"extraObjects"`
+}
+
+// Complete returns true when no referenced object is missing.
+func (r *RemoteVerifyReport) Complete() bool {
+	return len(r.MissingPackFiles) == 0 && len(r.MissingBlobs) == 0
+}
+
+// VerifyRemote checks the origin holds every pack file and blob referenced by its index.
+// Objects are not downloaded except pack files missing locally, which are read to find blobs.
+func (r *Repository) VerifyRemote() (*RemoteVerifyReport, error) {
+	origin := CurrentDB().Origin()
+	if origin == nil {
+		return nil, errors.New("no remote found")
+	}
+
+	data, err := origin.GetObject("index")
+	if errors.Is(err, ErrObjectNotExist) {
+		return nil, errors.New("missing index in remote")
+	}
+	if err != nil {
+		return nil, err
+	}
+	originIndex := NewIndex()
+	if err := originIndex.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	report := new(RemoteVerifyReport)
+
+	// Files pushed in addition to objects
+	expectedKeys := map[string]bool{
+		"index":             true,
+		"info/commit-graph": true,
+		"config":            true,
+		"bundle":            true,
+	}
+	// Orphans are still present until the next push following a gc
+	for _, orphan := range originIndex.OrphanPackFiles {
+		expectedKeys[OIDToPath(orphan.OID)] = true
+	}
+	for _, orphan := range originIndex.OrphanBlobs {
+		expectedKeys[OIDToPath(orphan.OID)] = true
+	}
+
+	var packFileOIDs []string
+	for oid := range originIndex.PackFiles {
+		packFileOIDs = append(packFileOIDs, oid)
+	}
+	sort.Strings(packFileOIDs)
+
+	var blobOIDs []string
+	blobsFound := make(map[string]bool)
+	for _, oid := range packFileOIDs {
+		key := OIDToPath(oid)
+		expectedKeys[key] = true
+		report.PackFilesChecked++
+
+		exists, err := origin.ObjectExists(key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			report.MissingPackFiles = append(report.MissingPackFiles, oid)
+		}
+
+		// Read the pack file to find blobs (local copy first to avoid downloads)
+		packFile, err := CurrentDB().ReadPackFile(oid)
+		if err != nil {
+			if !exists {
+				// Blobs cannot be determined
+				continue
+			}
+			data, err := origin.GetObject(key)
+			if err != nil {
+				return nil, err
+			}
+			packFile = new(PackFile)
+			if err := packFile.Read(bytes.NewReader(data)); err != nil {
+				return nil, err
+			}
+		}
+		for _, packObject := range packFile.PackObjects {
+			for _, blobRef := range packObject.ReadObject().Blobs() {
+				if blobsFound[blobRef.OID] {
+					continue
+				}
+				blobsFound[blobRef.OID] = true
+				blobOIDs = append(blobOIDs, blobRef.OID)
+			}
+		}
+	}
+
+	for _, oid := range blobOIDs {
+		key := OIDToPath(oid)
+		expectedKeys[key] = true
+		report.BlobsChecked++
+
+		exists, err := origin.ObjectExists(key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			report.MissingBlobs = append(report.MissingBlobs, oid)
+		}
+	}
+
+	keys, err := origin.ListObjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if !expectedKeys[key] {
+			report.ExtraObjects = append(report.ExtraObjects, key)
+		}
+	}
+	sort.Strings(report.ExtraObjects)
+
+	return report, nil
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRemote(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+	origin := t.TempDir()
+	CurrentConfig().ConfigFile.Remote = ConfigRemote{
+		Type: "fs",
+		Dir:  origin,
+	}
+
+	err := CurrentRepository().Add(".")
+	require.NoError(t, err)
+	err = CurrentDB().Commit("initial commit")
+	require.NoError(t, err)
+	err = CurrentDB().Push()
+	require.NoError(t, err)
+
+	report, err := CurrentRepository().VerifyRemote()
+	require.NoError(t, err)
+	assert.True(t, report.Complete())
+	assert.Equal(t, 1, report.PackFilesChecked)
+	assert.Greater(t, report.BlobsChecked, 0)
+	assert.Empty(t, report.ExtraObjects)
+
+	// Remove a blob and add an unknown object
+	logo, err := CurrentRepository().FindMediaByRelativePath("medias/go.svg")
+	require.NoError(t, err)
+	require.NotNil(t, logo)
+	blobOID := logo.BlobRefs[0].OID
+	require.NoError(t, os.Remove(filepath.Join(origin, OIDToPath(blobOID))))
+	unknownOID := strings.Repeat("ab", 20)
+	require.NoError(t, os.MkdirAll(filepath.Join(origin, "ab"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(origin, OIDToPath(unknownOID)), []byte("unknown"), 0644))
+
+	report, err = CurrentRepository().VerifyRemote()
+	require.NoError(t, err)
+	assert.False(t, report.Complete())
+	assert.Empty(t, report.MissingPackFiles)
+	assert.Equal(t, []string{blobOID}, report.MissingBlobs)
+	assert.Equal(t, []string{OIDToPath(unknownOID)}, report.ExtraObjects)
+}
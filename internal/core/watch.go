@@ -0,0 +1,178 @@
+package core
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is the delay to wait for other changes before processing changed files.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// WatchResult reports the processing of a changed file.
+type WatchResult struct {
+	RelativePath string
+	// Staged changes (nil in lint-only mode)
+	Summary *AddSummary
+	// Violations (nil except in lint-only mode)
+	Lint *LintResult
+	Err  error
+}
+
+// Watch monitors the given paths and stages changed files (or only lints them) until done is closed.
+// Changes are debounced and fn is called after every processed file.
+func (r *Repository) Watch(paths []string, lintOnly bool, debounce time.Duration, done <-chan struct{}, fn func(*WatchResult)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	relativePaths, err := r.relativePaths(paths)
+	if err != nil {
+		return err
+	}
+
+	// fsnotify is not recursive. All directories must be watched.
+	watchDir := func(dir string) error {
+		return filepath.WalkDir(dir, func(path string, info fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			relativePath, err := r.GetFileRelativePath(path)
+			if err != nil {
+				return err
+			}
+			if mustIgnoreWatchedPath(relativePath, true) {
+				return fs.SkipDir
+			}
+			return watcher.Add(path)
+		})
+	}
+	if err := watchDir(CurrentConfig().RootDirectory); err != nil {
+		return err
+	}
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+
+		case err := <-watcher.Errors:
+			fn(&WatchResult{Err: err})
+
+		case event := <-watcher.Events:
+			relativePath, err := r.GetFileRelativePath(event.Name)
+			if err != nil {
+				continue
+			}
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if stat, err := os.Stat(event.Name); err == nil && stat.IsDir() {
+					// Watch new directories and process files already present (ex: moved directory)
+					if err := watchDir(event.Name); err != nil {
+						fn(&WatchResult{RelativePath: relativePath, Err: err})
+						continue
+					}
+					if !mustIgnoreWatchedPath(relativePath, true) && matchRelativePaths(filepath.ToSlash(relativePath), relativePaths) {
+						pending[relativePath] = true
+						timer.Reset(debounce)
+					}
+					continue
+				}
+			}
+			if mustIgnoreWatchedPath(relativePath, false) || !matchRelativePaths(filepath.ToSlash(relativePath), relativePaths) {
+				continue
+			}
+			pending[relativePath] = true
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			var changedPaths []string
+			for relativePath := range pending {
+				changedPaths = append(changedPaths, relativePath)
+			}
+			sort.Strings(changedPaths)
+			pending = make(map[string]bool)
+
+			for _, relativePath := range changedPaths {
+				fn(r.processWatchedFile(relativePath, lintOnly))
+			}
+		}
+	}
+}
+
+// mustIgnoreWatchedPath returns true for paths that must not trigger a refresh.
+func mustIgnoreWatchedPath(relativePath string, dir bool) bool {
+	if relativePath == "." {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(relativePath), "/") {
+		if part == ".nt" || part == ".git" {
+			return true
+		}
+	}
+	if CurrentConfig().IgnoreFile.MustExcludeFile(relativePath, dir) {
+		return true
+	}
+	if dir {
+		return false
+	}
+	return !CurrentConfig().ConfigFile.SupportExtension(relativePath) && DetectMediaKind(relativePath) == KindUnknown
+}
+
+// processWatchedFile stages (or lints) a changed file or directory.
+func (r *Repository) processWatchedFile(relativePath string, lintOnly bool) *WatchResult {
+	result := &WatchResult{RelativePath: relativePath}
+
+	relativePaths := []string{relativePath}
+	if DetectMediaKind(relativePath) != KindUnknown {
+		// Medias are processed when adding files referencing them
+		relativePaths, result.Err = r.findFilesReferencingMedia(relativePath)
+		if result.Err != nil || len(relativePaths) == 0 {
+			return result
+		}
+	}
+
+	if lintOnly {
+		result.Lint, result.Err = r.Lint(nil, relativePaths...)
+	} else {
+		result.Summary, result.Err = r.AddWithSummary(relativePaths...)
+	}
+	return result
+}
+
+// findFilesReferencingMedia returns the relative paths of files containing notes referencing a media.
+// Medias are referenced using paths relative to files. The file name is searched instead.
+func (r *Repository) findFilesReferencingMedia(relativePath string) ([]string, error) {
+	rows, err := CurrentDB().Client().Query(`
+		SELECT DISTINCT relative_path
+		FROM note
+		WHERE content_raw LIKE ? ESCAPE '\'
+		ORDER BY relative_path;`, "%"+escapeLike(filepath.Base(relativePath))+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []string
+	for rows.Next() {
+		var noteRelativePath string
+		if err := rows.Scan(&noteRelativePath); err != nil {
+			return nil, err
+		}
+		results = append(results, noteRelativePath)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/julien-sobczak/the-notewriter/internal/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMustIgnoreWatchedPath(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	assert.False(t, mustIgnoreWatchedPath(".", true))
+	assert.False(t, mustIgnoreWatchedPath("go.md", false))
+	assert.False(t, mustIgnoreWatchedPath("medias/go.svg", false))
+	assert.False(t, mustIgnoreWatchedPath("medias", true))
+	assert.True(t, mustIgnoreWatchedPath(".nt", true))
+	assert.True(t, mustIgnoreWatchedPath(".nt/index", false))
+	assert.True(t, mustIgnoreWatchedPath(".git/HEAD", false))
+	assert.True(t, mustIgnoreWatchedPath("go.txt", false))
+	assert.True(t, mustIgnoreWatchedPath("README.md", false)) // .ntignore
+}
+
+func TestProcessWatchedFile(t *testing.T) {
+	SetUpRepositoryFromGoldenDirNamed(t, "TestMinimal")
+
+	result := CurrentRepository().processWatchedFile("go.md", false)
+	require.NoError(t, result.Err)
+	require.NotNil(t, result.Summary)
+	assert.ElementsMatch(t, []string{"go.md", "medias/go.svg"}, result.Summary.Added)
+	// Changes are reported against the last commit
+	require.NoError(t, CurrentDB().Commit("initial commit"))
+
+	// Medias are refreshed through files referencing them
+	paths, err := CurrentRepository().findFilesReferencingMedia("medias/go.svg")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.md"}, paths)
+	MustWriteFile(t, "medias/go.svg", "<svg></svg>")
+	result = CurrentRepository().processWatchedFile("medias/go.svg", false)
+	require.NoError(t, result.Err)
+	require.NotNil(t, result.Summary)
+	assert.Contains(t, result.Summary.Modified, "medias/go.svg")
+	media, err := CurrentRepository().FindMediaByRelativePath("medias/go.svg")
+	require.NoError(t, err)
+	assert.Equal(t, helpers.Hash([]byte("<svg></svg>")), media.Hash)
+
+	// Unreferenced medias are ignored
+	result = CurrentRepository().processWatchedFile("medias/unknown.png", false)
+	require.NoError(t, result.Err)
+	assert.Nil(t, result.Summary)
+
+	// Files are only linted in lint-only mode
+	result = CurrentRepository().processWatchedFile("go.md", true)
+	require.NoError(t, result.Err)
+	assert.Nil(t, result.Summary)
+	require.NotNil(t, result.Lint)
+	assert.Equal(t, 1, result.Lint.AnalyzedFiles)
+}
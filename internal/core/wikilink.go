@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
 	"regexp"
 	"strings"
 
@@ -110,3 +112,127 @@ func ParseWikilinks(text string) []*Wikilink {
 
 	return wikilinks
 }
+
+/* Fix */
+
+// WikilinkFix describes a wikilink rewritten by FixWikilinks.
+type WikilinkFix struct {
+	RelativePath string
+	Line         int
+	Before       string
+	After        string
+}
+
+func (f WikilinkFix) String() string {
+	return fmt.Sprintf("%s:%d: %s => %s", f.RelativePath, f.Line, f.Before, f.After)
+}
+
+// FixWikilinks rewrites wikilinks in all files under the given paths to strip the extensions
+// and to use the casing of the target file (ex: `[[Go.md#Note: Basics]]` => `[[go#Note: Basics]]`).
+// Links to missing or ambiguous files are left untouched. Code blocks are ignored.
+// The list of changes is returned. Files are not written when dryRun is true.
+func (r *Repository) FixWikilinks(dryRun bool, paths ...string) ([]*WikilinkFix, error) {
+	if err := loadSectionsInventory(); err != nil {
+		return nil, err
+	}
+
+	var fixes []*WikilinkFix
+	paths = r.normalizePaths(paths...)
+	err := r.walk(paths, func(path string, stat fs.FileInfo) error {
+		relativePath, err := r.GetFileRelativePath(path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		newContent, fileFixes := fixWikilinksInContent(string(content), relativePath)
+		if len(fileFixes) == 0 {
+			return nil
+		}
+		fixes = append(fixes, fileFixes...)
+
+		if dryRun {
+			return nil
+		}
+		CurrentLogger().Debugf("Fixing wikilinks in %s...", path)
+		return os.WriteFile(path, []byte(newContent), stat.Mode())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fixes, nil
+}
+
+// fixWikilinksInContent rewrites the wikilinks present in the raw content of a file.
+func fixWikilinksInContent(content string, relativePath string) (string, []*WikilinkFix) {
+	var fixes []*WikilinkFix
+
+	lines := strings.Split(content, "\n")
+	// Ignore wikilinks inside code blocks (lines are emptied but preserved)
+	cleanedLines := strings.Split(markdown.CleanCodeBlocks(content), "\n")
+	for i, line := range lines {
+		if cleanedLines[i] == "" {
+			continue
+		}
+		lines[i] = regexWikilink.ReplaceAllStringFunc(line, func(match string) string {
+			wikilink, err := NewWikilink(match)
+			if err != nil || wikilink.Internal() {
+				return match
+			}
+			canonicalPath, ok := canonicalWikilinkPath(wikilink.Path())
+			if !ok {
+				return match
+			}
+			link := canonicalPath
+			if strings.Contains(wikilink.Link, "#") {
+				link += "#" + wikilink.Section()
+			}
+			if link == wikilink.Link {
+				return match
+			}
+			fixedWikilink := Wikilink{Link: link, Text: wikilink.Text}
+			fixes = append(fixes, &WikilinkFix{
+				RelativePath: relativePath,
+				Line:         i + 1,
+				Before:       match,
+				After:        fixedWikilink.String(),
+			})
+			return fixedWikilink.String()
+		})
+	}
+
+	return strings.Join(lines, "\n"), fixes
+}
+
+// canonicalWikilinkPath returns the path of a wikilink without extension
+// and using the casing of the single matching file.
+func canonicalWikilinkPath(path string) (string, bool) {
+	prefix := ""
+	if strings.HasPrefix(path, "/") {
+		prefix = "/"
+	}
+	searchedParts := strings.Split(strings.TrimPrefix(text.TrimExtension(path), "/"), "/")
+
+	var matchingParts []string
+	for inventoryPath := range sectionsInventory {
+		inventoryParts := strings.Split(inventoryPath, "/")
+		if len(inventoryParts) < len(searchedParts) {
+			continue
+		}
+		candidateParts := inventoryParts[len(inventoryParts)-len(searchedParts):]
+		if !strings.EqualFold(strings.Join(candidateParts, "/"), strings.Join(searchedParts, "/")) {
+			continue
+		}
+		if matchingParts != nil {
+			// Ambiguous link
+			return "", false
+		}
+		matchingParts = candidateParts
+	}
+	if matchingParts == nil {
+		return "", false
+	}
+	return prefix + strings.Join(matchingParts, "/"), true
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -133,3 +135,72 @@ func TestParseWikilinks(t *testing.T) {
 	}
 	assert.Equal(t, expected, actual)
 }
+
+func TestFixWikilinks(t *testing.T) {
+	root := SetUpRepositoryFromTempDir(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "projects"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "archives"), 0755))
+
+	MustWriteFile(t, "Go.md", `# Go
+
+## Note: Basics
+
+See [[python.md#Note: Basics|Python]] and [[GO#Note: Basics]].
+Already correct: [[python]], [[#Note: Basics]].
+
+Missing: [[java.md]]. Ambiguous: [[todo.md]].
+
+`+"```md"+`
+[[python.md]]
+`+"```"+`
+`)
+	MustWriteFile(t, "python.md", `# Python
+
+## Note: Basics
+
+See [[projects/TODO.md]].
+`)
+	MustWriteFile(t, "projects/todo.md", "# TODO\n")
+	MustWriteFile(t, "archives/todo.md", "# TODO\n")
+
+	readFile := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		require.NoError(t, err)
+		return string(content)
+	}
+	original := readFile("Go.md")
+
+	// Dry-run
+	fixes, err := CurrentRepository().FixWikilinks(true)
+	require.NoError(t, err)
+	var actual []string
+	for _, fix := range fixes {
+		actual = append(actual, fix.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"Go.md:5: [[python.md#Note: Basics|Python]] => [[python#Note: Basics|Python]]",
+		"Go.md:5: [[GO#Note: Basics]] => [[Go#Note: Basics]]",
+		"python.md:5: [[projects/TODO.md]] => [[projects/todo]]",
+	}, actual)
+	assert.Equal(t, original, readFile("Go.md"))
+
+	// Fix
+	fixes, err = CurrentRepository().FixWikilinks(false, "python.md")
+	require.NoError(t, err)
+	assert.Len(t, fixes, 1)
+	assert.Equal(t, original, readFile("Go.md"))
+	assert.Contains(t, readFile("python.md"), "See [[projects/todo]].")
+
+	fixes, err = CurrentRepository().FixWikilinks(false)
+	require.NoError(t, err)
+	assert.Len(t, fixes, 2)
+	actualContent := readFile("Go.md")
+	assert.Contains(t, actualContent, "See [[python#Note: Basics|Python]] and [[Go#Note: Basics]].")
+	assert.Contains(t, actualContent, "Missing: [[java.md]]. Ambiguous: [[todo.md]].")
+	assert.Contains(t, actualContent, "```md\n[[python.md]]\n```")
+
+	// Nothing left to fix
+	fixes, err = CurrentRepository().FixWikilinks(false)
+	require.NoError(t, err)
+	assert.Empty(t, fixes)
+}
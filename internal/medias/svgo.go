@@ -0,0 +1,85 @@
+package medias
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SvgoConverter optimizes SVG pictures using the tool svgo.
+// Vector pictures are kept in SVG format as they don't need to be resized.
+// Requirements:
+//
+//	npm install -g svgo
+type SvgoConverter struct {
+	exe       string // searched in $PATH when empty
+	listeners []func(cmd string, args ...string)
+}
+
+func NewSvgoConverter() *SvgoConverter {
+	return &SvgoConverter{}
+}
+
+func (c *SvgoConverter) OnPreGeneration(fn func(cmd string, args ...string)) {
+	c.listeners = append(c.listeners, fn)
+}
+
+func (c *SvgoConverter) notifyListeners(cmd string, args ...string) {
+	for _, fn := range c.listeners {
+		fn(cmd, args...)
+	}
+}
+
+// ToAVIF optimizes a SVG picture. The destination file must use the extension .svg.
+// The dimensions are ignored.
+func (c *SvgoConverter) ToAVIF(srcPath string, destPath string, dimensions Dimensions) error {
+	// Check extensions
+	srcExt := strings.ToLower(filepath.Ext(srcPath))
+	if srcExt != ".svg" {
+		return fmt.Errorf("svgo only supports SVG files. Got: %s", srcExt)
+	}
+	destExt := strings.ToLower(filepath.Ext(destPath))
+	if destExt != ".svg" {
+		return fmt.Errorf("target file must used extension .svg. Got: %s", destExt)
+	}
+
+	// Check src file exists
+	_, err := os.Stat(srcPath)
+	if err != nil {
+		return err
+	}
+
+	// The executable is searched only when used so that svgo is not required
+	// when no SVG pictures are present.
+	if c.exe == "" {
+		path, err := exec.LookPath("svgo")
+		if err != nil {
+			return errors.New("executable 'svgo' not found in $PATH")
+		}
+		c.exe = path
+	}
+
+	args := []string{srcPath, "-o", destPath}
+	c.notifyListeners(c.exe, args...)
+	cmd := exec.CommandContext(context.Background(), c.exe, args...)
+
+	// Dump output to troubleshoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s", output)
+	}
+
+	return err
+}
+
+func (c *SvgoConverter) ToMP3(srcPath string, destPath string) error {
+	return errors.New("svgo only supports SVG files")
+}
+
+func (c *SvgoConverter) ToWebM(srcPath string, destPath string) error {
+	return errors.New("svgo only supports SVG files")
+}
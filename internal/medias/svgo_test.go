@@ -0,0 +1,52 @@
+package medias
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSvgoConverter(t *testing.T) {
+
+	t.Run("SVG", func(t *testing.T) {
+		t.Setenv("TEST_BEHAVIOR", "dump_cmd")
+		converter := &SvgoConverter{
+			exe: testExe(t),
+		}
+
+		mediasDir := filepath.Join("testdata", "TestMedias/medias")
+		outputDir := t.TempDir()
+
+		src := filepath.Join(mediasDir, "penguin.svg")
+		dest := filepath.Join(outputDir, "out.svg")
+
+		err := converter.ToAVIF(src, dest, ResizeTo(150))
+		require.NoError(t, err)
+
+		// Check cmd (dimensions are ignored)
+		actual, err := os.ReadFile(dest)
+		require.NoError(t, err)
+		expected := fmt.Sprintf("ffmpeg %s -o %s", src, dest) // dump_cmd always uses the name ffmpeg
+		assert.Equal(t, expected, string(actual))
+	})
+
+	t.Run("Unsupported formats", func(t *testing.T) {
+		converter := &SvgoConverter{
+			exe: testExe(t),
+		}
+
+		mediasDir := filepath.Join("testdata", "TestMedias/medias")
+		outputDir := t.TempDir()
+
+		err := converter.ToAVIF(filepath.Join(mediasDir, "tree-landscape-large.jpg"), filepath.Join(outputDir, "out.svg"), OriginalSize())
+		assert.ErrorContains(t, err, "only supports SVG files")
+		err = converter.ToAVIF(filepath.Join(mediasDir, "penguin.svg"), filepath.Join(outputDir, "out.avif"), OriginalSize())
+		assert.ErrorContains(t, err, "must used extension .svg")
+		err = converter.ToMP3(filepath.Join(mediasDir, "rain.aac"), filepath.Join(outputDir, "out.mp3"))
+		assert.ErrorContains(t, err, "only supports SVG files")
+	})
+}
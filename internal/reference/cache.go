@@ -0,0 +1,161 @@
+package reference
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+)
+
+// DefaultCacheTTL is the duration search results are kept when no TTL is configured.
+const DefaultCacheTTL = 30 * 24 * time.Hour
+
+// CachedManager decorates a Manager to persist search results as JSON files.
+// Repeated lookups are served from the cache, and stale entries are still
+// used when the underlying manager is not reachable (ex: offline).
+type CachedManager struct {
+	Manager Manager
+	// Kind identifies the manager in cache keys (ex: "wikipedia")
+	Kind string
+	// Dir is the directory where cache files are stored (ex: ".nt/cache/references")
+	Dir string
+	// TTL is the maximum age of a cache entry before a refresh is attempted
+	TTL time.Duration
+	// Refresh ignores existing cache entries (they are still updated)
+	Refresh bool
+}
+
+// NewCachedManager wraps the given manager to cache its results.
+func NewCachedManager(manager Manager, kind, dir string, ttl time.Duration) *CachedManager {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachedManager{
+		Manager: manager,
+		Kind:    kind,
+		Dir:     dir,
+		TTL:     ttl,
+	}
+}
+
+// CachedResult is a result restored from the cache.
+type CachedResult struct {
+	Desc  string         `json:"description"`
+	Attrs map[string]any `json:"attributes"`
+}
+
+func (r *CachedResult) Description() string {
+	return r.Desc
+}
+
+func (r *CachedResult) Attributes() map[string]any {
+	return r.Attrs
+}
+
+type cacheEntry struct {
+	Kind      string          `json:"kind"`
+	Query     string          `json:"query"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Results   []*CachedResult `json:"results"`
+}
+
+func (m *CachedManager) Ready() (bool, error) {
+	ready, err := m.Manager.Ready()
+	if ready {
+		return true, nil
+	}
+	// The cache can still answer past queries
+	if _, statErr := os.Stat(m.Dir); statErr == nil {
+		return true, nil
+	}
+	return ready, err
+}
+
+func (m *CachedManager) Search(query string) ([]Result, error) {
+	entry, err := m.load(query)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && !m.Refresh && clock.Now().Sub(entry.CreatedAt) < m.TTL {
+		return entry.toResults(), nil
+	}
+
+	results, err := m.Manager.Search(query)
+	if err != nil {
+		if entry != nil {
+			// Better stale results than no result at all
+			return entry.toResults(), nil
+		}
+		return nil, err
+	}
+
+	if err := m.save(query, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Key returns the cache key for a query.
+func (m *CachedManager) Key(query string) string {
+	h := sha1.New()
+	h.Write([]byte(m.Kind))
+	h.Write([]byte{0})
+	h.Write([]byte(query))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (m *CachedManager) path(query string) string {
+	return filepath.Join(m.Dir, m.Key(query)+".json")
+}
+
+func (m *CachedManager) load(query string) (*cacheEntry, error) {
+	data, err := os.ReadFile(m.path(query))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// Ignore corrupted entries, they will be overwritten
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (m *CachedManager) save(query string, results []Result) error {
+	entry := cacheEntry{
+		Kind:      m.Kind,
+		Query:     query,
+		CreatedAt: clock.Now(),
+	}
+	for _, result := range results {
+		entry.Results = append(entry.Results, &CachedResult{
+			Desc:  result.Description(),
+			Attrs: result.Attributes(),
+		})
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, os.ModePerm); err != nil {
+		return fmt.Errorf("unable to create cache directory %q: %w", m.Dir, err)
+	}
+	return os.WriteFile(m.path(query), data, 0644)
+}
+
+func (e *cacheEntry) toResults() []Result {
+	var results []Result
+	for _, result := range e.Results {
+		results = append(results, result)
+	}
+	return results
+}
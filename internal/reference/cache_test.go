@@ -0,0 +1,116 @@
+package reference_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/julien-sobczak/the-notewriter/internal/reference"
+	"github.com/julien-sobczak/the-notewriter/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// CountingManager counts calls and fails when offline.
+type CountingManager struct {
+	DummyManager
+	Calls   int
+	Offline bool
+}
+
+func (m *CountingManager) Ready() (bool, error) {
+	if m.Offline {
+		return false, errors.New("offline")
+	}
+	return true, nil
+}
+
+func (m *CountingManager) Search(query string) ([]reference.Result, error) {
+	m.Calls++
+	if m.Offline {
+		return nil, errors.New("offline")
+	}
+	return m.DummyManager.Search(query)
+}
+
+func TestCachedManager(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache", "references")
+
+	t.Run("Repeated lookups", func(t *testing.T) {
+		manager := &CountingManager{}
+		cached := reference.NewCachedManager(manager, "dummy", dir, time.Hour)
+
+		results, err := cached.Search("repeated")
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		results, err = cached.Search("repeated")
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, 1, manager.Calls)
+
+		assert.Equal(t, "1: Item 1", results[0].Description())
+		assert.Equal(t, "Bob", results[0].Attributes()["author"])
+		assert.FileExists(t, filepath.Join(dir, cached.Key("repeated")+".json"))
+	})
+
+	t.Run("Key depends on kind", func(t *testing.T) {
+		a := reference.NewCachedManager(&CountingManager{}, "wikipedia", dir, 0)
+		b := reference.NewCachedManager(&CountingManager{}, "zotero", dir, 0)
+		assert.NotEqual(t, a.Key("query"), b.Key("query"))
+		assert.Equal(t, reference.DefaultCacheTTL, a.TTL)
+	})
+
+	t.Run("Expired entries", func(t *testing.T) {
+		now := clock.Freeze()
+		defer clock.Unfreeze()
+
+		manager := &CountingManager{}
+		cached := reference.NewCachedManager(manager, "dummy", dir, time.Hour)
+		_, err := cached.Search("expired")
+		require.NoError(t, err)
+
+		// Still fresh
+		clock.FreezeAt(now.Add(59 * time.Minute))
+		_, err = cached.Search("expired")
+		require.NoError(t, err)
+		assert.Equal(t, 1, manager.Calls)
+
+		// Expire the entry
+		clock.FreezeAt(now.Add(61 * time.Minute))
+		_, err = cached.Search("expired")
+		require.NoError(t, err)
+		assert.Equal(t, 2, manager.Calls)
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		manager := &CountingManager{}
+		cached := reference.NewCachedManager(manager, "dummy", dir, time.Nanosecond)
+		_, err := cached.Search("offline")
+		require.NoError(t, err)
+
+		manager.Offline = true
+		ready, err := cached.Ready()
+		require.NoError(t, err)
+		assert.True(t, ready)
+		// Stale results are returned
+		results, err := cached.Search("offline")
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+
+		// Unknown queries still fail
+		_, err = cached.Search("unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("No cache", func(t *testing.T) {
+		manager := &CountingManager{}
+		cached := reference.NewCachedManager(manager, "dummy", dir, time.Hour)
+		cached.Refresh = true
+		_, err := cached.Search("refresh")
+		require.NoError(t, err)
+		_, err = cached.Search("refresh")
+		require.NoError(t, err)
+		assert.Equal(t, 2, manager.Calls)
+	})
+}
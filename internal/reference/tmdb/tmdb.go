@@ -0,0 +1,182 @@
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/julien-sobczak/the-notewriter/internal/reference"
+)
+
+const (
+	// How many movies to retrieve details for in maximum
+	maxResults = 5
+)
+
+// Module search structure
+
+type SearchResponse struct {
+	Page    int             `json:"page"`
+	Results []*SearchResult `json:"results"`
+}
+type SearchResult struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	ReleaseDate string `json:"release_date"`
+}
+
+// Module movie structure
+
+type Movie struct {
+	ID            int      `json:"id"`
+	IMDbID        string   `json:"imdb_id"`
+	Title         string   `json:"title"`
+	OriginalTitle string   `json:"original_title"`
+	Overview      string   `json:"overview"`
+	ReleaseDate   string   `json:"release_date"`
+	Runtime       int      `json:"runtime"`
+	Genres        []*Genre `json:"genres"`
+	Credits       Credits  `json:"credits"`
+}
+type Genre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+type Credits struct {
+	Cast []*CastMember `json:"cast"`
+	Crew []*CrewMember `json:"crew"`
+}
+type CastMember struct {
+	Name      string `json:"name"`
+	Character string `json:"character"`
+	Order     int    `json:"order"`
+}
+type CrewMember struct {
+	Name string `json:"name"`
+	Job  string `json:"job"`
+}
+
+// Manager provides reference management using The Movie Database (TMDb) API.
+type Manager struct {
+	// Overriden in tests to use a mock server
+	BaseURL string
+	// See https://developer.themoviedb.org/docs/getting-started
+	APIKey string
+}
+
+type Result struct {
+	Movie *Movie
+}
+
+func (r *Result) Description() string {
+	if year := r.Year(); year != "" {
+		return fmt.Sprintf("%s (%s)", r.Movie.Title, year)
+	}
+	return r.Movie.Title
+}
+
+// Year returns the release year of the movie.
+func (r *Result) Year() string {
+	year, _, _ := strings.Cut(r.Movie.ReleaseDate, "-") // Ex: "1999-10-15"
+	return year
+}
+
+func (r *Result) Attributes() map[string]any {
+	var genres []string
+	for _, genre := range r.Movie.Genres {
+		genres = append(genres, genre.Name)
+	}
+	var directors []string
+	for _, member := range r.Movie.Credits.Crew {
+		if member.Job == "Director" {
+			directors = append(directors, member.Name)
+		}
+	}
+	var cast []string
+	for _, member := range r.Movie.Credits.Cast {
+		cast = append(cast, member.Name)
+	}
+
+	results := map[string]any{
+		"id":            r.Movie.ID,
+		"imdbId":        r.Movie.IMDbID,
+		"title":         r.Movie.Title,
+		"originalTitle": r.Movie.OriginalTitle,
+		"overview":      r.Movie.Overview,
+		"releaseDate":   r.Movie.ReleaseDate,
+		"year":          r.Year(),
+		"runtime":       r.Movie.Runtime,
+		"genres":        genres,
+		"directors":     directors,
+		"cast":          cast,
+		"url":           fmt.Sprintf("https://www.themoviedb.org/movie/%d", r.Movie.ID),
+	}
+	if len(directors) > 0 {
+		results["director"] = directors[0]
+	}
+	return results
+}
+
+func NewManager(apiKey string) *Manager {
+	return &Manager{
+		BaseURL: "https://api.themoviedb.org/3",
+		APIKey:  apiKey,
+	}
+}
+
+/* Reference interface */
+
+func (m *Manager) Ready() (bool, error) {
+	if m.APIKey == "" {
+		return false, fmt.Errorf("missing API key for TMDb")
+	}
+	// Nothing to start locally
+	return true, nil
+}
+
+func (m *Manager) Search(query string) ([]reference.Result, error) {
+	// Ex: https://api.themoviedb.org/3/search/movie?query=fight+club
+	var searchResponse SearchResponse
+	requestURL := fmt.Sprintf("%s/search/movie?query=%s&api_key=%s", m.BaseURL, url.QueryEscape(query), url.QueryEscape(m.APIKey))
+	if err := m.get(requestURL, &searchResponse); err != nil {
+		return nil, err
+	}
+
+	var results []reference.Result
+	for i, searchResult := range searchResponse.Results {
+		if i == maxResults {
+			// Limit the number of results to limit HTTP queries
+			break
+		}
+
+		// Retrieve the details to get the genres and the director(s)
+		// Ex: https://api.themoviedb.org/3/movie/550?append_to_response=credits
+		var movie Movie
+		requestURL := fmt.Sprintf("%s/movie/%d?append_to_response=credits&api_key=%s", m.BaseURL, searchResult.ID, url.QueryEscape(m.APIKey))
+		if err := m.get(requestURL, &movie); err != nil {
+			return nil, err
+		}
+		results = append(results, &Result{
+			Movie: &movie,
+		})
+	}
+
+	return results, nil
+}
+
+func (m *Manager) get(requestURL string, target any) error {
+	res, err := http.Get(requestURL)
+	if err != nil {
+		return fmt.Errorf("error making HTTP request: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("wrong status code for HTTP request: %v", res.StatusCode)
+	}
+	if err := json.NewDecoder(res.Body).Decode(target); err != nil {
+		return fmt.Errorf("error unmarshalling JSON response: %v", err)
+	}
+	return nil
+}
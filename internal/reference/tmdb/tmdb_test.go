@@ -0,0 +1,90 @@
+package tmdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julien-sobczak/the-notewriter/internal/reference"
+	"github.com/julien-sobczak/the-notewriter/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	// Setup mock server
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Ex: https://api.themoviedb.org/3/search/movie?query=fight+club
+		if r.URL.Path == "/search/movie" && r.URL.Query().Get("query") == "fight club" {
+			responseBody := testutil.GoldenFileNamed(t, "search-fight-club.json")
+			fmt.Fprintln(w, string(responseBody))
+			return
+		}
+		// Ex: https://api.themoviedb.org/3/movie/550?append_to_response=credits
+		if r.URL.Path == "/movie/550" && r.URL.Query().Get("append_to_response") == "credits" {
+			responseBody := testutil.GoldenFileNamed(t, "movie-550.json")
+			fmt.Fprintln(w, string(responseBody))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	manager := NewManager("secret")
+	manager.BaseURL = ts.URL
+
+	ready, err := manager.Ready()
+	require.NoError(t, err)
+	require.True(t, ready)
+
+	results, err := manager.Search("fight club")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	result := results[0]
+	assert.Equal(t, "Fight Club (1999)", result.Description())
+	attributes := result.Attributes()
+	assert.Equal(t, "Fight Club", attributes["title"])
+	assert.Equal(t, "1999", attributes["year"])
+	assert.Equal(t, "David Fincher", attributes["director"])
+	assert.Equal(t, []string{"Drama", "Thriller", "Comedy"}, attributes["genres"])
+	assert.Equal(t, []string{"Edward Norton", "Brad Pitt"}, attributes["cast"])
+	assert.Equal(t, "tt0137523", attributes["imdbId"])
+
+	// Check generated reference
+	text, err := reference.EvaluateTemplate(`---
+title: "{{index . "title"}}"
+year: {{index . "year"}}
+director: "{{index . "director"}}"
+genres: [{{index . "genres" | join ", "}}]
+---
+
+# {{index . "title"}}
+`, result)
+	require.NoError(t, err)
+	assert.Equal(t, `---
+title: "Fight Club"
+year: 1999
+director: "David Fincher"
+genres: [Drama, Thriller, Comedy]
+---
+
+# Fight Club
+`, text)
+
+	// Check invalid API key
+	manager = NewManager("invalid")
+	manager.BaseURL = ts.URL
+	_, err = manager.Search("fight club")
+	assert.ErrorContains(t, err, "wrong status code")
+
+	// Check missing API key
+	ready, err = NewManager("").Ready()
+	assert.False(t, ready)
+	assert.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package clock
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/julien-sobczak/the-notewriter/pkg/resync"
@@ -69,3 +72,22 @@ func Unfreeze() {
 	clockSingleton = nil
 	clockOnce.Reset()
 }
+
+// ParseDuration is the same as time.ParseDuration but also accepts
+// a number of days (ex: "2d") or weeks (ex: "1w").
+func ParseDuration(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if value, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
@@ -49,6 +49,30 @@ func TestSwitchClock(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), clock.Now(), 1*time.Second)
 }
 
+func TestParseDuration(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"2d", 48 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"90m", 90 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := clock.ParseDuration(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	_, err := clock.ParseDuration("twod")
+	assert.Error(t, err)
+	_, err = clock.ParseDuration("2 days")
+	assert.Error(t, err)
+}
+
 func ExampleClock() {
 	point := time.Date(2023, 01, 01, 14, 00, 00, 00, time.UTC)
 	clock.FreezeAt(point)
@@ -0,0 +1,59 @@
+package filesystem
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index")
+
+	writeString := func(content string) func(w io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := io.WriteString(w, content)
+			return err
+		}
+	}
+
+	// Create a new file
+	err := WriteFileAtomic(path, writeString("v1"))
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(content))
+
+	// Replace the file
+	err = WriteFileAtomic(path, writeString("v2"))
+	require.NoError(t, err)
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(content))
+
+	// Simulate a partial write
+	err = WriteFileAtomic(path, func(w io.Writer) error {
+		if _, err := io.WriteString(w, "v3 (trunc"); err != nil {
+			return err
+		}
+		return errors.New("disk full")
+	})
+	require.EqualError(t, err, "disk full")
+	// The previous file must survive
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(content))
+	// No temporary file must remain
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "index", entries[0].Name())
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), stat.Mode().Perm())
+}
@@ -2,6 +2,7 @@ package markdown
 
 import (
 	"bytes"
+	"regexp"
 	"strings"
 
 	"github.com/julien-sobczak/the-notewriter/pkg/text"
@@ -232,6 +233,104 @@ func CleanCodeBlocks(md string) string {
 	return strings.Join(newLines, "\n")
 }
 
+// Normalize removes formatting-only differences (ex: reflowed paragraphs, reindented lists, list markers, heading spacing)
+// to compare two documents. Fenced code blocks are preserved. The result is not meant to be displayed.
+func Normalize(md string) string {
+	reListMarker := regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+	reHeading := regexp.MustCompile(`^(#+)\s*`)
+	reSpaces := regexp.MustCompile(`\s+`)
+
+	var blocks []string
+	var block []string
+	flush := func() {
+		if len(block) > 0 {
+			blocks = append(blocks, strings.Join(block, " "))
+			block = nil
+		}
+	}
+
+	insideCodeBlock := false
+	for _, line := range strings.Split(AlignHeadings(md), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			flush()
+			insideCodeBlock = !insideCodeBlock
+			blocks = append(blocks, strings.TrimSpace(line))
+			continue
+		}
+		if insideCodeBlock {
+			blocks = append(blocks, line)
+			continue
+		}
+
+		line = reSpaces.ReplaceAllString(strings.TrimSpace(line), " ")
+		if line == "" {
+			// Paragraph separator
+			flush()
+			continue
+		}
+		if reListMarker.MatchString(line) {
+			// Every list item starts a new block
+			flush()
+			line = reListMarker.ReplaceAllString(line, "- ")
+		} else if strings.HasPrefix(line, "#") {
+			flush()
+			blocks = append(blocks, reHeading.ReplaceAllString(line, "$1 "))
+			continue
+		}
+		block = append(block, line)
+	}
+	flush()
+
+	return strings.Join(blocks, "\n")
+}
+
+// ExtractCodeBlocks returns the content of fenced code blocks.
+func ExtractCodeBlocks(md string) []string {
+	var codeBlocks []string
+
+	var codeBlock []string
+	insideCodeBlock := false
+	for _, line := range strings.Split(md, "\n") {
+		if strings.HasPrefix(line, "```") {
+			if insideCodeBlock {
+				codeBlocks = append(codeBlocks, strings.Join(codeBlock, "\n"))
+				codeBlock = nil
+			}
+			insideCodeBlock = !insideCodeBlock
+			continue
+		}
+		if insideCodeBlock {
+			codeBlock = append(codeBlock, line)
+		}
+	}
+	return codeBlocks
+}
+
+// CountTasks returns the number of completed tasks and the total number of tasks in task lists.
+func CountTasks(md string) (int, int) {
+	reTask := regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s`)
+	done := 0
+	total := 0
+	for _, line := range strings.Split(CleanCodeBlocks(md), "\n") {
+		m := reTask.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		total++
+		if m[1] != " " {
+			done++
+		}
+	}
+	return done, total
+}
+
+// ParseAttribution splits the attribution of a quote into an author and an optional source.
+// Ex: "J.R.R. Tolkien, _The Fellowship of the Ring_" => "J.R.R. Tolkien", "_The Fellowship of the Ring_"
+func ParseAttribution(attribution string) (string, string) {
+	author, source, _ := strings.Cut(attribution, ",")
+	return strings.TrimSpace(author), strings.TrimSpace(source)
+}
+
 // ExtractQuote extracts a quote from a note content (support basic and sugar syntax)
 func ExtractQuote(md string) (string, string) {
 	var quote bytes.Buffer
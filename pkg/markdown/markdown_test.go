@@ -239,6 +239,131 @@ func TestCleanCodeBlocks(t *testing.T) {
 	}
 }
 
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		md1  string
+		md2  string
+		same bool
+	}{
+		{
+			name: "Reflowed paragraph",
+			md1:  "Go was designed at Google\nin 2007.\n",
+			md2:  "Go  was designed at Google in 2007.",
+			same: true,
+		},
+		{
+			name: "Reindented list",
+			md1:  "* Item 1\n* Item 2\n    + Item 2.1\n",
+			md2:  "- Item 1\n- Item 2\n  - Item 2.1",
+			same: true,
+		},
+		{
+			name: "Heading spacing",
+			md1:  "#### Title\n\nText",
+			md2:  "##   Title\n\n\nText",
+			same: true,
+		},
+		{
+			name: "Different text",
+			md1:  "Go was designed at Google in 2007.",
+			md2:  "Go was designed at Google in 2009.",
+			same: false,
+		},
+		{
+			name: "Split paragraph",
+			md1:  "Go was designed\nat Google.",
+			md2:  "Go was designed\n\nat Google.",
+			same: false,
+		},
+		{
+			name: "Code block",
+			md1:  "```go\nfunc main() {\n  fmt.Println()\n}\n```",
+			md2:  "```go\nfunc main() {\n    fmt.Println()\n}\n```",
+			same: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.same {
+				assert.Equal(t, markdown.Normalize(tt.md1), markdown.Normalize(tt.md2))
+			} else {
+				assert.NotEqual(t, markdown.Normalize(tt.md1), markdown.Normalize(tt.md2))
+			}
+		})
+	}
+}
+
+func TestCountTasks(t *testing.T) {
+	tests := []struct {
+		name          string
+		md            string // input
+		expectedDone  int
+		expectedTotal int
+	}{
+		{
+			name:          "No tasks",
+			md:            "* Passport\n* Charger\n",
+			expectedDone:  0,
+			expectedTotal: 0,
+		},
+		{
+			name:          "Tasks",
+			md:            "- [x] Passport\n- [ ] Charger\n* [X] Tickets\n  + [ ] Adapter\n",
+			expectedDone:  2,
+			expectedTotal: 4,
+		},
+		{
+			name:          "Tasks in code blocks",
+			md:            "- [ ] Passport\n\n```md\n- [x] Example\n```\n",
+			expectedDone:  0,
+			expectedTotal: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, total := markdown.CountTasks(tt.md)
+			assert.Equal(t, tt.expectedDone, done)
+			assert.Equal(t, tt.expectedTotal, total)
+		})
+	}
+}
+
+func TestExtractCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string   // input
+		expected []string // output
+	}{
+		{
+			name:     "No code blocks",
+			md:       "# Hello\n\nWorld\n",
+			expected: nil,
+		},
+		{
+			name:     "Single code block",
+			md:       "Run:\n\n```shell\n$ nt add .\n$ nt commit\n```\n",
+			expected: []string{"$ nt add .\n$ nt commit"},
+		},
+		{
+			name:     "Multiple code blocks",
+			md:       "```go\nfmt.Println()\n```\n\nOr:\n\n```\nprint()\n```\n",
+			expected: []string{"fmt.Println()", "print()"},
+		},
+		{
+			name:     "Unterminated code block",
+			md:       "```go\nfmt.Println()\n",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := markdown.ExtractCodeBlocks(tt.md)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
 func TestExtractQuote(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -281,6 +406,47 @@ func TestExtractQuote(t *testing.T) {
 
 }
 
+func TestParseAttribution(t *testing.T) {
+	tests := []struct {
+		name        string
+		attribution string // input
+		author      string // output
+		source      string // output
+	}{
+		{
+			name:        "Empty",
+			attribution: "",
+			author:      "",
+			source:      "",
+		},
+		{
+			name:        "Author only",
+			attribution: "Walt Disney",
+			author:      "Walt Disney",
+			source:      "",
+		},
+		{
+			name:        "Author and source",
+			attribution: "J.R.R. Tolkien, _The Fellowship of the Ring_",
+			author:      "J.R.R. Tolkien",
+			source:      "_The Fellowship of the Ring_",
+		},
+		{
+			name:        "Source with comma",
+			attribution: "Antoine de Saint-Exupéry, _Terre des hommes_, 1939",
+			author:      "Antoine de Saint-Exupéry",
+			source:      "_Terre des hommes_, 1939",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			author, source := markdown.ParseAttribution(tt.attribution)
+			assert.Equal(t, tt.author, author)
+			assert.Equal(t, tt.source, source)
+		})
+	}
+}
+
 func TestStripTopHeading(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -110,6 +110,39 @@ func ToText(md string) string {
 	return strings.TrimSpace(txt)
 }
 
+// Excerpt returns a plain text preview of at most maxChars characters (ellipsis excluded).
+// Code blocks and images are skipped. The text is cut on a word boundary when possible.
+func Excerpt(md string, maxChars int) string {
+	reImage := regexp.MustCompile(`!\[.*?\]\(.*?\)`)
+	reUnderline := regexp.MustCompile(`^[=-]+$`)
+
+	md = CleanCodeBlocks(md)
+	md = reImage.ReplaceAllString(md, "")
+
+	var words []string
+	for _, line := range strings.Split(ToText(md), "\n") {
+		if reUnderline.MatchString(strings.TrimSpace(line)) {
+			// Heading underline
+			continue
+		}
+		words = append(words, strings.Fields(line)...)
+	}
+	txt := strings.Join(words, " ")
+
+	runes := []rune(txt)
+	if len(runes) <= maxChars {
+		return txt
+	}
+	excerpt := string(runes[:maxChars])
+	if runes[maxChars] != ' ' {
+		// Do not cut in the middle of a word
+		if i := strings.LastIndex(excerpt, " "); i > 0 {
+			excerpt = excerpt[:i]
+		}
+	}
+	return strings.TrimRight(excerpt, " ,;:.") + "…"
+}
+
 // StripEmphasis remove Markdown emphasis characters.
 func StripEmphasis(text string) string {
 	reBoldAsterisks := regexp.MustCompile(`\*\*(.*?)\*\*`)
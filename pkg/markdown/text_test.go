@@ -91,6 +91,47 @@ func TestStripEmphasis(t *testing.T) {
 	}
 }
 
+func TestExcerpt(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		maxChars int
+		expected string
+	}{
+		{
+			name:     "Short text",
+			input:    "Go is **simple**.",
+			maxChars: 20,
+			expected: "Go is simple.",
+		},
+		{
+			name:     "Word boundary",
+			input:    "Go was designed at Google in 2007 to improve programming productivity.",
+			maxChars: 25,
+			expected: "Go was designed at Google…",
+		},
+		{
+			name:     "Punctuation",
+			input:    "Go was designed at Google, in 2007.",
+			maxChars: 26,
+			expected: "Go was designed at Google…",
+		},
+		{
+			name:     "Code blocks and images",
+			input:    "# Hello\n\n![Logo](oid:4044044044044044044044044044044044044040)\n\n```go\nfmt.Println(\"Hello\")\n```\n\nSee [the doc](https://go.dev).",
+			maxChars: 100,
+			expected: "Hello See the doc.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := markdown.Excerpt(tt.input, tt.maxChars)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
 func TestSlug(t *testing.T) {
 	var tests = []struct {
 		name     string   // name
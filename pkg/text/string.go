@@ -9,9 +9,13 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 // ExtractLines extract the given lines (1-based indices).
@@ -166,3 +170,79 @@ func ToBookTitle(text string) string {
 	return title
 
 }
+
+// Fold returns a canonical form ignoring case and diacritics (ex: "Élan" => "elan").
+func Fold(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		result = text
+	}
+	return strings.ToLower(result)
+}
+
+// CountWords returns the number of words in a plain text.
+// Tokens without any letter or digit (ex: "-", "→") are not words.
+func CountWords(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		if strings.IndexFunc(field, func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsNumber(r)
+		}) >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// FuzzyMatch reports if all characters of pattern appear in order in text, ignoring case and diacritics.
+// The score is higher when matched characters are consecutive or start words (ex: "gh" in "Go History").
+func FuzzyMatch(pattern, text string) (int, bool) {
+	patternRunes := []rune(Fold(pattern))
+	textRunes := []rune(Fold(text))
+	if len(patternRunes) == 0 {
+		return 0, true
+	}
+
+	// Try every occurrence of the first character to find the best match
+	bestScore := 0
+	matched := false
+	for start, r := range textRunes {
+		if r != patternRunes[0] {
+			continue
+		}
+		score, ok := fuzzyMatchFrom(patternRunes, textRunes, start)
+		if ok && (!matched || score > bestScore) {
+			bestScore = score
+			matched = true
+		}
+	}
+	return bestScore, matched
+}
+
+// fuzzyMatchFrom matches greedily the pattern in text starting at the given index.
+func fuzzyMatchFrom(patternRunes, textRunes []rune, start int) (int, bool) {
+	score := 0
+	lastMatch := -1
+	i := 0
+	for j := start; j < len(textRunes) && i < len(patternRunes); j++ {
+		if textRunes[j] != patternRunes[i] {
+			continue
+		}
+		score++
+		if lastMatch >= 0 && lastMatch == j-1 {
+			// Consecutive characters
+			score += 5
+		} else if lastMatch >= 0 {
+			// Penalize gaps (limited to not favor short texts too much)
+			score -= min(j-lastMatch-1, 3)
+		}
+		if j == 0 || !unicode.IsLetter(textRunes[j-1]) && !unicode.IsDigit(textRunes[j-1]) {
+			// Start of a word
+			score += 3
+		}
+		lastMatch = j
+		i++
+	}
+	return score, i == len(patternRunes)
+}
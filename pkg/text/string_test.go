@@ -328,3 +328,70 @@ func TestBookTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"golang", "golang"},
+		{"GoLang", "golang"},
+		{"Élan", "elan"},
+		{"crème-brûlée", "creme-brulee"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, text.Fold(tt.input))
+		})
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"", 0},
+		{"  \n ", 0},
+		{"Go is expressive", 3},
+		{"Go\nis\texpressive, concise.", 4},
+		{"Don't - repeat → yourself!", 3},
+		{"Released in 2009", 3},
+		{"L'élan vital", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, text.CountWords(tt.input))
+		})
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		matched bool
+	}{
+		{"empty pattern", "", "Go History", true},
+		{"subsequence", "ghst", "Go History", true},
+		{"case and diacritics", "ELAN", "Élan vital", true},
+		{"wrong order", "hg", "Go History", false},
+		{"missing character", "gox", "Go History", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, matched := text.FuzzyMatch(tt.pattern, tt.text)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+
+	// Consecutive characters and word starts score higher
+	consecutive, _ := text.FuzzyMatch("hist", "Go History")
+	scattered, _ := text.FuzzyMatch("hist", "Go hexadecimal is scattered")
+	assert.Greater(t, consecutive, scattered)
+	wordStart, _ := text.FuzzyMatch("gh", "Go History")
+	middle, _ := text.FuzzyMatch("gh", "Go uphill")
+	assert.Greater(t, wordStart, middle)
+}
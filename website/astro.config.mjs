@@ -64,6 +64,35 @@ export default defineConfig({
 								{ label: "nt gc", link: '/reference/commands/nt-gc' },
 								{ label: "nt lint", link: '/reference/commands/nt-lint' },
 								{ label: "nt cat-file", link: '/reference/commands/nt-cat-file' },
+								{ label: "nt stats", link: '/reference/commands/nt-stats' },
+								{ label: "nt new", link: '/reference/commands/nt-new' },
+								{ label: "nt restore", link: '/reference/commands/nt-restore' },
+								{ label: "nt export-json", link: '/reference/commands/nt-export-json' },
+								{ label: "nt snippets", link: '/reference/commands/nt-snippets' },
+								{ label: "nt todos", link: '/reference/commands/nt-todos' },
+								{ label: "nt config", link: '/reference/commands/nt-config' },
+								{ label: "nt search", link: '/reference/commands/nt-search' },
+								{ label: "nt watch", link: '/reference/commands/nt-watch' },
+								{ label: "nt show", link: '/reference/commands/nt-show' },
+								{ label: "nt open", link: '/reference/commands/nt-open' },
+								{ label: "nt verify-remote", link: '/reference/commands/nt-verify-remote' },
+								{ label: "nt log", link: '/reference/commands/nt-log' },
+								{ label: "nt doctor", link: '/reference/commands/nt-doctor' },
+								{ label: "nt related", link: '/reference/commands/nt-related' },
+								{ label: "nt pack", link: '/reference/commands/nt-pack' },
+								{ label: "nt tag", link: '/reference/commands/nt-tag' },
+								{ label: "nt merge", link: '/reference/commands/nt-merge' },
+								{ label: "nt reindex", link: '/reference/commands/nt-reindex' },
+								{ label: "nt rm", link: '/reference/commands/nt-rm' },
+								{ label: "nt deck", link: '/reference/commands/nt-deck' },
+								{ label: "nt recover", link: '/reference/commands/nt-recover' },
+								{ label: "nt export-markdown", link: '/reference/commands/nt-export-markdown' },
+								{ label: "nt remind", link: '/reference/commands/nt-remind' },
+								{ label: "nt quote", link: '/reference/commands/nt-quote' },
+								{ label: "nt golinks", link: '/reference/commands/nt-golinks' },
+								{ label: "nt outline", link: '/reference/commands/nt-outline' },
+								{ label: "nt fix-links", link: '/reference/commands/nt-fix-links' },
+								{ label: "nt notes", link: '/reference/commands/nt-notes' },
 							],
 						}
 					]